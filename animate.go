@@ -0,0 +1,120 @@
+package cover
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"sort"
+)
+
+// State of the matrix after a cover or an uncover of the search.
+type Frame struct {
+	Event string `json:"event"`
+	Col   string `json:"col"`
+	Depth int    `json:"depth"`
+	// columns and row indexes still in the matrix, in the order built
+	Cols []string `json:"cols"`
+	Rows []int    `json:"rows"`
+}
+
+// Frames showing the columns and rows disappearing and coming back during a
+// search, e.g. to illustrate the dancing links in documentation. A frame is
+// recorded per event, so it is meant for small matrices and short solves.
+type Animation struct {
+	Headers []string `json:"headers"`
+	// column indexes of each row, by row index
+	Rows   [][]int `json:"rows"`
+	Frames []Frame `json:"frames"`
+	// columns and rows currently in the matrix
+	cols map[string]bool
+	rows map[int]bool
+}
+
+// Starts recording an animation of the following searches, using a hook.
+func (m *SparseMatrix) Animate() *Animation {
+	a := &Animation{cols: map[string]bool{}, rows: map[int]bool{}}
+	index := map[*Node]int{}
+	root := m.Root()
+	for col := root.Right; col != root; col = col.Right {
+		index[col] = len(a.Headers)
+		a.Headers = append(a.Headers, col.Name)
+		a.cols[col.Name] = true
+	}
+	for col := root.Right; col != root; col = col.Right {
+		for _, r := range col.ColNodes() {
+			for len(a.Rows) <= r.Row {
+				a.Rows = append(a.Rows, nil)
+			}
+			a.Rows[r.Row] = append(a.Rows[r.Row], index[col])
+			a.rows[r.Row] = true
+		}
+	}
+	for _, r := range a.Rows {
+		sort.Ints(r)
+	}
+	m.AddHook(a.record)
+	return a
+}
+
+// Replays the event on the active columns and rows. A column keeps the list
+// of its rows while covered, which are the rows the event hides or restores.
+func (a *Animation) record(e Event, col *Node, depth int) {
+	active := e == UncoverEvent
+	a.cols[col.Name] = active
+	for _, r := range col.ColNodes() {
+		a.rows[r.Row] = active
+	}
+	f := Frame{Event: e.String(), Col: col.Name, Depth: depth, Cols: []string{}, Rows: []int{}}
+	for _, name := range a.Headers {
+		if a.cols[name] {
+			f.Cols = append(f.Cols, name)
+		}
+	}
+	for i := range a.Rows {
+		if a.rows[i] {
+			f.Rows = append(f.Rows, i)
+		}
+	}
+	a.Frames = append(a.Frames, f)
+}
+
+// Writes the layout of the matrix and the frames as JSON.
+func (a *Animation) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(a)
+}
+
+// Writes the i-th frame as an SVG picture of the matrix: the cells of the
+// active rows and columns are dark, the ones hidden by the search are light.
+func (a *Animation) WriteSVG(w io.Writer, i int) error {
+	const size, top = 20, 30
+	f := a.Frames[i]
+	cols, rows := map[string]bool{}, map[int]bool{}
+	for _, name := range f.Cols {
+		cols[name] = true
+	}
+	for _, r := range f.Rows {
+		rows[r] = true
+	}
+	width, height := len(a.Headers)*size, top+len(a.Rows)*size
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%v" height="%v">`+"\n", width, height)
+	fmt.Fprintf(w, `<title>%v %v at depth %v</title>`+"\n", f.Event, html.EscapeString(f.Col), f.Depth)
+	for j, name := range a.Headers {
+		color := "black"
+		if !cols[name] {
+			color = "lightgray"
+		}
+		fmt.Fprintf(w, `<text x="%v" y="%v" fill="%v" text-anchor="middle">%v</text>`+"\n", j*size+size/2, top-10, color, html.EscapeString(name))
+	}
+	for r, row := range a.Rows {
+		for _, j := range row {
+			color := "black"
+			if !rows[r] || !cols[a.Headers[j]] {
+				color = "lightgray"
+			}
+			fmt.Fprintf(w, `<rect x="%v" y="%v" width="%v" height="%v" fill="%v"/>`+"\n", j*size+1, top+r*size+1, size-2, size-2, color)
+		}
+	}
+	_, err := fmt.Fprintln(w, "</svg>")
+	return err
+}
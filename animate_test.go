@@ -0,0 +1,40 @@
+package cover
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestAnimate(t *testing.T) {
+	solver := NewSolver(knuth(), []string{"A", "B", "C", "D", "E", "F", "G"})
+	a := solver.matrix.Animate()
+	solver.SolveAll()
+	if len(a.Frames) == 0 || len(a.Frames)%2 != 0 {
+		t.Fatalf("Animation has %v frames", len(a.Frames))
+	}
+	// covering A hides the rows A D G and A D
+	if f := a.Frames[0]; f.Col != "A" || len(f.Cols) != 6 || len(f.Rows) != 4 {
+		t.Errorf("First frame is %+v", f)
+	}
+	if f := a.Frames[len(a.Frames)-1]; f.Event != "uncover" || len(f.Cols) != 7 || len(f.Rows) != 6 {
+		t.Errorf("Last frame is %+v", f)
+	}
+	var b bytes.Buffer
+	if err := a.WriteJSON(&b); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Animation
+	if err := json.Unmarshal(b.Bytes(), &decoded); err != nil || len(decoded.Frames) != len(a.Frames) {
+		t.Errorf("JSON animation decodes to %v frames: %v", len(decoded.Frames), err)
+	}
+	b.Reset()
+	if err := a.WriteSVG(&b, 0); err != nil {
+		t.Fatal(err)
+	}
+	// the 5 cells of the rows A D G and A D are hidden
+	if n := strings.Count(b.String(), `fill="lightgray"/>`); n != 5 {
+		t.Errorf("First frame has %v hidden cells (wants %v)", n, 5)
+	}
+}
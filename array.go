@@ -0,0 +1,180 @@
+package cover
+
+import (
+	"math/big"
+	"time"
+)
+
+// Dancing links over index slices rather than pointers. Index 0 is the root,
+// indexes 1 to cols are the column headers, and the others are the row nodes.
+// The few big slices are easier on the cache and on the garbage collector
+// than the pointer nodes of SparseMatrix.
+type ArrayMatrix struct {
+	left, right, up, down, col []int32
+	// size of each column, indexed like the headers
+	size  []int32
+	names []string
+	// row index of each node
+	row []int32
+	// search nodes visited since the matrix was built
+	Nodes int64
+	// time after which the search gives up, ignored when zero
+	Deadline time.Time
+	expired  bool
+}
+
+// Builds an array matrix from rows listing the indexes of their columns.
+func NewArrayMatrix(rows [][]int, headers []string) *ArrayMatrix {
+	each := func(yield func([]int)) {
+		for _, r := range rows {
+			yield(r)
+		}
+	}
+	return newArrayMatrix(each, headers, func(n int) []int32 {
+		return make([]int32, n)
+	})
+}
+
+// Builds an array matrix from the rows given by each, which is called twice:
+// once to count the nodes, then to link them. The slices of nodes are
+// allocated by alloc.
+func newArrayMatrix(each func(func([]int)), headers []string, alloc func(int) []int32) *ArrayMatrix {
+	cols := len(headers)
+	n := cols + 1
+	each(func(r []int) {
+		n += len(r)
+	})
+	a := &ArrayMatrix{
+		left:  alloc(n),
+		right: alloc(n),
+		up:    alloc(n),
+		down:  alloc(n),
+		col:   alloc(n),
+		row:   alloc(n),
+		size:  make([]int32, cols+1),
+		names: append([]string{"root"}, headers...),
+	}
+	for i := 0; i <= cols; i++ {
+		a.left[i] = int32((i + cols) % (cols + 1))
+		a.right[i] = int32((i + 1) % (cols + 1))
+		a.up[i] = int32(i)
+		a.down[i] = int32(i)
+		a.row[i] = -1
+	}
+	p := int32(cols + 1)
+	i := 0
+	each(func(r []int) {
+		first := p
+		for _, j := range r {
+			c := int32(j + 1)
+			a.col[p] = c
+			a.row[p] = int32(i)
+			a.up[p] = a.up[c]
+			a.down[p] = c
+			a.down[a.up[c]] = p
+			a.up[c] = p
+			a.size[c]++
+			a.left[p] = p - 1
+			a.right[p] = p + 1
+			p++
+		}
+		if p > first {
+			a.left[first] = p - 1
+			a.right[p-1] = first
+		}
+		i++
+	})
+	return a
+}
+
+func (a *ArrayMatrix) cover(c int32) {
+	a.right[a.left[c]] = a.right[c]
+	a.left[a.right[c]] = a.left[c]
+	for i := a.down[c]; i != c; i = a.down[i] {
+		for j := a.right[i]; j != i; j = a.right[j] {
+			a.down[a.up[j]] = a.down[j]
+			a.up[a.down[j]] = a.up[j]
+			a.size[a.col[j]]--
+		}
+	}
+}
+
+func (a *ArrayMatrix) uncover(c int32) {
+	for i := a.up[c]; i != c; i = a.up[i] {
+		for j := a.left[i]; j != i; j = a.left[j] {
+			a.size[a.col[j]]++
+			a.down[a.up[j]] = j
+			a.up[a.down[j]] = j
+		}
+	}
+	a.right[a.left[c]] = c
+	a.left[a.right[c]] = c
+}
+
+// Returns the column having the smallest number of intersecting rows.
+func (a *ArrayMatrix) smallest() int32 {
+	best, min := int32(0), int32(-1)
+	for c := a.right[0]; c != 0; c = a.right[c] {
+		if min < 0 || a.size[c] < min {
+			best, min = c, a.size[c]
+		}
+	}
+	return best
+}
+
+// Runs the search, calling found with the nodes of the solution until it
+// returns true.
+func (a *ArrayMatrix) search(O []int32, found func([]int32) bool) bool {
+	if a.right[0] == 0 {
+		return found(O)
+	}
+	a.Nodes++
+	if a.Nodes%1024 == 0 && !a.Deadline.IsZero() && time.Now().After(a.Deadline) {
+		a.expired = true
+		return true
+	}
+	c := a.smallest()
+	a.cover(c)
+	stop := false
+	for r := a.down[c]; r != c && !stop; r = a.down[r] {
+		for j := a.right[r]; j != r; j = a.right[j] {
+			a.cover(a.col[j])
+		}
+		stop = a.search(append(O, r), found)
+		for j := a.left[r]; j != r; j = a.left[j] {
+			a.uncover(a.col[j])
+		}
+	}
+	a.uncover(c)
+	return stop
+}
+
+// Tells whether the last search gave up at the deadline.
+func (a *ArrayMatrix) Expired() bool {
+	return a.expired
+}
+
+// Returns the row indexes of the first solution found, nil if there is none.
+func (a *ArrayMatrix) Solve() []int {
+	var rows []int
+	a.expired = false
+	a.search(make([]int32, 0, len(a.size)), func(O []int32) bool {
+		rows = make([]int, len(O))
+		for i, n := range O {
+			rows[i] = int(a.row[n])
+		}
+		return true
+	})
+	return rows
+}
+
+// Counts all the solutions by exhausting the search tree.
+func (a *ArrayMatrix) Count() *big.Int {
+	n := new(big.Int)
+	a.expired = false
+	a.search(make([]int32, 0, len(a.size)), func([]int32) bool {
+		n.Add(n, one)
+		return false
+	})
+	return n
+}
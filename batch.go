@@ -0,0 +1,117 @@
+package cover
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Result of a puzzle solved by SolveDir().
+type BatchResult struct {
+	File string `json:"file"`
+	// index of the puzzle in the file
+	Index int `json:"index"`
+	// solution in the Line format, empty if there is none
+	Solution   string        `json:"solution,omitempty"`
+	Candidates int64         `json:"candidates"`
+	Elapsed    time.Duration `json:"elapsed"`
+	Error      string        `json:"error,omitempty"`
+}
+
+// Parses the puzzles of a file: a single grid in any format ParseGrid()
+// reads, or one grid per line.
+func parsePuzzles(data string) ([][][]int, error) {
+	if grid, err := ParseGrid(data); err == nil {
+		return [][][]int{grid}, nil
+	}
+	grids := make([][][]int, 0)
+	for _, line := range strings.Split(data, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		grid, err := ParseGrid(line)
+		if err != nil {
+			return nil, err
+		}
+		grids = append(grids, grid)
+	}
+	return grids, nil
+}
+
+// Solves the puzzles of the files of fsys matching the glob, concurrently,
+// and writes one JSON result per puzzle to out, with its solution and the
+// time spent, in the order of the files. A file that cannot be read or parsed
+// gives a single result holding the error. Returns the error of the glob or
+// of the writer.
+func SolveDir(fsys fs.FS, glob string, out io.Writer) error {
+	files, err := fs.Glob(fsys, glob)
+	if err != nil {
+		return err
+	}
+	results := make([][]BatchResult, len(files))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// solvers by dimension, reused across puzzles
+			solvers := map[int]*SudokuSolver{}
+			for i := range next {
+				results[i] = solveFile(fsys, files[i], solvers)
+			}
+		}()
+	}
+	for i := range files {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	enc := json.NewEncoder(out)
+	for _, rs := range results {
+		for _, r := range rs {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func solveFile(fsys fs.FS, name string, solvers map[int]*SudokuSolver) []BatchResult {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return []BatchResult{{File: name, Error: (&SolveError{Problem: name, Err: err}).Error()}}
+	}
+	grids, err := parsePuzzles(string(data))
+	if err != nil {
+		return []BatchResult{{File: name, Error: (&SolveError{Problem: name, Err: err}).Error()}}
+	}
+	results := make([]BatchResult, len(grids))
+	for i, grid := range grids {
+		s, ok := solvers[len(grid)]
+		if !ok {
+			s = NewSudokuSolver(len(grid))
+			solvers[len(grid)] = s
+		}
+		start := time.Now()
+		O := s.Solve(grid)
+		r := BatchResult{File: name, Index: i, Elapsed: time.Since(start)}
+		stats := s.Stats()
+		r.Candidates = stats.Candidates()
+		if O.Len() > 0 {
+			r.Solution = FormatGrid(s.Grid(O), nil, Line)
+		} else {
+			err := s.matrix.solveError(ErrNoSolution)
+			err.Problem = fmt.Sprintf("%v#%v", name, i)
+			r.Error = err.Error()
+		}
+		results[i] = r
+	}
+	return results
+}
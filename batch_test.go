@@ -0,0 +1,48 @@
+package cover
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSolveDir(t *testing.T) {
+	hard := "1....7.9..3..2...8..96..5....53..9...1..8...26....4...3......1..4......7..7...3.."
+	fsys := fstest.MapFS{
+		"a.txt":     {Data: []byte("Puzzle of the day\n" + FormatGrid(parseGrid(easySudoku), nil, Unicode))},
+		"b.txt":     {Data: []byte(hard + "\n" + easySudoku + "\n")},
+		"c.txt":     {Data: []byte("11" + strings.Repeat(".", 79))},
+		"d.txt":     {Data: []byte("12..")},
+		"notes.md":  {Data: []byte("not a puzzle")},
+		"sub/e.txt": {Data: []byte(hard)},
+	}
+	var b bytes.Buffer
+	if err := SolveDir(fsys, "*.txt", &b); err != nil {
+		t.Fatal(err)
+	}
+	results := make([]BatchResult, 0)
+	dec := json.NewDecoder(&b)
+	for dec.More() {
+		var r BatchResult
+		if err := dec.Decode(&r); err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, r)
+	}
+	want := []struct {
+		file  string
+		index int
+		ok    bool
+	}{{"a.txt", 0, true}, {"b.txt", 0, true}, {"b.txt", 1, true}, {"c.txt", 0, false}, {"d.txt", 0, false}}
+	if len(results) != len(want) {
+		t.Fatalf("%v results (wants %v): %v", len(results), len(want), results)
+	}
+	for i, w := range want {
+		r := results[i]
+		if r.File != w.file || r.Index != w.index || (r.Error == "") != w.ok || w.ok && len(r.Solution) != 81 {
+			t.Errorf("Result %v is %+v (wants %v #%v solved: %v)", i, r, w.file, w.index, w.ok)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package bench
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/qur2/go-cover"
+	"github.com/qur2/go-cover/problems"
+)
+
+// Engine able to give up counting at a deadline, which AutoTune() requires.
+type LimitedEngine interface {
+	Engine
+	// Returns the number of solutions and of search nodes visited by the
+	// deadline, and whether the search finished.
+	CountUntil(p *problems.Problem, h Heuristic, deadline time.Time) (*big.Int, int64, bool)
+}
+
+// Guesser aborting the search once the deadline is over.
+type limitedGuesser struct {
+	guesser
+	deadline time.Time
+}
+
+func (g *limitedGuesser) ChooseCol(k int) *cover.Node {
+	if g.nodes%1024 == 0 && time.Now().After(g.deadline) {
+		g.matrix.Abort()
+	}
+	return g.guesser.ChooseCol(k)
+}
+
+func (pointerEngine) CountUntil(p *problems.Problem, h Heuristic, deadline time.Time) (*big.Int, int64, bool) {
+	m := cover.NewSparseMatrix(p.Matrix, p.Headers)
+	if h.Setup != nil {
+		h.Setup(m)
+	}
+	g := &limitedGuesser{guesser{matrix: m, choose: h.Choose, solutions: new(big.Int)}, deadline}
+	m.Search(m.NewSolution(), 0, g)
+	return g.solutions, g.nodes, !m.Aborted()
+}
+
+func (arrayEngine) CountUntil(p *problems.Problem, h Heuristic, deadline time.Time) (*big.Int, int64, bool) {
+	a := cover.NewArrayMatrix(cover.SparseRows(p.Matrix), p.Headers)
+	a.Deadline = deadline
+	count := a.Count()
+	return count, a.Nodes, !a.Expired()
+}
+
+func (bitsetEngine) CountUntil(p *problems.Problem, h Heuristic, deadline time.Time) (*big.Int, int64, bool) {
+	b := cover.NewBitsetMatrix(cover.SparseRows(p.Matrix), len(p.Headers))
+	b.Deadline = deadline
+	count := b.Count()
+	return count, b.Nodes, !b.Expired()
+}
+
+// Races the configurations of RunAll() one after the other, each one getting
+// an equal share of the budget, and returns the one to use for the full run.
+// The fastest configuration exhausting the problem wins. When none does, the
+// one finding the most solutions wins, then the one visiting the most nodes.
+// Registered engines which are no LimitedEngine are left out.
+func AutoTune(p *problems.Problem, budget time.Duration) (Engine, Heuristic) {
+	type config struct {
+		e Engine
+		h Heuristic
+	}
+	configs := make([]config, 0)
+	for _, h := range RegisteredHeuristics() {
+		configs = append(configs, config{Pointer, h})
+	}
+	for _, e := range RegisteredEngines() {
+		if e.Name() != Pointer.Name() {
+			configs = append(configs, config{e, Smallest})
+		}
+	}
+	share := budget / time.Duration(len(configs))
+	best, bestDone := configs[0], false
+	var bestCount *big.Int
+	var bestNodes int64
+	var bestTime time.Duration
+	for _, c := range configs {
+		l, ok := c.e.(LimitedEngine)
+		if !ok {
+			continue
+		}
+		start := time.Now()
+		count, nodes, done := l.CountUntil(p, c.h, start.Add(share))
+		elapsed := time.Since(start)
+		better := false
+		switch {
+		case bestCount == nil:
+			better = true
+		case done || bestDone:
+			better = done && (!bestDone || elapsed < bestTime)
+		case count.Cmp(bestCount) != 0:
+			better = count.Cmp(bestCount) > 0
+		default:
+			better = nodes > bestNodes
+		}
+		if better {
+			best, bestDone = c, done
+			bestCount, bestNodes, bestTime = count, nodes, elapsed
+		}
+	}
+	return best.e, best.h
+}
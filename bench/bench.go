@@ -0,0 +1,158 @@
+/*
+Package bench runs exact cover problems against the available engines and
+heuristics, so that users can pick a configuration empirically.
+*/
+package bench
+
+import (
+	"context"
+	"math/big"
+	"runtime/pprof"
+	"time"
+
+	"github.com/qur2/go-cover"
+	"github.com/qur2/go-cover/problems"
+)
+
+// Outcome of counting the solutions of a problem with one configuration.
+type Result struct {
+	Problem   string
+	Engine    string
+	Heuristic string
+	Solutions *big.Int
+	// search nodes visited, i.e. the number of branching steps
+	Nodes    int64
+	Duration time.Duration
+}
+
+// Column choice strategy for the pointer engine.
+type Heuristic struct {
+	Name string
+	// prepares the matrix before the search, may be nil
+	Setup func(*cover.SparseMatrix)
+	// picks the column to branch on
+	Choose func(*cover.SparseMatrix) *cover.Node
+}
+
+func smallest(m *cover.SparseMatrix) *cover.Node {
+	return m.SmallestCol()
+}
+
+// Built-in heuristics, registered under their names. The array and bitset
+// engines always use the smallest column.
+var (
+	First = Heuristic{Name: "first", Choose: func(m *cover.SparseMatrix) *cover.Node {
+		return m.Root().Right
+	}}
+	Smallest = Heuristic{Name: "smallest", Choose: smallest}
+	Buckets  = Heuristic{Name: "buckets", Setup: (*cover.SparseMatrix).TrackSizes, Choose: smallest}
+	Units    = Heuristic{Name: "units", Setup: func(m *cover.SparseMatrix) {
+		m.TrackSizes()
+		m.PropagateUnits()
+	}, Choose: smallest}
+	Lookahead = Heuristic{Name: "lookahead", Setup: func(m *cover.SparseMatrix) {
+		m.Lookahead(4)
+	}, Choose: smallest}
+	Heuristics = []Heuristic{First, Smallest, Buckets, Units, Lookahead}
+)
+
+// Search engine able to count the solutions of a problem.
+type Engine interface {
+	Name() string
+	// Returns the number of solutions and of search nodes visited.
+	Count(p *problems.Problem, h Heuristic) (*big.Int, int64)
+}
+
+// Guesser counting the solutions and the branching steps.
+type guesser struct {
+	matrix    *cover.SparseMatrix
+	choose    func(*cover.SparseMatrix) *cover.Node
+	nodes     int64
+	solutions *big.Int
+}
+
+func (g *guesser) ChooseCol(k int) *cover.Node {
+	g.nodes++
+	return g.choose(g.matrix)
+}
+func (g *guesser) Eureka(O *cover.Solution) {
+	g.solutions.Add(g.solutions, big.NewInt(1))
+}
+func (g *guesser) Terminate() bool {
+	return false
+}
+
+type pointerEngine struct{}
+
+func (pointerEngine) Name() string { return "pointer" }
+func (pointerEngine) Count(p *problems.Problem, h Heuristic) (*big.Int, int64) {
+	m := cover.NewSparseMatrix(p.Matrix, p.Headers)
+	if h.Setup != nil {
+		h.Setup(m)
+	}
+	g := &guesser{matrix: m, choose: h.Choose, solutions: new(big.Int)}
+	m.Search(m.NewSolution(), 0, g)
+	return g.solutions, g.nodes
+}
+
+type arrayEngine struct{}
+
+func (arrayEngine) Name() string { return "array" }
+func (arrayEngine) Count(p *problems.Problem, h Heuristic) (*big.Int, int64) {
+	a := cover.NewArrayMatrix(cover.SparseRows(p.Matrix), p.Headers)
+	count := a.Count()
+	return count, a.Nodes
+}
+
+type bitsetEngine struct{}
+
+func (bitsetEngine) Name() string { return "bitset" }
+func (bitsetEngine) Count(p *problems.Problem, h Heuristic) (*big.Int, int64) {
+	b := cover.NewBitsetMatrix(cover.SparseRows(p.Matrix), len(p.Headers))
+	count := b.Count()
+	return count, b.Nodes
+}
+
+// Built-in engines, registered under their names.
+var (
+	Pointer Engine = pointerEngine{}
+	Array   Engine = arrayEngine{}
+	Bitset  Engine = bitsetEngine{}
+	Engines        = []Engine{Pointer, Array, Bitset}
+)
+
+// Counts the solutions of the problem with the given configuration.
+// The duration includes building the engine specific structure. The search is
+// tagged with the problem, engine and heuristic pprof labels.
+func Run(p *problems.Problem, e Engine, h Heuristic) Result {
+	var count *big.Int
+	var nodes int64
+	start := time.Now()
+	labels := pprof.Labels("problem", p.Name, "engine", e.Name(), "heuristic", h.Name)
+	pprof.Do(context.Background(), labels, func(context.Context) {
+		count, nodes = e.Count(p, h)
+	})
+	return Result{
+		Problem:   p.Name,
+		Engine:    e.Name(),
+		Heuristic: h.Name,
+		Solutions: count,
+		Nodes:     nodes,
+		Duration:  time.Since(start),
+	}
+}
+
+// Runs the problem against every registered engine, and every registered
+// heuristic for the pointer engine since the other ones ignore it.
+func RunAll(p *problems.Problem) []Result {
+	results := make([]Result, 0)
+	for _, h := range RegisteredHeuristics() {
+		results = append(results, Run(p, Pointer, h))
+	}
+	for _, e := range RegisteredEngines() {
+		if e.Name() != Pointer.Name() {
+			results = append(results, Run(p, e, Smallest))
+		}
+	}
+	return results
+}
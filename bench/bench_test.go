@@ -0,0 +1,74 @@
+package bench
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qur2/go-cover"
+	"github.com/qur2/go-cover/problems"
+)
+
+func TestRunAll(t *testing.T) {
+	for _, r := range RunAll(problems.HardSudoku("ai-escargot")) {
+		if r.Solutions.Int64() != 1 {
+			t.Errorf("%v/%v finds %v solutions (wants %v)", r.Engine, r.Heuristic, r.Solutions, 1)
+		}
+		if r.Nodes == 0 {
+			t.Errorf("%v/%v visits no node", r.Engine, r.Heuristic)
+		}
+	}
+}
+
+func TestAutoTune(t *testing.T) {
+	e, h := AutoTune(problems.HardSudoku("everest"), time.Second)
+	if r := Run(problems.HardSudoku("everest"), e, h); r.Solutions.Int64() != 1 {
+		t.Errorf("%v/%v finds %v solutions (wants %v)", r.Engine, r.Heuristic, r.Solutions, 1)
+	}
+	for _, e := range []Engine{Pointer, Array, Bitset} {
+		if _, ok := e.(LimitedEngine); !ok {
+			t.Errorf("Engine %v cannot be auto tuned", e.Name())
+		}
+	}
+	// the first column heuristic cannot exhaust 4x15 pentominoes in time
+	if _, h := AutoTune(problems.Pentominoes(4, 15), 100*time.Millisecond); h.Name == First.Name {
+		t.Errorf("Auto tuning picks the %v heuristic", h.Name)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	if h, err := LookupHeuristic("buckets"); err != nil || h.Name != Buckets.Name {
+		t.Errorf("Buckets lookup gives %v, %v", h.Name, err)
+	}
+	if e, err := LookupEngine("bitset"); err != nil || e != Bitset {
+		t.Errorf("Bitset lookup gives %v, %v", e, err)
+	}
+	if _, err := LookupHeuristic("nope"); err != ErrUnknown {
+		t.Errorf("Unknown heuristic gives %v (wants %v)", err, ErrUnknown)
+	}
+	// a heuristic of another package, then reloaded under the same name
+	last := Heuristic{Name: "last", Choose: func(m *cover.SparseMatrix) *cover.Node {
+		return m.Root().Left
+	}}
+	RegisterHeuristic(last)
+	n := len(RegisteredHeuristics())
+	last.Setup = (*cover.SparseMatrix).TrackSizes
+	RegisterHeuristic(last)
+	if len(RegisteredHeuristics()) != n {
+		t.Errorf("Registering again adds a heuristic")
+	}
+	if h, _ := LookupHeuristic("last"); h.Setup == nil {
+		t.Errorf("Registering again keeps the first heuristic")
+	}
+	found := false
+	for _, r := range RunAll(problems.Knuth()) {
+		if r.Heuristic == "last" {
+			found = true
+			if r.Solutions.Int64() != 1 {
+				t.Errorf("Registered heuristic finds %v solutions", r.Solutions)
+			}
+		}
+	}
+	if !found {
+		t.Error("RunAll skips the registered heuristic")
+	}
+}
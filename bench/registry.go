@@ -0,0 +1,92 @@
+package bench
+
+import (
+	"errors"
+	"sync"
+)
+
+// Error returned when looking up a name nothing was registered under.
+var ErrUnknown = errors.New("unknown heuristic or engine")
+
+// Heuristics and engines selectable by name, in registration order.
+var registry = struct {
+	sync.RWMutex
+	heuristics []Heuristic
+	engines    []Engine
+}{}
+
+func init() {
+	for _, h := range Heuristics {
+		RegisterHeuristic(h)
+	}
+	for _, e := range Engines {
+		RegisterEngine(e)
+	}
+}
+
+// Registers a heuristic under its name, e.g. from the init function of an
+// external package, so that commands and configs select it by name without
+// changing their code. Registering a name again replaces the heuristic from
+// the next lookup on, which reloads it in a running program.
+func RegisterHeuristic(h Heuristic) {
+	registry.Lock()
+	defer registry.Unlock()
+	for i, r := range registry.heuristics {
+		if r.Name == h.Name {
+			registry.heuristics[i] = h
+			return
+		}
+	}
+	registry.heuristics = append(registry.heuristics, h)
+}
+
+// Registers an engine under its name, see RegisterHeuristic().
+func RegisterEngine(e Engine) {
+	registry.Lock()
+	defer registry.Unlock()
+	for i, r := range registry.engines {
+		if r.Name() == e.Name() {
+			registry.engines[i] = e
+			return
+		}
+	}
+	registry.engines = append(registry.engines, e)
+}
+
+// Returns the heuristic registered under the name, or ErrUnknown.
+func LookupHeuristic(name string) (Heuristic, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+	for _, h := range registry.heuristics {
+		if h.Name == name {
+			return h, nil
+		}
+	}
+	return Heuristic{}, ErrUnknown
+}
+
+// Returns the engine registered under the name, or ErrUnknown.
+func LookupEngine(name string) (Engine, error) {
+	registry.RLock()
+	defer registry.RUnlock()
+	for _, e := range registry.engines {
+		if e.Name() == name {
+			return e, nil
+		}
+	}
+	return nil, ErrUnknown
+}
+
+// Returns the registered heuristics, in registration order.
+func RegisteredHeuristics() []Heuristic {
+	registry.RLock()
+	defer registry.RUnlock()
+	return append([]Heuristic{}, registry.heuristics...)
+}
+
+// Returns the registered engines, in registration order.
+func RegisteredEngines() []Engine {
+	registry.RLock()
+	defer registry.RUnlock()
+	return append([]Engine{}, registry.engines...)
+}
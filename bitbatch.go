@@ -0,0 +1,173 @@
+package cover
+
+import (
+	"math/big"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Batch of many small exact cover problems of up to 64 columns, like the
+// digit combinations of every cage of a killer sudoku. Each row is packed in
+// a single word and the rows of all the problems share one array, so adding
+// a problem costs no more than copying its rows, and the searches reuse the
+// same scratch buffers instead of building a matrix per problem.
+type BitBatch struct {
+	masks []uint64
+	// offset of the rows of each problem in masks, and the end of the last
+	starts []int
+	// columns of each problem, all set
+	full []uint64
+	// search nodes visited by the batch since it was built
+	Nodes int64
+}
+
+// Creates an empty batch.
+func NewBitBatch() *BitBatch {
+	return &BitBatch{starts: []int{0}}
+}
+
+// Adds a problem of rows listing the indexes of their columns, and returns
+// its index in the batch. Panics if there are more than 64 columns.
+func (b *BitBatch) Add(rows [][]int, cols int) int {
+	if cols > 64 {
+		panic("cover: more than 64 columns in a batch problem")
+	}
+	for _, r := range rows {
+		mask := uint64(0)
+		for _, j := range r {
+			mask |= 1 << uint(j)
+		}
+		b.masks = append(b.masks, mask)
+	}
+	b.starts = append(b.starts, len(b.masks))
+	b.full = append(b.full, 1<<uint(cols)-1)
+	return len(b.full) - 1
+}
+
+// Returns the number of problems in the batch.
+func (b *BitBatch) Len() int {
+	return len(b.full)
+}
+
+// Buffers of the searches of a worker, reused from problem to problem.
+type bitScratch struct {
+	// active rows of every level, stacked
+	active []int
+	O      []int
+	nodes  int64
+}
+
+// Searches the rows of masks stacked in s.active from lo, calling found with
+// the chosen row indexes until it returns true.
+func (s *bitScratch) search(masks []uint64, covered, full uint64, lo int, found func([]int) bool) bool {
+	hi := len(s.active)
+	// choose the uncovered column having the fewest active rows
+	best, min := uint64(0), hi-lo+1
+	for left := full &^ covered; left != 0 && min > 0; left &= left - 1 {
+		col := left & -left
+		n := 0
+		for _, r := range s.active[lo:hi] {
+			if masks[r]&col != 0 {
+				n++
+			}
+		}
+		if n < min {
+			best, min = col, n
+		}
+	}
+	if best == 0 {
+		return found(s.O)
+	}
+	s.nodes++
+	for i := lo; i < hi; i++ {
+		r := s.active[i]
+		if masks[r]&best == 0 {
+			continue
+		}
+		next := covered | masks[r]
+		for _, c := range s.active[lo:hi] {
+			if masks[c]&next == 0 {
+				s.active = append(s.active, c)
+			}
+		}
+		s.O = append(s.O, r)
+		stop := s.search(masks, next, full, hi, found)
+		s.O = s.O[:len(s.O)-1]
+		s.active = s.active[:hi]
+		if stop {
+			return true
+		}
+	}
+	return false
+}
+
+// Searches problem i, calling found with its row indexes until it returns true.
+func (s *bitScratch) run(b *BitBatch, i int, found func([]int) bool) {
+	masks := b.masks[b.starts[i]:b.starts[i+1]]
+	s.active, s.O = s.active[:0], s.O[:0]
+	for r, mask := range masks {
+		// rows covering no column would repeat every solution
+		if mask != 0 && mask&^b.full[i] == 0 {
+			s.active = append(s.active, r)
+		}
+	}
+	s.search(masks, 0, b.full[i], 0, found)
+}
+
+// Calls f on the index of every problem from concurrent workers, each with
+// its own scratch buffers.
+func (b *BitBatch) each(f func(s *bitScratch, i int)) {
+	next := int64(-1)
+	var wg sync.WaitGroup
+	for w := 0; w < runtime.NumCPU(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s := &bitScratch{}
+			for i := int(atomic.AddInt64(&next, 1)); i < b.Len(); i = int(atomic.AddInt64(&next, 1)) {
+				f(s, i)
+			}
+			atomic.AddInt64(&b.Nodes, s.nodes)
+		}()
+	}
+	wg.Wait()
+}
+
+// Returns the row indexes of the first solution of every problem, nil for
+// the problems having none. The problems are solved concurrently.
+func (b *BitBatch) Solve() [][]int {
+	solutions := make([][]int, b.Len())
+	b.each(func(s *bitScratch, i int) {
+		s.run(b, i, func(O []int) bool {
+			solutions[i] = append([]int{}, O...)
+			return true
+		})
+	})
+	return solutions
+}
+
+// Counts the solutions of every problem by exhausting their search trees.
+// The problems are solved concurrently.
+func (b *BitBatch) Count() []*big.Int {
+	counts := make([]*big.Int, b.Len())
+	b.each(func(s *bitScratch, i int) {
+		var n uint64
+		s.run(b, i, func([]int) bool {
+			n++
+			return false
+		})
+		counts[i] = new(big.Int).SetUint64(n)
+	})
+	return counts
+}
+
+// Calls f with the row indexes of every solution of problem i, until it
+// returns false. The rows are only valid during the call.
+func (b *BitBatch) Each(i int, f func(rows []int) bool) {
+	s := &bitScratch{}
+	s.run(b, i, func(O []int) bool {
+		return !f(O)
+	})
+	b.Nodes += s.nodes
+}
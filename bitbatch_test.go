@@ -0,0 +1,78 @@
+package cover
+
+import (
+	"testing"
+)
+
+func TestBitBatch(t *testing.T) {
+	b := NewBitBatch()
+	want := make([]int64, 0)
+	instances := make([][][]int, 0)
+	for seed := int64(0); seed < 50; seed++ {
+		matrix, headers, _ := GeneratePlantedCover(10+int(seed)%50, 30, 0.2, seed)
+		rows := SparseRows(matrix)
+		if i := b.Add(rows, len(headers)); i != int(seed) {
+			t.Fatalf("Problem %v added at index %v", seed, i)
+		}
+		want = append(want, NewBitsetMatrix(rows, len(headers)).Count().Int64())
+		instances = append(instances, rows)
+	}
+	for i, n := range b.Count() {
+		if n.Int64() != want[i] {
+			t.Errorf("Problem %v has %v solutions (wants %v)", i, n, want[i])
+		}
+	}
+	for i, rows := range b.Solve() {
+		covered := map[int]int{}
+		for _, r := range rows {
+			for _, j := range instances[i][r] {
+				covered[j]++
+			}
+		}
+		if len(covered) != 10+i%50 {
+			t.Errorf("Solution %v of problem %v covers %v columns", rows, i, len(covered))
+		}
+		for j, n := range covered {
+			if n != 1 {
+				t.Errorf("Solution %v of problem %v covers %v %v times", rows, i, j, n)
+			}
+		}
+	}
+	seen := 0
+	b.Each(3, func(rows []int) bool {
+		seen++
+		return false
+	})
+	if seen != 1 {
+		t.Errorf("Enumeration goes on after %v solutions", seen)
+	}
+	if b.Nodes == 0 {
+		t.Errorf("Batch counts no nodes")
+	}
+}
+
+func BenchmarkBitBatch(b *testing.B) {
+	matrices := make([][][]int, 1000)
+	rows := make([][][]int, len(matrices))
+	headers := randomHeaders(12)
+	for i := range rows {
+		matrices[i], _, _ = GeneratePlantedCover(12, 20, 0.25, int64(i))
+		rows[i] = SparseRows(matrices[i])
+	}
+	b.Run("batch", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			batch := NewBitBatch()
+			for _, r := range rows {
+				batch.Add(r, 12)
+			}
+			batch.Solve()
+		}
+	})
+	b.Run("sparse", func(b *testing.B) {
+		for n := 0; n < b.N; n++ {
+			for _, m := range matrices {
+				NewSolver(m, headers).Solve()
+			}
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package cover
+
+import (
+	"math/big"
+	"time"
+)
+
+// Exact cover engine storing each row as a bitset of its columns. There are no
+// links to maintain: each search level filters the rows compatible with the
+// chosen one. It suits small problems, or problems with few columns.
+type BitsetMatrix struct {
+	rows  [][]uint64
+	words int
+	cols  int
+	// search nodes visited since the matrix was built
+	Nodes int64
+	// time after which the search gives up, ignored when zero
+	Deadline time.Time
+	expired  bool
+}
+
+// Builds a bitset matrix from rows listing the indexes of their columns.
+func NewBitsetMatrix(rows [][]int, cols int) *BitsetMatrix {
+	b := &BitsetMatrix{words: (cols + 63) / 64, cols: cols}
+	b.rows = make([][]uint64, len(rows))
+	for i, r := range rows {
+		b.rows[i] = make([]uint64, b.words)
+		for _, j := range r {
+			b.rows[i][j/64] |= 1 << uint(j%64)
+		}
+	}
+	return b
+}
+
+func (b *BitsetMatrix) disjoint(x, y []uint64) bool {
+	for w := range x {
+		if x[w]&y[w] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Runs the search over the active rows, calling found with the chosen row
+// indexes until it returns true.
+func (b *BitsetMatrix) search(covered []uint64, active []int, O []int, found func([]int) bool) bool {
+	// choose the uncovered column having the fewest active rows
+	best, min := -1, len(active)+1
+	for j := 0; j < b.cols && min > 0; j++ {
+		if covered[j/64]&(1<<uint(j%64)) != 0 {
+			continue
+		}
+		n := 0
+		for _, r := range active {
+			if b.rows[r][j/64]&(1<<uint(j%64)) != 0 {
+				n++
+			}
+		}
+		if n < min {
+			best, min = j, n
+		}
+	}
+	if best < 0 {
+		return found(O)
+	}
+	b.Nodes++
+	if b.Nodes%1024 == 0 && !b.Deadline.IsZero() && time.Now().After(b.Deadline) {
+		b.expired = true
+		return true
+	}
+	next := make([]uint64, b.words)
+	for _, r := range active {
+		if b.rows[r][best/64]&(1<<uint(best%64)) == 0 {
+			continue
+		}
+		for w := range next {
+			next[w] = covered[w] | b.rows[r][w]
+		}
+		compatible := make([]int, 0, len(active))
+		for _, s := range active {
+			if b.disjoint(b.rows[s], next) {
+				compatible = append(compatible, s)
+			}
+		}
+		if b.search(next, compatible, append(O, r), found) {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *BitsetMatrix) run(found func([]int) bool) {
+	b.expired = false
+	active := make([]int, len(b.rows))
+	for i := range active {
+		active[i] = i
+	}
+	b.search(make([]uint64, b.words), active, make([]int, 0, b.cols), found)
+}
+
+// Tells whether the last search gave up at the deadline.
+func (b *BitsetMatrix) Expired() bool {
+	return b.expired
+}
+
+// Returns the row indexes of the first solution found, nil if there is none.
+func (b *BitsetMatrix) Solve() []int {
+	var rows []int
+	b.run(func(O []int) bool {
+		rows = append([]int{}, O...)
+		return true
+	})
+	return rows
+}
+
+// Counts all the solutions by exhausting the search tree.
+func (b *BitsetMatrix) Count() *big.Int {
+	n := new(big.Int)
+	b.run(func([]int) bool {
+		n.Add(n, one)
+		return false
+	})
+	return n
+}
@@ -0,0 +1,99 @@
+package cover
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Error returned by CheckRows() for a selection of rows that is not an exact
+// cover, listing every problem found.
+type CoverError struct {
+	// row indexes not in the matrix, or repeated
+	Unknown, Repeated []int
+	// names of the primary columns no row covers
+	Uncovered []string
+	// names of the columns several rows cover, with the rows covering them
+	Overlaps map[string][]int
+}
+
+func (e *CoverError) Error() string {
+	var parts []string
+	if len(e.Unknown) > 0 {
+		parts = append(parts, fmt.Sprintf("unknown rows %v", e.Unknown))
+	}
+	if len(e.Repeated) > 0 {
+		parts = append(parts, fmt.Sprintf("repeated rows %v", e.Repeated))
+	}
+	if len(e.Uncovered) > 0 {
+		parts = append(parts, fmt.Sprintf("uncovered columns %v", e.Uncovered))
+	}
+	names := make([]string, 0, len(e.Overlaps))
+	for name := range e.Overlaps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("column %v covered by rows %v", name, e.Overlaps[name]))
+	}
+	return "not an exact cover: " + strings.Join(parts, ", ")
+}
+
+// Checks that the rows of the given indexes, produced by another solver or by
+// hand, are an exact cover of the remaining matrix: every primary column is
+// covered once and every secondary column at most once, or by rows giving it
+// the same color. The matrix should be
+// restored, by Reset() after Solve() for instance. Returns a *CoverError
+// describing all the problems otherwise.
+func (s *Solver) CheckRows(rowIDs []int) error {
+	m := s.matrix
+	nodes := m.rowNodes()
+	e := &CoverError{Overlaps: map[string][]int{}}
+	covers := map[*Node][]*Node{}
+	seen := map[int]bool{}
+	for _, id := range rowIDs {
+		r, ok := nodes[id]
+		switch {
+		case !ok:
+			e.Unknown = append(e.Unknown, id)
+			continue
+		case seen[id]:
+			e.Repeated = append(e.Repeated, id)
+			continue
+		}
+		seen[id] = true
+		covers[r.Col] = append(covers[r.Col], r)
+		for j := r.Right; j != r; j = j.Right {
+			covers[j.Col] = append(covers[j.Col], j)
+		}
+	}
+	secondary := m.secondaryCols()
+	m.forEachCol(func(col *Node) {
+		covering := covers[col]
+		switch {
+		case len(covering) == 0 && !secondary[col]:
+			e.Uncovered = append(e.Uncovered, col.Name)
+		case !agreeing(covering):
+			rows := make([]int, len(covering))
+			for i, j := range covering {
+				rows[i] = j.Row
+			}
+			e.Overlaps[col.Name] = rows
+		}
+	})
+	if len(e.Unknown)+len(e.Repeated)+len(e.Uncovered)+len(e.Overlaps) > 0 {
+		return e
+	}
+	return nil
+}
+
+// Tells whether the nodes of a column agree on its color, see agree(). A
+// single node always does.
+func agreeing(nodes []*Node) bool {
+	for i := 1; i < len(nodes); i++ {
+		if !agree(nodes[0], nodes[i]) {
+			return false
+		}
+	}
+	return true
+}
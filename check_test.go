@@ -0,0 +1,44 @@
+package cover
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCheckRows(t *testing.T) {
+	s := NewSolver(knuth(), []string{"A", "B", "C", "D", "E", "F", "G"})
+	if err := s.CheckRows([]int{0, 3, 4}); err != nil {
+		t.Errorf("Knuth solution fails the check: %v", err)
+	}
+	err := s.CheckRows([]int{0, 1, 1, 4, 9})
+	var e *CoverError
+	if !errors.As(err, &e) {
+		t.Fatalf("Wrong selection gives %v (wants a *CoverError)", err)
+	}
+	if !reflect.DeepEqual(e.Unknown, []int{9}) || !reflect.DeepEqual(e.Repeated, []int{1}) {
+		t.Errorf("Unknown rows %v and repeated rows %v (wants [9] and [1])", e.Unknown, e.Repeated)
+	}
+	if len(e.Uncovered) != 0 || !reflect.DeepEqual(e.Overlaps, map[string][]int{"G": {1, 4}}) {
+		t.Errorf("Uncovered %v and overlaps %v (wants none and G by rows 1 and 4)", e.Uncovered, e.Overlaps)
+	}
+	err = s.CheckRows([]int{3})
+	if !errors.As(err, &e) || !reflect.DeepEqual(e.Uncovered, []string{"B", "C", "E", "F", "G"}) {
+		t.Errorf("Single row gives %v (wants B, C, E, F, G uncovered)", err)
+	}
+}
+
+func TestCheckRowsColors(t *testing.T) {
+	s, err := coloredSpec(t).Solver()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CheckRows([]int{0, 1}); err != nil {
+		t.Errorf("Rows agreeing on the color of x fail the check: %v", err)
+	}
+	err = s.CheckRows([]int{0, 2})
+	var e *CoverError
+	if !errors.As(err, &e) || !reflect.DeepEqual(e.Overlaps, map[string][]int{"x": {0, 2}}) {
+		t.Errorf("Rows giving x two colors give %v (wants x covered by rows 0 and 2)", err)
+	}
+}
@@ -0,0 +1,248 @@
+package cover
+
+import (
+	"math/rand"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// Guesser looking for a solution different from a known grid, and aborting
+// the search once found.
+type differ struct {
+	solver *SudokuSolver
+	grid   [][]int
+	other  [][]int
+}
+
+func (d *differ) ChooseCol(k int) *Node {
+	return d.solver.matrix.SmallestCol()
+}
+func (d *differ) Eureka(O *Solution) {
+	g := d.solver.Grid(O)
+	for i, line := range g {
+		for j, cell := range line {
+			if cell != d.grid[i][j] {
+				d.other = g
+				d.solver.matrix.Abort()
+				return
+			}
+		}
+	}
+}
+func (d *differ) Terminate() bool {
+	return false
+}
+
+// Returns the cells, as x*dim+y, where another completion of the clues differs
+// from the complete grid, nil if the grid is the only one.
+func (s *SudokuSolver) otherCompletion(grid [][]int, clues []int) []int {
+	s.Reset()
+	m := s.matrix
+	dim := len(grid)
+	partial := make([][]int, dim)
+	for i := range partial {
+		partial[i] = make([]int, dim)
+	}
+	for _, c := range clues {
+		partial[c/dim][c%dim] = grid[c/dim][c%dim]
+	}
+	O := m.NewSolution()
+	k, err := m.Require(O, s.givens(partial)...)
+	if err != nil {
+		return nil
+	}
+	d := &differ{solver: s, grid: grid}
+	m.Search(O, k, d)
+	m.Release(O, k)
+	if d.other == nil {
+		return nil
+	}
+	diff := make([]int, 0)
+	for i, line := range d.other {
+		for j, cell := range line {
+			if cell != grid[i][j] {
+				diff = append(diff, i*dim+j)
+			}
+		}
+	}
+	return diff
+}
+
+// Search for the puzzles of a complete grid, as a hitting set problem: every
+// puzzle having a unique solution contains a cell of each unavoidable set,
+// i.e. a set of cells whose digits can be permuted into another grid.
+type clueSearch struct {
+	solver *SudokuSolver
+	grid   [][]int
+	max    int
+	sets   [][]int
+	stop   *atomic.Bool
+	found  func([]int) bool
+}
+
+// Finds unavoidable sets by removing random groups of cells from the grid and
+// comparing another completion with the grid.
+func (c *clueSearch) probe(rnd *rand.Rand, tries int) {
+	dim := len(c.grid)
+	seen := map[string]bool{}
+	for i := 0; i < tries; i++ {
+		removed := map[int]bool{}
+		for _, cell := range rnd.Perm(dim * dim)[:4+rnd.Intn(2*dim)] {
+			removed[cell] = true
+		}
+		clues := make([]int, 0, dim*dim)
+		for cell := 0; cell < dim*dim; cell++ {
+			if !removed[cell] {
+				clues = append(clues, cell)
+			}
+		}
+		if set := c.solver.otherCompletion(c.grid, clues); set != nil && !seen[cellsKey(set)] {
+			seen[cellsKey(set)] = true
+			c.sets = append(c.sets, set)
+		}
+	}
+	sort.SliceStable(c.sets, func(i, j int) bool {
+		return len(c.sets[i]) < len(c.sets[j])
+	})
+}
+
+// Returns a string key of the cells, for deduplication.
+func cellsKey(s []int) string {
+	b := make([]byte, 0, len(s)*2)
+	for _, n := range s {
+		b = append(b, byte(n), byte(n>>8))
+	}
+	return string(b)
+}
+
+// Returns the unhit set having the fewest allowed cells, and a lower bound on
+// the clues still needed: the size of a greedy packing of disjoint unhit sets.
+func (c *clueSearch) unhit(chosen, excluded []bool) (best []int, need int) {
+	used := map[int]bool{}
+	bestLen := -1
+	for _, set := range c.sets {
+		hit, disjoint := false, true
+		allowed := 0
+		for _, cell := range set {
+			hit = hit || chosen[cell]
+			disjoint = disjoint && !used[cell]
+			if !excluded[cell] {
+				allowed++
+			}
+		}
+		if hit {
+			continue
+		}
+		if disjoint {
+			need++
+			for _, cell := range set {
+				used[cell] = true
+			}
+		}
+		if bestLen < 0 || allowed < bestLen {
+			best, bestLen = set, allowed
+		}
+	}
+	return best, need
+}
+
+func (c *clueSearch) search(clues []int, chosen, excluded []bool) {
+	if c.stop.Load() {
+		return
+	}
+	set, need := c.unhit(chosen, excluded)
+	if len(clues)+need > c.max {
+		return
+	}
+	if set == nil {
+		// every known set is hit: the clues are a puzzle or reveal a new set
+		other := c.solver.otherCompletion(c.grid, clues)
+		if other == nil {
+			if !c.found(append([]int{}, clues...)) {
+				c.stop.Store(true)
+			}
+			return
+		}
+		c.sets = append(c.sets, other)
+		set = other
+		if len(clues) == c.max {
+			return
+		}
+	}
+	branched := make([]int, 0)
+	for _, cell := range set {
+		if excluded[cell] {
+			continue
+		}
+		chosen[cell] = true
+		c.search(append(clues, cell), chosen, excluded)
+		chosen[cell] = false
+		excluded[cell] = true
+		branched = append(branched, cell)
+	}
+	for _, cell := range branched {
+		excluded[cell] = false
+	}
+}
+
+// Searches the puzzles of at most n clues taken from the complete grid that
+// have a unique solution, calling found with each one until it returns false.
+// This is how 17 clue sudokus are hunted: clues must hit every unavoidable
+// set of the grid, so the search branches on the cells of the smallest unhit
+// set known and prunes when disjoint unhit sets need more clues than left.
+// Sets are first found by probing, then by the uniqueness checks themselves.
+// The branches of the first set are shared among workers, each one having
+// its own solver, a non positive count using all the CPUs. Found is called
+// from a single goroutine at a time.
+func SearchPuzzles(grid [][]int, n, workers int, found func(puzzle [][]int) bool) {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	dim := len(grid)
+	var stop atomic.Bool
+	var mu sync.Mutex
+	report := func(clues []int) bool {
+		puzzle := make([][]int, dim)
+		for i := range puzzle {
+			puzzle[i] = make([]int, dim)
+		}
+		for _, cell := range clues {
+			puzzle[cell/dim][cell%dim] = grid[cell/dim][cell%dim]
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		return !stop.Load() && found(puzzle)
+	}
+	root := &clueSearch{solver: NewSudokuSolver(dim), grid: grid, max: n, stop: &stop, found: report}
+	root.probe(rand.New(rand.NewSource(1)), 16*dim)
+	if len(root.sets) == 0 {
+		return
+	}
+	first := root.sets[0]
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := &clueSearch{solver: NewSudokuSolver(dim), grid: grid, max: n, stop: &stop, found: report}
+			c.sets = append([][]int{}, root.sets...)
+			for i := range next {
+				// branch i takes the ith cell of the first set and none before
+				chosen, excluded := make([]bool, dim*dim), make([]bool, dim*dim)
+				for _, cell := range first[:i] {
+					excluded[cell] = true
+				}
+				chosen[first[i]] = true
+				c.search([]int{first[i]}, chosen, excluded)
+			}
+		}()
+	}
+	for i := range first {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+}
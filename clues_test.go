@@ -0,0 +1,49 @@
+package cover
+
+import (
+	"testing"
+)
+
+func TestSearchPuzzles(t *testing.T) {
+	grid := [][]int{{1, 2, 3, 4}, {3, 4, 1, 2}, {2, 1, 4, 3}, {4, 3, 2, 1}}
+	s := NewSudokuSolver(4)
+	// brute force over the subsets of 4 cells, no 4x4 sudoku having fewer clues
+	want := 0
+	var pick func(clues []int, from int)
+	pick = func(clues []int, from int) {
+		if len(clues) == 4 {
+			if s.otherCompletion(grid, clues) == nil {
+				want++
+			}
+			return
+		}
+		for c := from; c < 16; c++ {
+			pick(append(clues, c), c+1)
+		}
+	}
+	pick([]int{}, 0)
+	if want == 0 {
+		t.Fatalf("Grid has no puzzle of 4 clues")
+	}
+	for _, workers := range []int{1, 3} {
+		seen := map[string]bool{}
+		SearchPuzzles(grid, 4, workers, func(puzzle [][]int) bool {
+			seen[FormatGrid(puzzle, nil, Line)] = true
+			if O := s.Solve(puzzle); O.Len() != 16 {
+				t.Errorf("Puzzle %v has no solution", FormatGrid(puzzle, nil, Line))
+			}
+			return true
+		})
+		if len(seen) != want {
+			t.Errorf("%v workers find %v puzzles of 4 clues (wants %v)", workers, len(seen), want)
+		}
+	}
+	calls := 0
+	SearchPuzzles(grid, 4, 2, func([][]int) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Errorf("Search goes on after stop, %v calls", calls)
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Command dlx counts the solutions of the exact cover problem read from the
+standard input. The first line lists the column names, each following line
+lists the columns of a row, names being separated by spaces. Empty lines and
+lines starting with # are skipped. With -json, the problem is read as a
+cover.Spec instead, which also allows secondary columns.
+
+With -engine or -heuristic, the count runs on the engine and heuristic
+registered under these names in package bench, without the progress line.
+With -estimate, the count is estimated from that many random probes of the
+search tree instead, which is much faster on huge problems. With -hardness,
+the columns are listed from the most constraining to the least instead.
+
+A progress line is shown on the standard error during the search, with the
+time left as estimated from random probes of the search tree, and an
+interrupt stops it with the partial count and statistics. SIGUSR1, or SIGINFO
+where available, dumps the statistics and the rows chosen so far.
+*/
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/qur2/go-cover"
+	"github.com/qur2/go-cover/bench"
+	"github.com/qur2/go-cover/cmd/internal/cli"
+	"github.com/qur2/go-cover/problems"
+)
+
+// Reads the problem as a binary matrix and its headers.
+func read(f *os.File) (matrix [][]int, headers []string, err error) {
+	index := map[string]int{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		names := strings.Fields(line)
+		if headers == nil {
+			headers = names
+			for i, name := range names {
+				index[name] = i
+			}
+			continue
+		}
+		row := make([]int, len(headers))
+		for _, name := range names {
+			j, ok := index[name]
+			if !ok {
+				return nil, nil, fmt.Errorf("row %v: unknown column %v", len(matrix), name)
+			}
+			row[j] = 1
+		}
+		matrix = append(matrix, row)
+	}
+	return matrix, headers, scanner.Err()
+}
+
+// Counts the solutions of the matrix read from the standard input with the
+// named engine and heuristic, the pointer engine and the smallest column by
+// default.
+func runBench(engine, heuristic string) error {
+	e, h := bench.Pointer, bench.Smallest
+	var err error
+	if engine != "" {
+		if e, err = bench.LookupEngine(engine); err != nil {
+			return fmt.Errorf("engine %v: %w", engine, err)
+		}
+	}
+	if heuristic != "" {
+		if h, err = bench.LookupHeuristic(heuristic); err != nil {
+			return fmt.Errorf("heuristic %v: %w", heuristic, err)
+		}
+	}
+	matrix, headers, err := read(os.Stdin)
+	if err != nil {
+		return err
+	}
+	r := bench.Run(&problems.Problem{Name: "stdin", Matrix: matrix, Headers: headers}, e, h)
+	fmt.Println(r.Solutions)
+	return nil
+}
+
+func main() {
+	every := flag.Duration("progress", 200*time.Millisecond, "period of the progress line, 0 to hide it")
+	spec := flag.Bool("json", false, "read the problem as a JSON spec")
+	engine := flag.String("engine", "", "name of a registered engine, see package bench")
+	heuristic := flag.String("heuristic", "", "name of a registered heuristic, see package bench")
+	estimate := flag.Int("estimate", 0, "number of random probes estimating the count, 0 to count exactly")
+	hardness := flag.Bool("hardness", false, "list how constraining each column is instead of counting")
+	flag.Parse()
+	if *engine != "" || *heuristic != "" {
+		if err := runBench(*engine, *heuristic); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	var s *cover.Solver
+	monitor := cli.Watch(*every, func() cover.Stats { return s.Stats() })
+	opts := []cover.Option{cover.WithSizeTracking(), monitor.Option()}
+	if *spec {
+		p, err := cover.LoadSpec(os.Stdin)
+		if err == nil {
+			s, err = p.Solver(opts...)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	} else {
+		matrix, headers, err := read(os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		s = cover.NewSolver(matrix, headers, opts...)
+	}
+	if *hardness {
+		monitor.Done()
+		for _, h := range s.Hardness() {
+			fmt.Println(h)
+		}
+		return
+	}
+	if *estimate > 0 {
+		monitor.Done()
+		fmt.Println(s.EstimateCount(*estimate, time.Now().UnixNano()))
+		return
+	}
+	if monitor.Shown() {
+		monitor.Estimate(s.EstimateCount(cli.Probes, time.Now().UnixNano()).Nodes)
+	}
+	count := s.Count()
+	monitor.Done()
+	if monitor.Stopped() {
+		fmt.Println(count, "solutions so far")
+		os.Exit(130)
+	}
+	fmt.Println(count)
+}
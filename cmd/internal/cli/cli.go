@@ -0,0 +1,125 @@
+/*
+Package cli monitors the searches run by the commands: it shows a progress
+line, with the time left when the size of the search tree is estimated, stops
+the search on an interrupt, and dumps the search statistics and the partial
+solution when sent a status signal, i.e. SIGUSR1, or SIGINFO (Ctrl-T) where
+available.
+*/
+package cli
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"time"
+
+	"github.com/qur2/go-cover"
+)
+
+// Period at which the search checks for signals.
+const poll = 100 * time.Millisecond
+
+// Number of random probes estimating the size of the search tree, see
+// Estimate().
+const Probes = 100
+
+// Monitor of a command line search.
+type Monitor struct {
+	// period of the progress line, 0 to hide it
+	every   time.Duration
+	printed time.Duration
+	// estimated nodes of the search tree, 0 when unknown
+	nodes   float64
+	stopped atomic.Bool
+	status  atomic.Bool
+	// returns the statistics of the search, for the status dumps
+	stats func() cover.Stats
+}
+
+// Installs the signal handlers. The progress line is shown every period,
+// unless it is 0.
+func Watch(every time.Duration, stats func() cover.Stats) *Monitor {
+	m := &Monitor{every: every, stats: stats}
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	statuses := make(chan os.Signal, 1)
+	if len(statusSignals) > 0 {
+		signal.Notify(statuses, statusSignals...)
+	}
+	go func() {
+		for {
+			select {
+			case <-interrupts:
+				m.stopped.Store(true)
+			case <-statuses:
+				m.status.Store(true)
+			}
+		}
+	}()
+	return m
+}
+
+// Returns the option reporting the progress of the solver to the monitor.
+func (m *Monitor) Option() cover.Option {
+	return cover.WithProgress(poll, m.report)
+}
+
+// Tells whether the progress line is shown, so that the size of the search
+// tree is worth estimating.
+func (m *Monitor) Shown() bool {
+	return m.every > 0
+}
+
+// Sets the estimated size of the search tree, from which the progress line
+// shows the share of the tree searched and the time left to exhaust it, e.g.
+// from the Nodes of Solver.EstimateCount() with Probes probes.
+func (m *Monitor) Estimate(nodes float64) {
+	m.nodes = nodes
+}
+
+func (m *Monitor) report(p cover.Progress) bool {
+	if m.every > 0 && p.Elapsed-m.printed >= m.every {
+		m.printed = p.Elapsed
+		fmt.Fprintf(os.Stderr, "\r%v", line(p, m.nodes))
+	}
+	if m.status.Swap(false) {
+		stats := m.stats()
+		fmt.Fprintf(os.Stderr, "\n%vrows chosen above depth %v:\n%v", stats.String(), p.Depth, p.Stack.String())
+	}
+	return !m.stopped.Load()
+}
+
+// Formats the progress line, with the time left to search the estimated
+// nodes unless 0. The estimate may fall short of the tree, in which case the
+// time left is unknown.
+func line(p cover.Progress, nodes float64) string {
+	s := fmt.Sprintf("%v nodes, %.0f nodes/s, depth %v, %v solutions", p.Nodes, p.Rate(), p.Depth, p.Solutions)
+	if nodes <= 0 {
+		return s
+	}
+	left := nodes - float64(p.Nodes)
+	if left <= 0 || p.Rate() <= 0 {
+		return s + fmt.Sprintf(", past the ~%.3g nodes estimated", nodes)
+	}
+	eta := time.Duration(math.Min(left/p.Rate(), math.MaxInt64/1e9) * 1e9).Round(time.Second)
+	return s + fmt.Sprintf(", %.0f%% of ~%.3g nodes, ETA %v", 100*float64(p.Nodes)/nodes, nodes, eta)
+}
+
+// Tells whether the search was interrupted.
+func (m *Monitor) Stopped() bool {
+	return m.stopped.Load()
+}
+
+// Ends the progress line, and prints the statistics if the search was
+// interrupted.
+func (m *Monitor) Done() {
+	if m.printed > 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+	if m.Stopped() {
+		stats := m.stats()
+		fmt.Fprintf(os.Stderr, "interrupted\n%v", stats.String())
+	}
+}
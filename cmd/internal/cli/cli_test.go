@@ -0,0 +1,21 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/qur2/go-cover"
+)
+
+func TestLine(t *testing.T) {
+	p := cover.Progress{Nodes: 1000, Solutions: 2, Depth: 5, Elapsed: 2 * time.Second}
+	for nodes, want := range map[float64]string{
+		0:    "1000 nodes, 500 nodes/s, depth 5, 2 solutions",
+		4000: "1000 nodes, 500 nodes/s, depth 5, 2 solutions, 25% of ~4e+03 nodes, ETA 6s",
+		500:  "1000 nodes, 500 nodes/s, depth 5, 2 solutions, past the ~500 nodes estimated",
+	} {
+		if got := line(p, nodes); got != want {
+			t.Errorf("Line for %v nodes is %q (wants %q)", nodes, got, want)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+
+package cli
+
+import (
+	"syscall"
+)
+
+func init() {
+	// sent by Ctrl-T
+	statusSignals = append(statusSignals, syscall.SIGINFO)
+}
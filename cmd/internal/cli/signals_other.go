@@ -0,0 +1,9 @@
+//go:build !unix
+
+package cli
+
+import (
+	"os"
+)
+
+var statusSignals []os.Signal
@@ -0,0 +1,90 @@
+/*
+Command sudoku solves the sudoku read from the arguments or from the standard
+input, in any layout accepted by cover.ParseGrid(), or as the JSON of an
+f-puzzles sudoku whose constraints map to variants. A progress line is shown on
+the standard error during long searches, with the time left to exhaust the
+search tree as estimated from random probes, and an interrupt stops the search
+with its statistics. SIGUSR1, or SIGINFO where available, dumps the statistics
+and the rows chosen so far.
+
+The solution is printed on the standard output in the format named by
+-format: line, box, csv, json or unicode.
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/qur2/go-cover"
+	"github.com/qur2/go-cover/cmd/internal/cli"
+)
+
+// Returns the grid format of the given name.
+func parseFormat(name string) (cover.Format, error) {
+	for _, f := range []cover.Format{cover.Line, cover.Box, cover.CSV, cover.JSON, cover.Unicode} {
+		if f.String() == name {
+			return f, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown format %q", name)
+}
+
+func main() {
+	every := flag.Duration("progress", 200*time.Millisecond, "period of the progress line, 0 to hide it")
+	name := flag.String("format", "box", "format of the solution: line, box, csv, json or unicode")
+	flag.Parse()
+	format, err := parseFormat(*name)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	var text string
+	if flag.NArg() > 0 {
+		text = strings.Join(flag.Args(), "\n")
+	} else {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		text = string(data)
+	}
+	var s *cover.SudokuSolver
+	monitor := cli.Watch(*every, func() cover.Stats { return s.Stats() })
+	var grid [][]int
+	if strings.HasPrefix(strings.TrimSpace(text), "{") {
+		var p *cover.FPuzzle
+		if p, err = cover.ParseFPuzzles([]byte(text)); err == nil {
+			grid = p.Givens
+			s, err = p.Solver(monitor.Option())
+		}
+	} else if grid, err = cover.ParseGrid(text); err == nil {
+		s = cover.NewSudokuSolver(len(grid), monitor.Option())
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if monitor.Shown() {
+		monitor.Estimate(s.EstimateCount(grid, cli.Probes, time.Now().UnixNano()).Nodes)
+	}
+	O := s.Solve(grid)
+	monitor.Done()
+	switch {
+	case monitor.Stopped():
+		os.Exit(130)
+	case O.Len() == 0:
+		fmt.Fprintln(os.Stderr, "no solution")
+		os.Exit(1)
+	}
+	out := cover.FormatGrid(s.Grid(O), grid, format)
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	fmt.Print(out)
+}
@@ -0,0 +1,96 @@
+package cover
+
+// Includes a node of a chosen row on behalf of the search: covers its column
+// if the node has no color, or purifies the column if it has one, so that
+// the rows giving the column another color leave the matrix while those
+// agreeing with it stay. A column already purified with the color is left.
+func (m *SparseMatrix) commit(j *Node, k int) {
+	switch {
+	case j.Color == 0:
+		m.cover(j.Col, k)
+	case j.Color > 0:
+		m.purify(j)
+	}
+}
+
+// Undoes commit(), which must be undone in reverse order.
+func (m *SparseMatrix) uncommit(j *Node, k int) {
+	switch {
+	case j.Color == 0:
+		m.uncover(j.Col, k)
+	case j.Color > 0:
+		m.unpurify(j)
+	}
+}
+
+// Hides the rows of the column giving it another color than the node, and
+// marks the nodes agreeing with it, whose rows no longer purify the column,
+// with the color -1. The node keeps its color for unpurify().
+func (m *SparseMatrix) purify(p *Node) {
+	c := p.Color
+	col := p.Col
+	for q := col.Down; q != col; q = q.Down {
+		switch {
+		case q == p:
+		case q.Color == c:
+			q.Color = -1
+		default:
+			m.hideOthers(q)
+		}
+	}
+}
+
+// Undoes purify(), in reverse order.
+func (m *SparseMatrix) unpurify(p *Node) {
+	c := p.Color
+	col := p.Col
+	for q := col.Up; q != col; q = q.Up {
+		switch {
+		case q == p:
+		case q.Color < 0:
+			q.Color = c
+		default:
+			m.unhideOthers(q)
+		}
+	}
+}
+
+// Removes the other nodes of the row from their columns.
+func (m *SparseMatrix) hideOthers(q *Node) {
+	for j := q.Right; j != q; j = j.Right {
+		j.Down.Up = j.Up
+		j.Up.Down = j.Down
+		if b := j.Col.bucket; b != nil {
+			b.remove(j.Col)
+			j.Col.Size--
+			b.insert(j.Col)
+		} else {
+			j.Col.Size--
+		}
+	}
+}
+
+// Undoes hideOthers(), in reverse order.
+func (m *SparseMatrix) unhideOthers(q *Node) {
+	for j := q.Left; j != q; j = j.Left {
+		if b := j.Col.bucket; b != nil {
+			b.remove(j.Col)
+			j.Col.Size++
+			b.insert(j.Col)
+		} else {
+			j.Col.Size++
+		}
+		j.Down.Up = j
+		j.Up.Down = j
+	}
+}
+
+// Tells whether the rows of two nodes of a column may both be chosen: both
+// give it a color and the colors are the same. In a purified column all the
+// rows left agree.
+func agree(a, b *Node) bool {
+	if a.Color == 0 || b.Color == 0 {
+		return false
+	}
+	return a.Color == b.Color || a.Color < 0 || b.Color < 0
+}
@@ -0,0 +1,97 @@
+package cover
+
+import (
+	"encoding/binary"
+	"math/big"
+)
+
+var one = big.NewInt(1)
+
+// Guesser counting the solutions rather than storing them.
+type counter struct {
+	matrix *SparseMatrix
+	count  *big.Int
+}
+
+func (c *counter) ChooseCol(k int) *Node {
+	return c.matrix.SmallestCol()
+}
+func (c *counter) Eureka(O *Solution) {
+	c.count.Add(c.count, one)
+}
+func (c *counter) Terminate() bool {
+	return false
+}
+
+// Counts all the solutions by exhausting the search tree. Counts are big integers
+// because sudoku completions or large tilings quickly overflow native integers.
+func (s *Solver) Count() *big.Int {
+	s.matrix.ResetStats()
+	c := &counter{matrix: s.matrix, count: new(big.Int)}
+	s.matrix.Search(s.matrix.NewSolution(), 0, c)
+	return c.count
+}
+
+// Counts all the solutions, caching the count of each subproblem met during the
+// search. A subproblem only depends on the remaining columns and on the colors
+// given to the colored ones, so structured problems like domino tilings reach
+// the same ones through many paths and count exponentially faster. The cache
+// grows with the number of distinct subproblems. An aborted search, e.g. by a
+// node limit, returns the count so far.
+func (s *Solver) CountMemo() *big.Int {
+	index := map[*Node]int{}
+	s.matrix.forEachCol(func(col *Node) {
+		index[col] = len(index)
+	})
+	O := s.matrix.NewSolution()
+	s.matrix.begin(O)
+	// copy since the count may be the shared constant
+	return new(big.Int).Set(s.matrix.countMemo(O, index, map[string]*big.Int{}))
+}
+
+// Returns a key identifying the set of remaining columns, primary or secondary,
+// followed by the colors the rows of O gave to the colored columns.
+func (m *SparseMatrix) frontier(O *Solution, index map[*Node]int) string {
+	key := make([]byte, (len(index)+7)/8)
+	m.forEachCol(func(col *Node) {
+		i := index[col]
+		key[i/8] |= 1 << uint(i%8)
+	})
+	// a purifying node keeps its color, the later agreeing ones being at -1
+	colors := make([]int, len(index))
+	for _, r := range *O {
+		for _, n := range r.RowNodes() {
+			if n.Color > 0 {
+				colors[index[n.Col]] = n.Color
+			}
+		}
+	}
+	for i, c := range colors {
+		if c > 0 {
+			key = binary.AppendUvarint(key, uint64(i))
+			key = binary.AppendUvarint(key, uint64(c))
+		}
+	}
+	return string(key)
+}
+
+func (m *SparseMatrix) countMemo(O *Solution, index map[*Node]int, cache map[string]*big.Int) *big.Int {
+	root := m.Root()
+	if root.Right == root {
+		return one
+	}
+	key := m.frontier(O, index)
+	if count, ok := cache[key]; ok {
+		return count
+	}
+	count := new(big.Int)
+	m.descend(O, len(*O), descent{visit: func(*Node) bool {
+		count.Add(count, m.countMemo(O, index, cache))
+		return false
+	}})
+	// the count of an aborted subproblem is partial
+	if !m.aborted {
+		cache[key] = count
+	}
+	return count
+}
@@ -4,27 +4,40 @@ The paper can be found at: http://www-cs-faculty.stanford.edu/~knuth/musings.htm
 search for "Dancing links" in the page.
 
 It also includes tools to solve sudoku using Knuth's algorithm.
+
+A matrix, and the solver embedding it, must only be used by one goroutine at
+a time since the search modifies it in place. Independent solvers share no
+state and are safe to run in parallel, see SolverPool.
 */
 package cover
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	// "log"
+	"log/slog"
+	"runtime/pprof"
 )
 
-//log.SetOutput(ioutil.Discard) // turns off logging
-
 // Used for column nodes to remember their name and size.
 type Meta struct {
 	Size uint
 	Name string
+	// set when the matrix tracks column sizes, see TrackSizes()
+	bucket *bucket
 }
 
 // Element of the four-way linked list.
+// Meta is stored inline so that updating a column size does not dereference
+// another pointer. It is only meaningful for column nodes.
 type Node struct {
 	Right, Up, Left, Down *Node
 	Col                   *Node
-	*Meta
+	// index of the row in the matrix the node was built from
+	Row int
+	// color given by the row to a secondary column, 0 when none, see commit()
+	Color int
+	Meta
 }
 
 // Initializes a node with neighbours pointing to itself.
@@ -40,7 +53,7 @@ func NewNode() *Node {
 // Initializes a column node as a normal node + meta.
 func NewColNode(s string) *Node {
 	n := NewNode()
-	n.Meta = &Meta{Name: s}
+	n.Name = s
 	return n
 }
 
@@ -53,9 +66,16 @@ func (r *Node) RowAppend(n *Node) {
 	r.Left = n
 }
 
+// Error returned when appending to a node which is not a column header.
+var ErrNotColumn = errors.New("node is not a column header")
+
 // Appends a node to a column by putting it before the current node.
-// Note that the current node has to own meta in order to update the size.
-func (c *Node) ColAppend(n *Node) {
+// Note that the current node has to be a column header in order to update the
+// size, otherwise ErrNotColumn is returned and nothing is appended.
+func (c *Node) ColAppend(n *Node) error {
+	if c.Col != nil {
+		return ErrNotColumn
+	}
 	n.Col = c
 	n.Down = c
 	n.Up = c.Up
@@ -63,6 +83,47 @@ func (c *Node) ColAppend(n *Node) {
 	c.Up.Down = n
 	c.Up = n
 	c.Size++
+	return nil
+}
+
+// Returns the node followed by the other nodes of its row, from left to right.
+func (n *Node) RowNodes() []*Node {
+	nodes := make([]*Node, 0, 4)
+	n.ForEachInRow(func(m *Node) {
+		nodes = append(nodes, m)
+	})
+	return nodes
+}
+
+// Calls f on the node and on the other nodes of its row, from left to right.
+func (n *Node) ForEachInRow(f func(*Node)) {
+	f(n)
+	for m := n.Right; m != n; m = m.Right {
+		f(m)
+	}
+}
+
+// Returns the nodes of the column from top to bottom, starting at the node.
+// The column header is skipped.
+func (n *Node) ColNodes() []*Node {
+	nodes := make([]*Node, 0)
+	n.ForEachInCol(func(m *Node) {
+		nodes = append(nodes, m)
+	})
+	return nodes
+}
+
+// Calls f on the nodes of the column from top to bottom, starting at the node.
+// The column header is skipped.
+func (n *Node) ForEachInCol(f func(*Node)) {
+	if n.Col != nil {
+		f(n)
+	}
+	for m := n.Down; m != n; m = m.Down {
+		if m.Col != nil {
+			f(m)
+		}
+	}
 }
 
 func (n *Node) String() string {
@@ -72,14 +133,22 @@ func (n *Node) String() string {
 // Reduces the matrix in a non-destructive way by hiding the column
 // from the matrix headers as well as the intersecting rows.
 func (c *Node) Cover() {
-	// log.Println("Cover col", c.Name)
 	c.Right.Left = c.Left
 	c.Left.Right = c.Right
+	if c.bucket != nil {
+		c.bucket.remove(c)
+	}
 	for i := c.Down; i != c; i = i.Down {
 		for j := i.Right; j != i; j = j.Right {
 			j.Down.Up = j.Up
 			j.Up.Down = j.Down
-			j.Col.Size--
+			if b := j.Col.bucket; b != nil {
+				b.remove(j.Col)
+				j.Col.Size--
+				b.insert(j.Col)
+			} else {
+				j.Col.Size--
+			}
 		}
 	}
 }
@@ -87,14 +156,22 @@ func (c *Node) Cover() {
 // Expands the matrix bz restoring the columns and its intersecting rows.
 // Beware that the order is important to properly undo a Cover() step.
 func (c *Node) Uncover() {
-	// log.Println("Uncover col", c.Name)
 	for i := c.Up; i != c; i = i.Up {
 		for j := i.Left; j != i; j = j.Left {
-			j.Col.Size++
+			if b := j.Col.bucket; b != nil {
+				b.remove(j.Col)
+				j.Col.Size++
+				b.insert(j.Col)
+			} else {
+				j.Col.Size++
+			}
 			j.Down.Up = j
 			j.Up.Down = j
 		}
 	}
+	if c.bucket != nil {
+		c.bucket.insert(c)
+	}
 	c.Right.Left = c
 	c.Left.Right = c
 }
@@ -102,6 +179,57 @@ func (c *Node) Uncover() {
 // Embeds the root node to provide a clean interface.
 type SparseMatrix struct {
 	*Node
+	sizes *sizeIndex
+	units bool
+	stats Stats
+	// set by Abort() to unwind the search
+	aborted bool
+	hooks   []Hook
+	// pprof labels of the searches, nil when not profiled
+	labels []string
+	// solution of the search in progress, for progress reports
+	current *Solution
+	// header of the secondary columns, nil when there is none
+	secondary *Node
+	// rows removed from the matrix, see Simplify()
+	hidden []*Node
+	// failed sets of rows, nil when not recorded
+	nogoods *Nogoods
+	// budgets of the searches, zero when unlimited, see SetNodeLimit()
+	nodeLimit  int64
+	depthLimit int
+	// rows tried by the search in progress, and whether a budget ran out
+	tried   int64
+	limited bool
+	// depth of the node choosing its column, for the covers of the lookahead
+	level int
+	// checks the links after every uncover, see SelfCheck()
+	selfCheck bool
+	// diagnostics of the searches, nil when not traced
+	trace *Trace
+	// structured logging of the searches, nil when not logged, and whether
+	// the search in progress logs its debug records, see SetLogger()
+	logger   *slog.Logger
+	logDebug bool
+	// logger of the search in progress, with the name and tags
+	logSearch *slog.Logger
+	// visits the same nodes on every run, see SetDeterministic()
+	deterministic bool
+	// decisions of the searches, nil when not journaled, and the point the
+	// next search resumes from, nil when starting afresh, see Resume()
+	journal *Journal
+	resume  *resumePoint
+	// smallest columns looked ahead, 0 when disabled, see Lookahead()
+	lookahead int
+	// named sets of columns, nil when there is none, see Group()
+	groups *columnGroups
+	// name and tags of the problem, see SetName()
+	name string
+	tags map[string]string
+	// context of the search in progress, nil when not cancelable
+	ctx context.Context
+	// counts at build time, for memory reporting
+	nodes, cols int
 }
 
 /*
@@ -121,7 +249,7 @@ double linked nodes for 1 values.
 func NewSparseMatrix(matrix [][]int, headers []string) *SparseMatrix {
 	rowCount := len(matrix)
 	colCount := len(headers)
-	root := &Node{Meta: &Meta{Name: "root"}}
+	root := &Node{Meta: Meta{Name: "root"}}
 	root.Left = root
 	root.Right = root
 	// create the columns
@@ -129,12 +257,15 @@ func NewSparseMatrix(matrix [][]int, headers []string) *SparseMatrix {
 		head := NewColNode(h)
 		root.RowAppend(head)
 	}
+	nodes := 0
 	for i := 0; i < rowCount; i++ {
 		var prev, head *Node
 		head = root.Right
 		for j := 0; j < colCount; j++ {
 			if matrix[i][j] > 0 {
+				nodes++
 				node := NewNode()
+				node.Row = i
 				head.ColAppend(node)
 				if prev != nil {
 					prev.RowAppend(node)
@@ -145,12 +276,67 @@ func NewSparseMatrix(matrix [][]int, headers []string) *SparseMatrix {
 			head = head.Right
 		}
 	}
-	return &SparseMatrix{root}
+	return &SparseMatrix{Node: root, nodes: nodes, cols: colCount}
+}
+
+// Converts a binary matrix to rows listing the indexes of their columns.
+func SparseRows(matrix [][]int) [][]int {
+	rows := make([][]int, len(matrix))
+	for i, line := range matrix {
+		rows[i] = make([]int, 0)
+		for j, v := range line {
+			if v > 0 {
+				rows[i] = append(rows[i], j)
+			}
+		}
+	}
+	return rows
+}
+
+// Builds a sparse matrix from rows listing the indexes of their columns, for
+// problems too big to be given as a dense binary matrix.
+func NewSparseMatrixFromRows(rows [][]int, headers []string) *SparseMatrix {
+	root := &Node{Meta: Meta{Name: "root"}}
+	root.Left = root
+	root.Right = root
+	cols := make([]*Node, len(headers))
+	for i, h := range headers {
+		cols[i] = NewColNode(h)
+		root.RowAppend(cols[i])
+	}
+	nodes := 0
+	for i, row := range rows {
+		var first *Node
+		for _, j := range row {
+			nodes++
+			node := NewNode()
+			node.Row = i
+			cols[j].ColAppend(node)
+			if first != nil {
+				first.RowAppend(node)
+			} else {
+				first = node
+			}
+		}
+	}
+	return &SparseMatrix{Node: root, nodes: nodes, cols: len(headers)}
 }
 
 // Returns the column having the smallest number of intersecting rows.
 // It used to reduce the branching in the Search() method.
+// The columns of the preferred group come first, see PreferGroup().
 func (m *SparseMatrix) SmallestCol() *Node {
+	if m.groups != nil && m.groups.preferred != nil {
+		if c := m.groups.smallestPreferred(); c != nil {
+			return c
+		}
+	}
+	if m.lookahead > 0 {
+		return m.lookaheadCol()
+	}
+	if m.sizes != nil {
+		return m.sizes.smallest()
+	}
 	var r *Node
 	min := ^uint(0)
 	// we want the underlying node rather than the matrix for comparison
@@ -164,6 +350,21 @@ func (m *SparseMatrix) SmallestCol() *Node {
 	return r
 }
 
+// Tells whether a column has no more intersecting rows, in which case
+// the matrix cannot be covered anymore.
+func (m *SparseMatrix) DeadEnd() bool {
+	if m.sizes != nil {
+		return m.sizes.heads[0] != nil
+	}
+	root := m.Root()
+	for col := root.Right; col != root; col = col.Right {
+		if col.Size == 0 {
+			return true
+		}
+	}
+	return false
+}
+
 // Get the root element of the matrix.
 func (m *SparseMatrix) Root() *Node {
 	return m.Left.Right
@@ -171,41 +372,167 @@ func (m *SparseMatrix) Root() *Node {
 
 // Returns the column of the specified name. Panics it not found.
 func (m *SparseMatrix) Col(name string) *Node {
+	if col := m.findCol(name); col != nil {
+		return col
+	}
+	panic(fmt.Sprintf("Column \"%v\" not found", name))
+}
+
+// Returns the column of the specified name, or nil if not found.
+func (m *SparseMatrix) findCol(name string) *Node {
 	root := m.Root()
 	for col := root.Right; col != root; col = col.Right {
 		if col.Name == name {
 			return col
 		}
 	}
-	panic(fmt.Sprintf("Column \"%v\" not found", name))
+	if m.secondary != nil {
+		for col := m.secondary.Right; col != m.secondary; col = col.Right {
+			if col.Name == name {
+				return col
+			}
+		}
+	}
+	return nil
+}
+
+// Returns the number of columns left in the headers. Since every row covers
+// at least one column, it also bounds the number of rows of a solution.
+func (m *SparseMatrix) ColCount() int {
+	n := 0
+	root := m.Root()
+	for col := root.Right; col != root; col = col.Right {
+		n++
+	}
+	return n
+}
+
+// Returns an empty solution able to hold any solution without growing.
+func (m *SparseMatrix) NewSolution() *Solution {
+	O := make(Solution, 0, m.ColCount())
+	return &O
 }
 
 // Heart of the DLX algorithm.
 func (m *SparseMatrix) Search(O *Solution, k int, g Guesser) {
-	// log.Println(k)
+	m.aborted = false
+	m.tried, m.limited = 0, false
+	m.current = O
+	if m.logger != nil {
+		m.logStart()
+		defer m.logEnd()
+	}
+	defer m.traceEnd()
+	m.journalStart()
+	defer m.journalEnd()
+	if m.labels == nil {
+		m.search(O, k, g)
+		return
+	}
+	labels := append(m.labels, "engine", "pointer", "heuristic", m.heuristic())
+	labels = append(labels, m.tagPairs()...)
+	pprof.Do(context.Background(), pprof.Labels(labels...), func(context.Context) {
+		m.search(O, k, g)
+	})
+}
+
+func (m *SparseMatrix) search(O *Solution, k int, g Guesser) {
+	if !m.units {
+		m.branch(O, k, g)
+		return
+	}
+	forced := m.Propagate(O, k)
+	if !m.DeadEnd() {
+		m.branch(O, k+forced, g)
+		if g.Terminate() {
+			return
+		}
+	}
+	m.Unpropagate(O, k, forced)
+}
+
+// Chooses a column and tries each of its rows in turn.
+func (m *SparseMatrix) branch(O *Solution, k int, g Guesser) {
 	root := m.Root()
 	if root.Right == root {
+		// drop rows left over from deeper branches explored earlier
+		*O = (*O)[:k]
+		if m.resume.skips(O) {
+			return
+		}
+		m.stats.Solutions++
+		if m.trace != nil {
+			m.trace.Printf("solution %v", O.Rows())
+		}
+		if m.logDebug {
+			m.logSolution(O)
+		}
+		m.journalSolution(O)
 		g.Eureka(O)
 		return
 	}
+	if m.depthLimit > 0 && k >= m.depthLimit {
+		m.limited = true
+		m.Abort()
+		return
+	}
+	m.level = k
 	c := g.ChooseCol(k)
-	c.Cover()
-	for r := c.Down; r != c; r = r.Down {
+	if m.groups != nil {
+		m.groups.branched(c)
+	}
+	m.cover(c, k)
+	m.stats.level(k)
+	for r := m.resume.start(c, k); r != c && !m.aborted; r = r.Down {
+		if m.spent() {
+			break
+		}
+		m.stats.Levels[k].Candidates++
+		found := m.stats.Solutions
 		O.Set(k, r)
+		if m.journal != nil {
+			m.journal.printf("try %v %v", k, r.Row)
+		}
 		for j := r.Right; j != r; j = j.Right {
-			j.Col.Cover()
+			m.commit(j, k)
 		}
-		m.Search(O, k+1, g)
-		if g.Terminate() {
-			return
+		// no need to go deeper if a column can no longer be covered
+		searched := !m.DeadEnd() && !m.nogoods.prunes(O, k)
+		if searched {
+			m.search(O, k+1, g)
+			if g.Terminate() {
+				return
+			}
+		}
+		if m.stats.Solutions == found {
+			m.stats.Levels[k].Failures++
+			if searched && !m.aborted {
+				m.nogoods.record(O, k)
+			}
 		}
 		r = O.Get(k)
 		c = r.Col
 		for j := r.Left; j != r; j = j.Left {
-			j.Col.Uncover()
+			m.uncommit(j, k)
 		}
+		if m.journal != nil && !m.aborted {
+			m.journal.printf("back %v %v", k, r.Row)
+		}
+		m.resume.left(k + 1)
 	}
-	c.Uncover()
+	m.uncover(c, k)
+}
+
+// Stops the search in progress, which then restores the matrix as if it had
+// been exhausted. Meant to be called from a guesser, e.g. when a budget runs out,
+// whose Terminate() must then return false to let the search unwind.
+func (m *SparseMatrix) Abort() {
+	m.aborted = true
+}
+
+// Tells whether the last search was aborted. Starting a new search clears it.
+func (m *SparseMatrix) Aborted() bool {
+	return m.aborted
 }
 
 // A guesser is an object able to choose a specific column for the DLX algorithm.
@@ -218,26 +545,73 @@ type Guesser interface {
 }
 
 // Embeds a sparse matrix to provide clean interface.
+// A solver is not safe for concurrent use.
 type Solver struct {
 	matrix    *SparseMatrix
 	Solutions []*Solution
+	decoder   Decoder
+	backend   Backend
+	sat       SATSolver
+	// search in progress of Run()
+	run *Session
+	// rows of the solution forgotten by Reset(), reused by Solve()
+	spare *Solution
 }
 
-func NewSolver(m [][]int, h []string) *Solver {
+// Creates a solver for the binary matrix m with headers h, applying the
+// options in order.
+func NewSolver(m [][]int, h []string, opts ...Option) *Solver {
 	s := Solver{matrix: NewSparseMatrix(m, h), Solutions: make([]*Solution, 0, 1)}
+	for _, opt := range opts {
+		opt(&s)
+	}
 	return &s
 }
 func (s *Solver) Solve() *Solution {
-	O := new(Solution)
+	if s.backend != DLX {
+		return s.solveWith(s.backend)
+	}
+	s.matrix.ResetStats()
+	O := s.spare
+	if O != nil {
+		*O, s.spare = (*O)[:0], nil
+	} else {
+		O = s.matrix.NewSolution()
+	}
 	s.matrix.Search(O, 0, s)
 	return O
 }
 
+// Guesser collecting a copy of every solution.
+type collector struct {
+	matrix    *SparseMatrix
+	solutions []*Solution
+}
+
+func (c *collector) ChooseCol(k int) *Node {
+	return c.matrix.SmallestCol()
+}
+func (c *collector) Eureka(O *Solution) {
+	s := append(Solution{}, *O...)
+	c.solutions = append(c.solutions, &s)
+}
+func (c *collector) Terminate() bool {
+	return false
+}
+
+// Returns all the solutions by exhausting the search tree. Unlike Solve(),
+// the matrix is restored afterwards.
+func (s *Solver) SolveAll() []*Solution {
+	s.matrix.ResetStats()
+	c := &collector{matrix: s.matrix, solutions: make([]*Solution, 0)}
+	s.matrix.Search(s.matrix.NewSolution(), 0, c)
+	return c.solutions
+}
+
 // Chooses the column havng the smallest number of interesecting rows and always
 // asks for backtracking.
 func (s *Solver) ChooseCol(k int) *Node {
 	m := s.matrix
-	// log.Println("guess is", m.SmallestCol().Name, "(", m.SmallestCol().Size, "), bt", true)
 	return m.SmallestCol()
 }
 func (s *Solver) Eureka(O *Solution) {
@@ -247,6 +621,23 @@ func (s *Solver) Terminate() bool {
 	return len(s.Solutions) > 0
 }
 
+// Restores the matrix left covered by the solution found by the last Solve()
+// or Run(), or by a search of Run() in progress, and forgets the solutions,
+// so that the solver can be used again. The next Solve() reuses the rows of
+// the last solution, which must be copied to be kept.
+func (s *Solver) Reset() {
+	if s.run != nil {
+		s.run.Reset()
+		s.run = nil
+	}
+	if len(s.Solutions) > 0 {
+		O := s.Solutions[len(s.Solutions)-1]
+		s.matrix.Unpropagate(O, 0, O.Len())
+		s.spare = O
+	}
+	s.Solutions = s.Solutions[:0]
+}
+
 // Aliases a Node pointer array to provide a nice interface.
 type Solution []*Node
 
@@ -268,9 +659,11 @@ func (s *Solution) String() string {
 	o := ""
 	for _, n := range *s {
 		if n != nil {
-			o += n.Col.Name
-			for m := n.Right; n != m; m = m.Right {
-				o += " " + m.Col.Name
+			for i, m := range n.RowNodes() {
+				if i > 0 {
+					o += " "
+				}
+				o += m.Col.Name
 			}
 			o += "\n"
 		}
@@ -2,6 +2,8 @@ package cover
 
 import (
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
 )
 
@@ -91,6 +93,17 @@ func TestColAppend(t *testing.T) {
 	}
 }
 
+func knuth() [][]int {
+	knuth := make([][]int, 6)
+	knuth[0] = []int{0, 0, 1, 0, 1, 1, 0}
+	knuth[1] = []int{1, 0, 0, 1, 0, 0, 1}
+	knuth[2] = []int{0, 1, 1, 0, 0, 1, 0}
+	knuth[3] = []int{1, 0, 0, 1, 0, 0, 0}
+	knuth[4] = []int{0, 1, 0, 0, 0, 0, 1}
+	knuth[5] = []int{0, 0, 0, 1, 1, 0, 1}
+	return knuth
+}
+
 func TestSolve(t *testing.T) {
 	knuth := make([][]int, 6)
 	knuth[0] = []int{0, 0, 1, 0, 1, 1, 0}
@@ -110,3 +123,324 @@ func TestSolve(t *testing.T) {
 		t.Errorf("Wrong solution to Knuth example cover problem: %v", solution)
 	}
 }
+
+func TestCount(t *testing.T) {
+	m := [][]int{
+		{1, 0, 0},
+		{0, 1, 0},
+		{0, 0, 1},
+		{1, 1, 1},
+		{0, 1, 1},
+	}
+	solver := NewSolver(m, []string{"A", "B", "C"})
+	if count := solver.Count(); count.Int64() != 3 {
+		t.Errorf("Matrix has exactly 3 solutions, %v found", count)
+	}
+}
+
+func TestCountMemo(t *testing.T) {
+	// domino tilings of a 4x4 board
+	headers := make([]string, 16)
+	for i := range headers {
+		headers[i] = fmt.Sprint(i)
+	}
+	m := make([][]int, 0)
+	for i := 0; i < 16; i++ {
+		if i%4 < 3 {
+			row := make([]int, 16)
+			row[i], row[i+1] = 1, 1
+			m = append(m, row)
+		}
+		if i < 12 {
+			row := make([]int, 16)
+			row[i], row[i+4] = 1, 1
+			m = append(m, row)
+		}
+	}
+	if count := NewSolver(m, headers).CountMemo(); count.Int64() != 36 {
+		t.Errorf("4x4 board has 36 domino tilings, %v found", count)
+	}
+	if count := NewSolver(m, headers).Count(); count.Int64() != 36 {
+		t.Errorf("4x4 board has 36 domino tilings, %v found", count)
+	}
+}
+
+func TestCountMemoColors(t *testing.T) {
+	// both rows of a cover it, leaving b and c, but x:g hides a row of c
+	spec, err := LoadSpec(strings.NewReader(`{
+		"items": [{"name": "a"}, {"name": "b"}, {"name": "c"},
+			{"name": "x", "secondary": true, "colors": ["r", "g"]}],
+		"options": [{"items": ["a", "x:r"]}, {"items": ["a", "x:g"]}, {"items": ["b"]}, {"items": ["b"]},
+			{"items": ["c", "x:r"]}, {"items": ["c"]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	solver, err := spec.Solver()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count := solver.Count(); count.Int64() != 6 {
+		t.Errorf("Colored spec has %v solutions (wants 6)", count)
+	}
+	if count := solver.CountMemo(); count.Int64() != 6 {
+		t.Errorf("Colored spec has %v solutions with memo (wants 6)", count)
+	}
+	solver, _ = coloredSpec(t).Solver()
+	if count := solver.CountMemo(); count.Int64() != 1 {
+		t.Errorf("Colored spec has %v solutions with memo (wants 1)", count)
+	}
+}
+
+func TestTrackSizes(t *testing.T) {
+	m, h := GraecoLatinConstraintMatrix(3)
+	scan := NewSolver(m, h).Count()
+	solver := NewSolver(m, h)
+	solver.matrix.TrackSizes()
+	if count := solver.Count(); count.Cmp(scan) != 0 {
+		t.Errorf("Tracking sizes finds %v solutions (wants %v)", count, scan)
+	}
+	root := solver.matrix.Root()
+	for col := root.Right; col != root; col = col.Right {
+		if col.bucket.index.heads[col.Size] == nil {
+			t.Errorf("Column %v missing from bucket %v after search", col.Name, col.Size)
+		}
+	}
+}
+
+func TestPropagateUnits(t *testing.T) {
+	m, h := GraecoLatinConstraintMatrix(3)
+	for _, track := range []bool{false, true} {
+		solver := NewSolver(m, h)
+		if track {
+			solver.matrix.TrackSizes()
+		}
+		solver.matrix.PropagateUnits()
+		if count := solver.Count(); count.Int64() != 72 {
+			t.Errorf("Propagating units finds %v solutions (wants %v)", count, 72)
+		}
+		if cols := solver.matrix.ColCount(); cols != len(h) {
+			t.Errorf("Matrix has %v columns after search (wants %v)", cols, len(h))
+		}
+	}
+}
+
+func TestSolverPool(t *testing.T) {
+	m, h := GraecoLatinConstraintMatrix(4)
+	pool := NewSolverPool(m, h)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 3; j++ {
+				s := pool.Get()
+				if O := s.Solve(); O.Len() != 16 {
+					t.Errorf("Solution has %v rows (wants %v)", O.Len(), 16)
+				}
+				s.Reset()
+				if cols := s.matrix.ColCount(); cols != len(h) {
+					t.Errorf("Matrix has %v columns after reset (wants %v)", cols, len(h))
+				}
+				pool.Put(s)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestColAppendNotColumn(t *testing.T) {
+	c := NewColNode("The col")
+	p := NewNode()
+	c.ColAppend(p)
+	if err := p.ColAppend(NewNode()); err != ErrNotColumn {
+		t.Errorf("Appending to a row node returns %v (wants %v)", err, ErrNotColumn)
+	}
+	if c.Size != 1 || p.Down != c {
+		t.Errorf("Appending to a row node modified the column")
+	}
+}
+
+func TestRowColNodes(t *testing.T) {
+	m := NewSparseMatrix([][]int{{1, 1, 0}, {0, 1, 1}, {1, 1, 1}}, []string{"A", "B", "C"})
+	b := m.Col("B")
+	col := b.ColNodes()
+	if len(col) != 3 {
+		t.Fatalf("Column B has %v nodes (wants %v)", len(col), 3)
+	}
+	if from := col[1].ColNodes(); len(from) != 3 || from[0] != col[1] || from[2] != col[0] {
+		t.Errorf("Column nodes from the middle are not in order: %v", from)
+	}
+	row := col[2].RowNodes()
+	if len(row) != 3 || row[0].Col != b || row[1].Col.Name != "C" || row[2].Col.Name != "A" {
+		t.Errorf("Row nodes are not in order: %v", row)
+	}
+}
+
+func TestStats(t *testing.T) {
+	solver := dominoes(4, 4)
+	solver.Count()
+	stats := solver.Stats()
+	if stats.Solutions != 36 {
+		t.Errorf("Stats report %v solutions (wants %v)", stats.Solutions, 36)
+	}
+	if len(stats.Levels) != 8 {
+		t.Errorf("Stats report %v levels (wants %v)", len(stats.Levels), 8)
+	}
+	if last := stats.Levels[7]; last.Candidates != 36 || last.Failures != 0 {
+		t.Errorf("Last level has %v candidates and %v failures (wants 36 and 0)", last.Candidates, last.Failures)
+	}
+}
+
+func TestLuby(t *testing.T) {
+	expected := []int64{1, 1, 2, 1, 1, 2, 4, 1, 1, 2, 1, 1, 2, 4, 8, 1}
+	for i, l := range expected {
+		if got := luby(int64(i + 1)); got != l {
+			t.Errorf("Luby term %v is %v (wants %v)", i+1, got, l)
+		}
+	}
+}
+
+func TestSolveWithRestarts(t *testing.T) {
+	m, h := GraecoLatinConstraintMatrix(4)
+	solver := NewSolver(m, h)
+	if O := solver.SolveWithRestarts(2, 1); O.Len() != 16 {
+		t.Errorf("Solution has %v rows (wants %v)", O.Len(), 16)
+	}
+	solver.Reset()
+	if cols := solver.matrix.ColCount(); cols != len(h) {
+		t.Errorf("Matrix has %v columns after restarts (wants %v)", cols, len(h))
+	}
+	m, h = GraecoLatinConstraintMatrix(2)
+	if O := NewSolver(m, h).SolveWithRestarts(1, 1); O.Len() != 0 {
+		t.Errorf("Order 2 has no Graeco-Latin square, found %v", O)
+	}
+}
+
+func TestSolveWithRestartsAborted(t *testing.T) {
+	m, h := GraecoLatinConstraintMatrix(2)
+	for _, opt := range []Option{WithDepthLimit(1), WithNodeLimit(3)} {
+		solver := NewSolver(m, h, opt)
+		if O := solver.SolveWithRestarts(1, 1); O.Len() != 0 || !solver.LimitReached() {
+			t.Errorf("Limited restarts return %v, limit reached %v", O, solver.LimitReached())
+		}
+	}
+	calls := 0
+	solver := NewSolver(m, h, WithProgress(0, func(Progress) bool {
+		calls++
+		return false
+	}))
+	if O := solver.SolveWithRestarts(1, 1); O.Len() != 0 || calls != 1 {
+		t.Errorf("Interrupted restarts return %v after %v progress calls", O, calls)
+	}
+	if cols := solver.matrix.ColCount(); cols != len(h) {
+		t.Errorf("Matrix has %v columns after restarts (wants %v)", cols, len(h))
+	}
+}
+
+func TestOrderColumns(t *testing.T) {
+	m, h := GraecoLatinConstraintMatrix(3)
+	for _, o := range []ColumnOrder{AsBuilt, BySize, ByConnectivity} {
+		solver := NewSolver(m, h, WithSizeTracking(), WithColumnOrder(o))
+		if count := solver.Count(); count.Int64() != 72 {
+			t.Errorf("Order %v finds %v solutions (wants %v)", o, count, 72)
+		}
+	}
+	solver := NewSolver([][]int{{1, 1, 1}, {0, 1, 0}, {0, 1, 1}}, []string{"A", "B", "C"}, WithColumnOrder(BySize))
+	names := ""
+	root := solver.matrix.Root()
+	for col := root.Right; col != root; col = col.Right {
+		names += col.Name
+	}
+	if names != "ACB" {
+		t.Errorf("Columns ordered by size are %v (wants %v)", names, "ACB")
+	}
+}
+
+func TestOrderRows(t *testing.T) {
+	m := [][]int{{1, 0}, {0, 1}, {1, 1}}
+	h := []string{"A", "B"}
+	// the full row first
+	solver := NewSolver(m, h, WithRowOrder(ByRowCost([]float64{2, 2, 1})))
+	if rows := solver.Solve().Rows(); len(rows) != 1 || rows[0] != 2 {
+		t.Errorf("Cheapest solution is %v (wants [2])", rows)
+	}
+	solver = NewSolver(m, h, WithRandomRows(3), WithRowOrder(ByRowIndex))
+	if rows := solver.Solve().Rows(); len(rows) != 2 {
+		t.Errorf("First solution as built is %v (wants [0 1])", rows)
+	}
+	if count := NewSolver(m, h, WithRandomRows(5)).Count(); count.Int64() != 2 {
+		t.Errorf("Shuffled matrix has %v solutions (wants %v)", count, 2)
+	}
+}
+
+func TestHooks(t *testing.T) {
+	depth := map[string]int{}
+	covered := 0
+	trace := func(e Event, col *Node, k int) {
+		if e == CoverEvent {
+			covered++
+			depth[col.Name] = k
+		} else {
+			covered--
+		}
+	}
+	solver := NewSolver(knuth(), []string{"A", "B", "C", "D", "E", "F", "G"}, WithHook(trace))
+	solver.Solve()
+	if covered != 7 {
+		t.Errorf("Hooks report %v columns covered by the solution (wants %v)", covered, 7)
+	}
+	solver.Reset()
+	if covered != 0 {
+		t.Errorf("Hooks report %v columns covered after reset (wants %v)", covered, 0)
+	}
+	if depth["A"] != 0 || depth["B"] != 2 {
+		t.Errorf("Columns A and B were covered at depth %v and %v (wants 0 and 2)", depth["A"], depth["B"])
+	}
+}
+
+func TestHooksAbort(t *testing.T) {
+	solver := NewSolver(knuth(), []string{"A", "B", "C", "D", "E", "F", "G"})
+	solver.matrix.AddHook(func(e Event, col *Node, k int) {
+		if k > 0 {
+			solver.matrix.Abort()
+		}
+	})
+	if O := solver.Solve(); len(solver.Solutions) != 0 || !solver.matrix.Aborted() {
+		t.Errorf("Aborted search found %v", O)
+	}
+	if cols := solver.matrix.ColCount(); cols != 7 {
+		t.Errorf("Matrix has %v columns after abort (wants %v)", cols, 7)
+	}
+}
+
+func TestLabels(t *testing.T) {
+	solver := NewSolver(knuth(), []string{"A", "B", "C", "D", "E", "F", "G"}, WithSizeTracking(), WithLabels("knuth", "tenant", "test"))
+	if h := solver.matrix.heuristic(); h != "buckets" {
+		t.Errorf("Heuristic label is %v (wants %v)", h, "buckets")
+	}
+	if O := solver.Solve(); O.Len() != 3 {
+		t.Errorf("Labeled search found %v", O)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	solver := NewSolver(knuth(), []string{"A", "B", "C", "D", "E", "F", "G"})
+	objs := solver.SolveDecoded()
+	if fmt.Sprint(objs) != "[[A D] [E F C] [B G]]" {
+		t.Errorf("Solution decodes to %v (wants %v)", objs, "[[A D] [E F C] [B G]]")
+	}
+	solver.Reset()
+	solver.SetDecoder(DecoderFunc(func(row *Node) interface{} {
+		return row.Row
+	}))
+	if all := solver.SolveAllDecoded(); fmt.Sprint(all) != "[[3 0 4]]" {
+		t.Errorf("Solutions decode to %v (wants %v)", all, "[[3 0 4]]")
+	}
+	// the empty solution of a matrix without columns is no missing solution
+	if objs := NewSolver(nil, nil).SolveDecoded(); objs == nil || len(objs) != 0 {
+		t.Errorf("Empty solution decodes to %#v", objs)
+	}
+	if objs := NewSolver([][]int{{1, 0}}, []string{"A", "B"}).SolveDecoded(); objs != nil {
+		t.Errorf("Missing solution decodes to %v", objs)
+	}
+}
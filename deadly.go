@@ -0,0 +1,156 @@
+package cover
+
+import (
+	"sort"
+)
+
+// Set of cells whose digits differ between two completions of a draft: the
+// digits of one can be permuted into the other, so a puzzle having a unique
+// solution needs a given among these cells.
+type DeadlyPattern struct {
+	// cells with their digit in the first completion
+	Cells []SudokuCell
+	// 4 cells in 2 rows, 2 columns and 2 boxes holding 2 digits
+	Rectangle bool
+}
+
+// Analysis of a puzzle draft, see AnalyzeDraft().
+type DraftReport struct {
+	// completions found, up to the bound
+	Solutions int
+	// the completions were all enumerated, so that the count is exact
+	Exhaustive bool
+	// differences between the first completion and the others, smallest
+	// first, without duplicates
+	Patterns []DeadlyPattern
+	// givens of the first completion which remove all the other completions
+	// found, chosen greedily among the cells of the patterns
+	Fixes []SudokuCell
+}
+
+// Guesser collecting up to max grids, aborting the search after the last.
+type gridCollector struct {
+	solver *SudokuSolver
+	max    int
+	grids  [][][]int
+}
+
+func (g *gridCollector) ChooseCol(k int) *Node {
+	return g.solver.matrix.SmallestCol()
+}
+func (g *gridCollector) Eureka(O *Solution) {
+	g.grids = append(g.grids, g.solver.Grid(O))
+	if len(g.grids) >= g.max {
+		g.solver.matrix.Abort()
+	}
+}
+func (g *gridCollector) Terminate() bool {
+	return false
+}
+
+// Reports the risks of multiple solutions of a draft, 0 being an empty cell,
+// for setters to fix before publishing: enumerates up to max completions and
+// returns the deadly patterns telling the first completion from the others,
+// flagging unique rectangles, with givens removing them. A draft with a
+// unique solution has no pattern, nor has one with no solution. The matrix is
+// restored afterwards.
+func (s *SudokuSolver) AnalyzeDraft(draft [][]int, max int) *DraftReport {
+	s.Reset()
+	m := s.matrix
+	report := &DraftReport{}
+	O := m.NewSolution()
+	rows := s.givens(draft)
+	if rows == nil {
+		return report
+	}
+	k, err := m.Require(O, rows...)
+	if err != nil {
+		return report
+	}
+	g := &gridCollector{solver: s, max: max}
+	m.Search(O, k, g)
+	m.Release(O, k)
+	report.Solutions = len(g.grids)
+	report.Exhaustive = !m.Aborted()
+	if len(g.grids) < 2 {
+		return report
+	}
+	first := g.grids[0]
+	seen := map[string]bool{}
+	for _, other := range g.grids[1:] {
+		p := DeadlyPattern{}
+		cells := make([]int, 0)
+		for i, line := range first {
+			for j, digit := range line {
+				if other[i][j] != digit {
+					p.Cells = append(p.Cells, SudokuCell{i, j, digit})
+					cells = append(cells, i*s.Dim+j)
+				}
+			}
+		}
+		if key := cellsKey(cells); !seen[key] {
+			seen[key] = true
+			p.Rectangle = s.rectangle(p.Cells)
+			report.Patterns = append(report.Patterns, p)
+		}
+	}
+	sort.SliceStable(report.Patterns, func(a, b int) bool {
+		return len(report.Patterns[a].Cells) < len(report.Patterns[b].Cells)
+	})
+	report.Fixes = fixes(report.Patterns)
+	return report
+}
+
+// Tells whether the cells form a unique rectangle.
+func (s *SudokuSolver) rectangle(cells []SudokuCell) bool {
+	if len(cells) != 4 {
+		return false
+	}
+	sdim := 1
+	for sdim*sdim < s.Dim {
+		sdim++
+	}
+	rows, cols, boxes, digits := map[int]bool{}, map[int]bool{}, map[int]bool{}, map[int]bool{}
+	for _, c := range cells {
+		rows[c.X] = true
+		cols[c.Y] = true
+		boxes[c.X/sdim*sdim+c.Y/sdim] = true
+		digits[c.Digit] = true
+	}
+	return len(rows) == 2 && len(cols) == 2 && len(boxes) == 2 && len(digits) == 2
+}
+
+// Chooses cells hitting every pattern, the one hitting the most remaining
+// patterns first, the first in row-major order on ties.
+func fixes(patterns []DeadlyPattern) []SudokuCell {
+	hit := make([]bool, len(patterns))
+	chosen := make([]SudokuCell, 0)
+	for {
+		count := map[SudokuCell]int{}
+		for i, p := range patterns {
+			if !hit[i] {
+				for _, c := range p.Cells {
+					count[c]++
+				}
+			}
+		}
+		if len(count) == 0 {
+			return chosen
+		}
+		var best SudokuCell
+		most := 0
+		for c, n := range count {
+			if n > most || n == most && (c.X < best.X || c.X == best.X && c.Y < best.Y) {
+				best, most = c, n
+			}
+		}
+		chosen = append(chosen, best)
+		for i, p := range patterns {
+			for _, c := range p.Cells {
+				if c == best {
+					hit[i] = true
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package cover
+
+import (
+	"testing"
+)
+
+func TestAnalyzeDraft(t *testing.T) {
+	draft := parseGrid("534678912672195348198342567859761423426853791713924856961537284287419635345286179")
+	// a unique rectangle of 6 and 7 in rows 0 and 3
+	for _, c := range [][2]int{{0, 3}, {0, 4}, {3, 3}, {3, 4}} {
+		draft[c[0]][c[1]] = 0
+	}
+	s := NewSudokuSolver(9)
+	r := s.AnalyzeDraft(draft, 10)
+	if r.Solutions != 2 || !r.Exhaustive {
+		t.Fatalf("%v solutions, exhaustive %v (wants 2, true)", r.Solutions, r.Exhaustive)
+	}
+	if len(r.Patterns) != 1 || !r.Patterns[0].Rectangle {
+		t.Fatalf("Patterns %+v (wants a rectangle)", r.Patterns)
+	}
+	if len(r.Fixes) != 1 {
+		t.Fatalf("Fixes %v (wants 1)", r.Fixes)
+	}
+	fix := r.Fixes[0]
+	draft[fix.X][fix.Y] = fix.Digit
+	if r := s.AnalyzeDraft(draft, 10); r.Solutions != 1 || len(r.Patterns) != 0 {
+		t.Errorf("Fixed draft has %v solutions and patterns %v", r.Solutions, r.Patterns)
+	}
+}
+
+func TestAnalyzeDraftBound(t *testing.T) {
+	draft := make([][]int, 4)
+	for i := range draft {
+		draft[i] = make([]int, 4)
+	}
+	s := NewSudokuSolver(4)
+	r := s.AnalyzeDraft(draft, 20)
+	if r.Solutions != 20 || r.Exhaustive {
+		t.Fatalf("%v solutions, exhaustive %v (wants 20, false)", r.Solutions, r.Exhaustive)
+	}
+	if len(r.Patterns) == 0 {
+		t.Fatal("No pattern")
+	}
+	// every pattern holds a fix
+	fixed := map[SudokuCell]bool{}
+	for _, c := range r.Fixes {
+		fixed[c] = true
+	}
+	for _, p := range r.Patterns {
+		hit := false
+		for _, c := range p.Cells {
+			hit = hit || fixed[c]
+		}
+		if !hit {
+			t.Errorf("Pattern %v holds no fix", p.Cells)
+		}
+	}
+}
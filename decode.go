@@ -0,0 +1,62 @@
+package cover
+
+// Turns a row chosen by the search into an object of the problem domain, e.g.
+// a digit placed in a sudoku cell, so that consumers need not parse the column
+// names themselves.
+type Decoder interface {
+	Decode(row *Node) interface{}
+}
+
+// Adapts a function to the Decoder interface.
+type DecoderFunc func(row *Node) interface{}
+
+func (f DecoderFunc) Decode(row *Node) interface{} {
+	return f(row)
+}
+
+// Decodes a row into the names of its columns, starting with the given node.
+// Solvers having no decoder use it.
+var ColumnNames = DecoderFunc(func(row *Node) interface{} {
+	names := make([]string, 0)
+	for _, n := range row.RowNodes() {
+		names = append(names, n.Col.Name)
+	}
+	return names
+})
+
+// Registers the decoder of the solver.
+func (s *Solver) SetDecoder(d Decoder) {
+	s.decoder = d
+}
+
+// Decodes each row of the solution, in order.
+func (s *Solver) Decode(O *Solution) []interface{} {
+	d := s.decoder
+	if d == nil {
+		d = ColumnNames
+	}
+	objs := make([]interface{}, len(*O))
+	for i, r := range *O {
+		objs[i] = d.Decode(r)
+	}
+	return objs
+}
+
+// Same as Solve(), but returns the decoded rows, nil if there is no solution
+// and empty if the solution is empty, e.g. for a matrix without columns.
+func (s *Solver) SolveDecoded() []interface{} {
+	n := len(s.Solutions)
+	if O := s.Solve(); len(s.Solutions) > n {
+		return s.Decode(O)
+	}
+	return nil
+}
+
+// Same as SolveAll(), but returns the decoded rows of each solution.
+func (s *Solver) SolveAllDecoded() [][]interface{} {
+	all := make([][]interface{}, 0)
+	for _, O := range s.SolveAll() {
+		all = append(all, s.Decode(O))
+	}
+	return all
+}
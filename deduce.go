@@ -0,0 +1,133 @@
+package cover
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Sudoku solving technique, as human solvers name it.
+type Technique int
+
+const (
+	// A cell has a single candidate left.
+	NakedSingle Technique = iota
+	// A digit has a single cell left in a row, a column or a block.
+	HiddenSingle
+	// The cells left for a digit in a block share a row or a column, whose
+	// other cells lose the digit.
+	PointingPair
+	// The cells left for a digit in a row or a column share a block, whose
+	// other cells lose the digit.
+	BoxLineReduction
+	// No technique applies, the search gives the digit of a cell.
+	Guess
+)
+
+func (t Technique) String() string {
+	switch t {
+	case NakedSingle:
+		return "naked single"
+	case HiddenSingle:
+		return "hidden single"
+	case PointingPair:
+		return "pointing pair"
+	case BoxLineReduction:
+		return "box/line reduction"
+	case Guess:
+		return "guess"
+	}
+	return "Technique(" + strconv.Itoa(int(t)) + ")"
+}
+
+// Digit placed, or candidates eliminated, by a technique in the unit named
+// after its constraint column: "x,y" for a cell, "5r2" for digit 5 in row 2,
+// and so on for columns (c) and blocks (b).
+type Deduction struct {
+	Technique Technique
+	// digit placed, zero for eliminations
+	Cell SudokuCell
+	Unit string
+	// candidates removed by eliminations
+	Eliminated []SudokuCell
+}
+
+func (d Deduction) String() string {
+	if len(d.Eliminated) == 0 {
+		return fmt.Sprintf("%v: %v at %v,%v (%v)", d.Technique, d.Cell.Digit, d.Cell.X, d.Cell.Y, d.Unit)
+	}
+	cells := make([]string, len(d.Eliminated))
+	for i, c := range d.Eliminated {
+		cells[i] = fmt.Sprintf("%v,%v", c.X, c.Y)
+	}
+	return fmt.Sprintf("%v (%v): removes %v from %v", d.Technique, d.Unit, d.Eliminated[0].Digit, strings.Join(cells, " "))
+}
+
+// Returns a column of size 1, preferring cells to digits, or nil.
+func (s *SudokuSolver) singleCol() *Node {
+	var hidden *Node
+	root := s.matrix.Root()
+	for col := root.Right; col != root; col = col.Right {
+		if col.Size != 1 {
+			continue
+		}
+		if strings.Contains(col.Name, ",") {
+			return col
+		}
+		if hidden == nil {
+			hidden = col
+		}
+	}
+	return hidden
+}
+
+// Places the naked and hidden singles from level k, until there is none or
+// the matrix reaches a dead end, appending them to steps. In matrix terms,
+// both are columns of size 1, which Propagate() includes in any order.
+// Returns the number of cells placed, to be given to Unpropagate().
+func (s *SudokuSolver) singles(O *Solution, k int, steps *[]Deduction) int {
+	m := s.matrix
+	n := 0
+	for !m.DeadEnd() {
+		c := s.singleCol()
+		if c == nil {
+			break
+		}
+		r := c.Down
+		t := HiddenSingle
+		if strings.Contains(c.Name, ",") {
+			t = NakedSingle
+		}
+		*steps = append(*steps, Deduction{Technique: t, Cell: SudokuDecoder(r).(SudokuCell), Unit: c.Name})
+		O.Set(k+n, r)
+		m.cover(c, k+n)
+		for j := r.Right; j != r; j = j.Right {
+			m.cover(j.Col, k+n)
+		}
+		n++
+	}
+	return n
+}
+
+// Fills the grid with naked and hidden singles only, without guessing, and
+// returns it along with the deductions in order. Cells left empty need
+// another technique or a search. The matrix is restored afterwards. Returns
+// nil if the givens contradict each other.
+func (s *SudokuSolver) SolveSingles(sudoku [][]int) ([][]int, []Deduction) {
+	s.Reset()
+	m := s.matrix
+	O := m.NewSolution()
+	rows := s.givens(sudoku)
+	if rows == nil {
+		return nil, nil
+	}
+	k, err := m.Require(O, rows...)
+	if err != nil {
+		return nil, nil
+	}
+	steps := make([]Deduction, 0)
+	n := s.singles(O, k, &steps)
+	grid := s.Grid(O)
+	m.Release(O, k+n)
+	return grid, steps
+}
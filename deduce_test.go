@@ -0,0 +1,45 @@
+package cover
+
+import (
+	"strings"
+	"testing"
+)
+
+// Project Euler's first sudoku, solved by singles alone.
+const easySudoku = "..3.2.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3.."
+
+func TestSolveSingles(t *testing.T) {
+	s := NewSudokuSolver(9)
+	given := parseGrid(easySudoku)
+	empty := strings.Count(easySudoku, ".")
+	grid, steps := s.SolveSingles(given)
+	if len(steps) != empty {
+		t.Fatalf("Singles place %v cells (wants %v)", len(steps), empty)
+	}
+	checkGrid(t, given, grid)
+	naked := 0
+	for _, d := range steps {
+		if d.Technique == NakedSingle {
+			naked++
+		}
+		if grid[d.Cell.X][d.Cell.Y] != d.Cell.Digit {
+			t.Errorf("Deduction %v does not match the grid", d)
+		}
+	}
+	if naked == 0 {
+		t.Errorf("No naked single, though they come first")
+	}
+	if s.Solve(given); s.Singles != empty {
+		t.Errorf("Solve places %v singles (wants %v)", s.Singles, empty)
+	}
+	hard := "1....7.9..3..2...8..96..5....53..9...1..8...26....4...3......1..4......7..7...3.."
+	if _, steps := s.SolveSingles(parseGrid(hard)); len(steps) >= strings.Count(hard, ".") {
+		t.Errorf("Singles solve a hard sudoku")
+	}
+	if O := s.Solve(parseGrid(hard)); O.Len() != 81 {
+		t.Errorf("Hard sudoku not solved after singles")
+	}
+	if grid, _ := s.SolveSingles(parseGrid("11" + strings.Repeat(".", 79))); grid != nil {
+		t.Errorf("Contradicting givens give a grid")
+	}
+}
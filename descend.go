@@ -0,0 +1,115 @@
+package cover
+
+// Work of a node of a search written as a recursion, see descend().
+type descent struct {
+	// column to branch on, nil for the one of the heuristic
+	col *Node
+	// tells whether to try a row, nil to try them all
+	keep func(r *Node) bool
+	// explores the child node of a row, which is committed and appended to
+	// the solution, returning true to stop trying rows and keep the row in
+	// the solution
+	visit func(r *Node) bool
+	// explores the child node leaving the column uncovered after its rows,
+	// which rules them out, returning true like visit; nil for none
+	leave func(c *Node) bool
+	// columns may be left uncovered, so that the rows leaving a column
+	// without rows are visited too
+	partial bool
+	// looks ahead rather than searching, so that neither the statistics nor
+	// the budgets count the rows
+	tentative bool
+}
+
+// Prepares a search written as a recursion, e.g. a branch and bound, whose
+// nodes call descend(): clears the statistics, the abort and the budgets.
+func (m *SparseMatrix) begin(O *Solution) {
+	m.ResetStats()
+	m.aborted = false
+	m.tried, m.limited = 0, false
+	m.current = O
+}
+
+// Counts a row tried by branching, and tells whether the node budget or the
+// context stopped the search, which is then aborted.
+func (m *SparseMatrix) spent() bool {
+	if m.tried++; m.nodeLimit > 0 && m.tried > m.nodeLimit {
+		m.limited = true
+		m.Abort()
+		return true
+	}
+	// checking the context at every row would slow the search down
+	if m.ctx != nil && m.tried%256 == 0 && m.ctx.Err() != nil {
+		m.Abort()
+		return true
+	}
+	return false
+}
+
+// Branches a node at depth k of a search written as a recursion, with the
+// checks of branch(): the column is covered and the rows are committed on
+// behalf of the search, which fires the hooks, purifies the colored columns
+// and self-checks the matrix, the rows leaving a column without rows are cut,
+// and the statistics, the budgets and the context apply, an aborted search
+// trying no more rows. The rows are appended to O unless it is nil, which
+// must then hold k rows. The matrix is restored afterwards. Returns true when
+// a visit stopped the branching, not when the search is aborted, which the
+// caller checks with Aborted().
+func (m *SparseMatrix) descend(O *Solution, k int, d descent) bool {
+	if !d.tentative {
+		if m.aborted {
+			return false
+		}
+		if m.depthLimit > 0 && k >= m.depthLimit {
+			m.limited = true
+			m.Abort()
+			return false
+		}
+	}
+	c := d.col
+	if c == nil {
+		m.level = k
+		if c = m.SmallestCol(); c == nil {
+			return false
+		}
+	}
+	if !d.tentative {
+		if m.groups != nil {
+			m.groups.branched(c)
+		}
+		m.stats.level(k)
+	}
+	m.cover(c, k)
+	stop := false
+	for r := c.Down; r != c && !stop && (d.tentative || !m.aborted); r = r.Down {
+		if d.keep != nil && !d.keep(r) {
+			continue
+		}
+		if !d.tentative {
+			if m.spent() {
+				break
+			}
+			m.stats.Levels[k].Candidates++
+		}
+		if O != nil {
+			*O = append(*O, r)
+		}
+		for j := r.Right; j != r; j = j.Right {
+			m.commit(j, k)
+		}
+		if d.partial || !m.DeadEnd() {
+			stop = d.visit(r)
+		}
+		for j := r.Left; j != r; j = j.Left {
+			m.uncommit(j, k)
+		}
+		if !stop && O != nil {
+			*O = (*O)[:k]
+		}
+	}
+	if !stop && d.leave != nil && (d.tentative || !m.aborted) {
+		stop = d.leave(c)
+	}
+	m.uncover(c, k)
+	return stop
+}
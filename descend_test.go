@@ -0,0 +1,79 @@
+package cover
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Items a and b with a secondary item x colored r or g, whose only solution
+// is the rows {a, x:r} and {b, x:r}.
+func coloredSpec(t *testing.T) *Spec {
+	spec, err := LoadSpec(strings.NewReader(`{
+		"items": [{"name": "a"}, {"name": "b"},
+			{"name": "x", "secondary": true, "colors": ["r", "g"]}],
+		"options": [{"items": ["a", "x:r"]}, {"items": ["b", "x:r"]}, {"items": ["b", "x:g"]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+func TestDescendColors(t *testing.T) {
+	solver, err := coloredSpec(t).Solver()
+	if err != nil {
+		t.Fatal(err)
+	}
+	costs := []float64{1, 1, 1}
+	if O, cost := solver.SolveMinCost(costs, true); cost != 2 || fmt.Sprint(O.Rows()) != "[0 1]" {
+		t.Errorf("Min cost solution is %v costing %v", O.Rows(), cost)
+	}
+	if O, cost, _ := solver.SolvePreferred(costs, costs, true); cost != 2 || O.Len() != 2 {
+		t.Errorf("Preferred solution is %v costing %v", O.Rows(), cost)
+	}
+	if points := solver.SolvePareto(costs, costs); len(points) != 1 {
+		t.Errorf("Pareto frontier is %v", points)
+	}
+	if O, _, cost := solver.SolveWithPenalties(costs, nil); cost != 2 || O.Len() != 2 {
+		t.Errorf("Solution with penalties is %v costing %v", O.Rows(), cost)
+	}
+	if O, changes := solver.SolveNear(Solution{}, 2); changes != 2 || O.Len() != 2 {
+		t.Errorf("Near solution is %v with %v changes", O.Rows(), changes)
+	}
+	if pool := solver.SolveDiverse(3, 1); len(pool) != 1 {
+		t.Errorf("Diverse solutions are %v", pool)
+	}
+	if e := solver.EstimateCount(10, 1); e.Count != 1 {
+		t.Errorf("Estimate is %v", e)
+	}
+	for _, h := range solver.Hardness() {
+		// b x:g purifies x, which hides the only row of a
+		if h.Name == "b" && h.Viable != 1 {
+			t.Errorf("Column b hardness %v", h)
+		}
+	}
+	if err := solver.matrix.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDescendLimitsAndHooks(t *testing.T) {
+	matrix, headers, _ := GeneratePlantedCover(16, 80, 0.2, 1)
+	costs := make([]float64, len(matrix))
+	covers := 0
+	solver := NewSolver(matrix, headers, WithNodeLimit(3), WithHook(func(e Event, col *Node, depth int) {
+		if e == CoverEvent {
+			covers++
+		}
+	}))
+	solver.SolveMinCost(costs, false)
+	if !solver.LimitReached() || covers == 0 {
+		t.Errorf("Min cost search reached its limit %v with %v covers", solver.LimitReached(), covers)
+	}
+	if stats := solver.Stats(); stats.Candidates() != 3 {
+		t.Errorf("Min cost search tried %v rows (wants 3)", stats.Candidates())
+	}
+	if cols := solver.matrix.ColCount(); cols != len(headers) {
+		t.Errorf("Matrix has %v columns after search (wants %v)", cols, len(headers))
+	}
+}
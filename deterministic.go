@@ -0,0 +1,20 @@
+package cover
+
+// Events between two progress reports in deterministic mode.
+const deterministicPeriod = 1 << 16
+
+// Makes the following searches visit the same nodes in the same order on
+// every run, as performance regression tracking needs: ShuffleRows() keeps
+// the rows in their order, restarts break ties with the first smallest column
+// rather than at random, and progress is reported every fixed number of
+// events rather than of elapsed time, so that a callback aborting the search
+// does it at the same node. Contexts and nogoods shared with concurrent
+// solvers still depend on the scheduling.
+func (m *SparseMatrix) SetDeterministic(on bool) {
+	m.deterministic = on
+}
+
+// Tells whether the searches are deterministic, see SetDeterministic().
+func (m *SparseMatrix) Deterministic() bool {
+	return m.deterministic
+}
@@ -0,0 +1,48 @@
+package cover
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestDeterministic(t *testing.T) {
+	matrix, headers, _ := GeneratePlantedCover(16, 40, 0.2, 3)
+	traces := make([]string, 0)
+	for seed := int64(0); seed < 3; seed++ {
+		var out bytes.Buffer
+		s := NewSolver(matrix, headers, WithDeterministic(), WithRandomRows(seed), WithTrace(&out, ""))
+		s.SolveAll()
+		traces = append(traces, out.String())
+	}
+	for i, trace := range traces[1:] {
+		if trace != traces[0] {
+			t.Errorf("Search with seed %v differs from seed 0", i+1)
+		}
+	}
+
+	candidates := make([]int64, 0)
+	for seed := int64(0); seed < 3; seed++ {
+		s := NewSolver(matrix, headers, WithDeterministic())
+		if O := s.SolveWithRestarts(10, seed); O.Len() == 0 {
+			t.Fatalf("Restarts with seed %v find no solution", seed)
+		}
+		stats := s.Stats()
+		candidates = append(candidates, stats.Candidates())
+	}
+	if candidates[1] != candidates[0] || candidates[2] != candidates[0] {
+		t.Errorf("Restarts try %v candidates depending on the seed", candidates)
+	}
+
+	s := NewSolver(oddPairs(13))
+	s.matrix.SetDeterministic(true)
+	reports := 0
+	s.matrix.OnProgress(time.Hour, func(p Progress) bool {
+		reports++
+		return reports < 3
+	})
+	s.SolveAll()
+	if reports != 3 || !s.matrix.Aborted() {
+		t.Errorf("Deterministic progress reported %v times, aborted %v", reports, s.matrix.Aborted())
+	}
+}
@@ -0,0 +1,72 @@
+package cover
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+// Returns the sorted indexes of the rows of the solution.
+func (s *Solution) Rows() []int {
+	rows := make([]int, 0, len(*s))
+	for _, n := range *s {
+		if n != nil {
+			rows = append(rows, n.Row)
+		}
+	}
+	sort.Ints(rows)
+	return rows
+}
+
+// Tells whether both solutions are made of the same rows, in any order.
+func (s *Solution) Equal(o *Solution) bool {
+	a, b := s.Rows(), o.Rows()
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the rows of a which are not in b, and the rows of b which are not in a.
+func Diff(a, b *Solution) (onlyA, onlyB []int) {
+	x, y := a.Rows(), b.Rows()
+	onlyA, onlyB = make([]int, 0), make([]int, 0)
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case j == len(y) || i < len(x) && x[i] < y[j]:
+			onlyA = append(onlyA, x[i])
+			i++
+		case i == len(x) || y[j] < x[i]:
+			onlyB = append(onlyB, y[j])
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	return
+}
+
+// Returns a string identifying the set of rows, suitable as a map key.
+func (s *Solution) Key() string {
+	rows := s.Rows()
+	parts := make([]string, len(rows))
+	for i, r := range rows {
+		parts[i] = fmt.Sprint(r)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Returns a 64 bits hash of the set of rows.
+func (s *Solution) Hash() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s.Key()))
+	return h.Sum64()
+}
@@ -0,0 +1,29 @@
+package cover
+
+import (
+	"testing"
+)
+
+func TestSolutionDiff(t *testing.T) {
+	m := NewSparseMatrix([][]int{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}, {1, 1, 0}}, []string{"A", "B", "C"})
+	rows := m.Col("A").ColNodes()
+	b := m.Col("B").ColNodes()
+	c := m.Col("C").ColNodes()
+	first := Solution{rows[0], b[0], c[0]}
+	// same rows given by other nodes, in another order
+	same := Solution{c[0], b[0], rows[0]}
+	other := Solution{c[0], b[1]}
+	if !first.Equal(&same) {
+		t.Errorf("Solutions %v and %v should be equal", first.Rows(), same.Rows())
+	}
+	if first.Key() != same.Key() || first.Hash() != same.Hash() {
+		t.Errorf("Equal solutions have different keys")
+	}
+	if first.Equal(&other) {
+		t.Errorf("Solutions %v and %v should differ", first.Rows(), other.Rows())
+	}
+	onlyA, onlyB := Diff(&first, &other)
+	if len(onlyA) != 2 || onlyA[0] != 0 || onlyA[1] != 1 || len(onlyB) != 1 || onlyB[0] != 3 {
+		t.Errorf("Diff is %v, %v (wants [0 1], [3])", onlyA, onlyB)
+	}
+}
@@ -0,0 +1,187 @@
+/*
+Package distributed counts the solutions of an exact cover problem on several
+machines. A coordinator splits the top of the search tree into work units of
+subtrees, see cover.Checkpoint, which workers lease over HTTP. Units whose
+lease expires, e.g. because the worker died, are issued again.
+*/
+package distributed
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/qur2/go-cover"
+)
+
+// Problem sent to the workers.
+type Problem struct {
+	Spec *cover.Spec
+	// depth of the subtrees the work units are made of
+	Depth int
+}
+
+// Range of subtrees to count, from Lo to Hi excluded.
+type Unit struct {
+	ID     int
+	Lo, Hi int
+}
+
+// Counts of the subtrees of a unit, sent back by a worker.
+type Result struct {
+	ID     int
+	Counts map[int]*big.Int
+}
+
+// Error of a result not matching the unit it is sent for.
+var ErrBadResult = errors.New("bad result")
+
+// Hands out work units and aggregates their results.
+type Coordinator struct {
+	problem  Problem
+	units    []Unit
+	lease    time.Duration
+	mu       sync.Mutex
+	deadline map[int]time.Time
+	done     map[int]bool
+	progress *cover.Checkpoint
+	finished chan struct{}
+}
+
+// Creates a coordinator splitting the subtrees rooted at the given depth in
+// units of size subtrees. A unit leased for longer than lease is issued again.
+// Returns the error of NewSpecCoordinator().
+func NewCoordinator(matrix [][]int, headers []string, depth, size int, lease time.Duration) (*Coordinator, error) {
+	return NewSpecCoordinator(cover.NewSpec(matrix, headers), depth, size, lease)
+}
+
+// Same as NewCoordinator(), for a problem given by its spec. Fails if the
+// engine cannot search the problem.
+func NewSpecCoordinator(spec *cover.Spec, depth, size int, lease time.Duration) (*Coordinator, error) {
+	solver, err := spec.Solver()
+	if err != nil {
+		return nil, err
+	}
+	n := solver.Subtrees(depth)
+	c := &Coordinator{
+		problem:  Problem{Spec: spec, Depth: depth},
+		lease:    lease,
+		deadline: map[int]time.Time{},
+		done:     map[int]bool{},
+		progress: cover.NewCheckpoint(depth),
+		finished: make(chan struct{}),
+	}
+	for lo := 0; lo < n; lo += size {
+		hi := lo + size
+		if hi > n {
+			hi = n
+		}
+		c.units = append(c.units, Unit{ID: len(c.units), Lo: lo, Hi: hi})
+	}
+	if len(c.units) == 0 {
+		close(c.finished)
+	}
+	return c, nil
+}
+
+// Returns a unit neither done nor leased, or whose lease expired.
+// It fails if there is none, the last value telling whether all are done.
+func (c *Coordinator) next() (Unit, bool, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.done) == len(c.units) {
+		return Unit{}, false, true
+	}
+	now := time.Now()
+	for _, u := range c.units {
+		if c.done[u.ID] {
+			continue
+		}
+		if d, leased := c.deadline[u.ID]; leased && now.Before(d) {
+			continue
+		}
+		c.deadline[u.ID] = now.Add(c.lease)
+		return u, true, false
+	}
+	return Unit{}, false, false
+}
+
+// Records the result of a unit. Late results of reissued units are ignored.
+// Fails with ErrBadResult, recording nothing, unless the result is for a unit
+// and counts each of its subtrees, and only them.
+func (c *Coordinator) record(r Result) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if r.ID < 0 || r.ID >= len(c.units) {
+		return fmt.Errorf("%w: no unit %v", ErrBadResult, r.ID)
+	}
+	u := c.units[r.ID]
+	if len(r.Counts) != u.Hi-u.Lo {
+		return fmt.Errorf("%w: %v counts for the %v subtrees of unit %v", ErrBadResult, len(r.Counts), u.Hi-u.Lo, u.ID)
+	}
+	for i, n := range r.Counts {
+		if i < u.Lo || i >= u.Hi || n == nil || n.Sign() < 0 {
+			return fmt.Errorf("%w: count %v of subtree %v for unit %v", ErrBadResult, n, i, u.ID)
+		}
+	}
+	if c.done[r.ID] {
+		return nil
+	}
+	c.done[r.ID] = true
+	for i, n := range r.Counts {
+		c.progress.Counts[i] = n
+	}
+	if len(c.done) == len(c.units) {
+		close(c.finished)
+	}
+	return nil
+}
+
+// Serves the problem on GET /problem, leases units on POST /lease, and collects
+// results on POST /result. Leasing answers 204 once all the units are done, and
+// 503 while the remaining ones are leased by other workers. Bad results are
+// answered 400.
+func (c *Coordinator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == "GET" && r.URL.Path == "/problem":
+		json.NewEncoder(w).Encode(c.problem)
+	case r.Method == "POST" && r.URL.Path == "/lease":
+		u, ok, finished := c.next()
+		if finished {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if !ok {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(w).Encode(u)
+	case r.Method == "POST" && r.URL.Path == "/result":
+		var res Result
+		if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := c.record(res); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// Returns a channel closed once all the units are done.
+func (c *Coordinator) Done() <-chan struct{} {
+	return c.finished
+}
+
+// Returns the number of solutions counted so far.
+func (c *Coordinator) Total() *big.Int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.progress.Total()
+}
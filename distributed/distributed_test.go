@@ -0,0 +1,125 @@
+package distributed
+
+import (
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/qur2/go-cover"
+)
+
+func TestCoordinator(t *testing.T) {
+	m, h := cover.GraecoLatinConstraintMatrix(3)
+	c, err := NewCoordinator(m, h, 2, 3, 50*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(c)
+	defer server.Close()
+	// a worker leasing a unit then dying
+	resp, err := http.Post(server.URL+"/lease", "application/json", nil)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("Lease failed: %v %v", err, resp)
+	}
+	resp.Body.Close()
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := RunWorker(http.DefaultClient, server.URL, 10*time.Millisecond); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	select {
+	case <-c.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Units not done in time")
+	}
+	wg.Wait()
+	if total := c.Total(); total.Int64() != 72 {
+		t.Errorf("Workers counted %v solutions (wants %v)", total, 72)
+	}
+}
+
+func TestCoordinatorErrors(t *testing.T) {
+	spec := &cover.Spec{Items: []cover.ItemSpec{{Name: "a", Min: 0, Max: 2}}}
+	if _, err := NewSpecCoordinator(spec, 1, 1, time.Second); !errors.Is(err, cover.ErrUnsupported) {
+		t.Errorf("Coordinator of a problem with multiplicities fails with %v", err)
+	}
+	m, h := cover.GraecoLatinConstraintMatrix(3)
+	c, err := NewCoordinator(m, h, 2, 3, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := c.units[0]
+	full := map[int]*big.Int{}
+	for i := u.Lo; i < u.Hi; i++ {
+		full[i] = big.NewInt(1)
+	}
+	for _, r := range []Result{
+		{ID: -1, Counts: full},
+		{ID: len(c.units), Counts: full},
+		{ID: u.ID, Counts: map[int]*big.Int{u.Lo: big.NewInt(1)}},
+		{ID: u.ID, Counts: map[int]*big.Int{u.Lo: big.NewInt(1), u.Lo + 1: big.NewInt(1), u.Hi: big.NewInt(1)}},
+		{ID: u.ID, Counts: map[int]*big.Int{u.Lo: big.NewInt(1), u.Lo + 1: big.NewInt(-1), u.Lo + 2: big.NewInt(1)}},
+		{ID: u.ID, Counts: map[int]*big.Int{u.Lo: big.NewInt(1), u.Lo + 1: nil, u.Lo + 2: big.NewInt(1)}},
+	} {
+		if err := c.record(r); !errors.Is(err, ErrBadResult) {
+			t.Errorf("Result %v recorded with %v", r, err)
+		}
+	}
+	if c.done[u.ID] || c.Total().Sign() != 0 {
+		t.Errorf("Bad results counted %v solutions", c.Total())
+	}
+	server := httptest.NewServer(c)
+	defer server.Close()
+	resp, err := http.Post(server.URL+"/result", "application/json", strings.NewReader(`{"ID": 0, "Counts": {"9": 1}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Bad result answered %v", resp.Status)
+	}
+	if err := c.record(Result{ID: u.ID, Counts: full}); err != nil {
+		t.Errorf("Result of unit %v failed: %v", u.ID, err)
+	}
+}
+
+func TestWorkerRejected(t *testing.T) {
+	m, h := cover.GraecoLatinConstraintMatrix(3)
+	c, err := NewCoordinator(m, h, 2, 3, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/result" {
+			http.Error(w, "down", http.StatusInternalServerError)
+			return
+		}
+		c.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	if err := RunWorker(http.DefaultClient, server.URL, time.Millisecond); err == nil {
+		t.Error("Worker ignored the rejection of its result")
+	}
+}
+
+func TestWorkerProblemFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("{}"))
+	}))
+	defer server.Close()
+	err := RunWorker(http.DefaultClient, server.URL, time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Worker gives %v for an unavailable problem (wants the status)", err)
+	}
+}
@@ -0,0 +1,78 @@
+package distributed
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/qur2/go-cover"
+)
+
+// Leases and counts work units from the coordinator at url until all are done.
+// While the remaining units are leased by other workers, it polls every wait in
+// case their lease expires. Fails if the coordinator rejects a result.
+func RunWorker(client *http.Client, url string, wait time.Duration) error {
+	resp, err := client.Get(url + "/problem")
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return fmt.Errorf("problem failed: %v", resp.Status)
+	}
+	var p Problem
+	err = json.NewDecoder(resp.Body).Decode(&p)
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	if p.Spec == nil {
+		return errors.New("problem has no spec")
+	}
+	for {
+		resp, err := client.Post(url+"/lease", "application/json", nil)
+		if err != nil {
+			return err
+		}
+		if resp.StatusCode == http.StatusNoContent {
+			resp.Body.Close()
+			return nil
+		}
+		if resp.StatusCode == http.StatusServiceUnavailable {
+			resp.Body.Close()
+			time.Sleep(wait)
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return fmt.Errorf("lease failed: %v", resp.Status)
+		}
+		var u Unit
+		err = json.NewDecoder(resp.Body).Decode(&u)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+		solver, err := p.Spec.Solver()
+		if err != nil {
+			return err
+		}
+		progress := cover.NewCheckpoint(p.Depth)
+		solver.CountSubtrees(progress, u.Lo, u.Hi)
+		body, err := json.Marshal(Result{ID: u.ID, Counts: progress.Counts})
+		if err != nil {
+			return err
+		}
+		resp, err = client.Post(url+"/result", "application/json", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("result of unit %v rejected: %v", u.ID, resp.Status)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package cover
+
+// Returns up to n solutions differing pairwise in at least minHamming rows,
+// i.e. each one missing at least that many rows of every earlier one, for
+// presenting genuinely different alternatives, 1 only asking for distinct
+// solutions. The solutions are found by
+// successive searches, each one excluding the partial solutions already
+// holding too many rows of an earlier solution. Fewer solutions are returned
+// when the search runs out of them. The matrix is restored afterwards.
+func (s *Solver) SolveDiverse(n, minHamming int) []*Solution {
+	m := s.matrix
+	pool := make([]*Solution, 0, n)
+	// earlier solutions holding each row
+	holders := map[int][]int{}
+	m.begin(nil)
+	for len(pool) < n && !m.aborted {
+		shared := make([]int, len(pool))
+		O := m.NewSolution()
+		m.current = O
+		if !m.diverse(O, pool, holders, shared, minHamming) {
+			break
+		}
+		for _, r := range *O {
+			holders[r.Row] = append(holders[r.Row], len(pool))
+		}
+		pool = append(pool, O)
+	}
+	return pool
+}
+
+// Searches a solution sharing at most len(pool[i])-minHamming rows with each
+// earlier solution i, shared counting the rows shared so far, and leaves it in
+// O.
+func (m *SparseMatrix) diverse(O *Solution, pool []*Solution, holders map[int][]int, shared []int, minHamming int) bool {
+	root := m.Root()
+	if root.Right == root {
+		return true
+	}
+	return m.descend(O, len(*O), descent{keep: func(r *Node) bool {
+		for _, i := range holders[r.Row] {
+			if shared[i]+1 > pool[i].Len()-minHamming {
+				return false
+			}
+		}
+		return true
+	}, visit: func(r *Node) bool {
+		for _, i := range holders[r.Row] {
+			shared[i]++
+		}
+		found := m.diverse(O, pool, holders, shared, minHamming)
+		for _, i := range holders[r.Row] {
+			shared[i]--
+		}
+		return found
+	}})
+}
@@ -0,0 +1,47 @@
+package cover
+
+import (
+	"testing"
+)
+
+func TestSolveDiverse(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		matrix, headers, _ := GeneratePlantedCover(16, 40, 0.2, seed)
+		s := NewSolver(matrix, headers)
+		all := len(s.SolveAll())
+		if pool := s.SolveDiverse(all+1, 1); len(pool) != all {
+			t.Errorf("Seed %v: %v distinct solutions (wants all %v)", seed, len(pool), all)
+		}
+		pool := s.SolveDiverse(4, 3)
+		if len(pool) == 0 {
+			t.Fatalf("Seed %v: no solution", seed)
+		}
+		for i, a := range pool {
+			rows := make([]int, a.Len())
+			for k, r := range *a {
+				rows[k] = r.Row
+			}
+			if err := s.CheckRows(rows); err != nil {
+				t.Errorf("Seed %v: solution %v: %v", seed, i, err)
+			}
+			for _, b := range pool[:i] {
+				in := map[int]bool{}
+				for _, r := range *a {
+					in[r.Row] = true
+				}
+				missing := 0
+				for _, r := range *b {
+					if !in[r.Row] {
+						missing++
+					}
+				}
+				if missing < 3 {
+					t.Errorf("Seed %v: solutions %v and %v differ in %v rows (wants 3)", seed, a, b, missing)
+				}
+			}
+		}
+		if cols := s.matrix.ColCount(); cols != len(headers) {
+			t.Errorf("Matrix has %v columns after search (wants %v)", cols, len(headers))
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package cover
+
+import (
+	"context"
+)
+
+// Small SAT solver doing unit propagation over two watched literals per
+// clause and chronological backtracking, without clause learning. It is
+// enough for the formulas of CNF() on moderate matrices; a CDCL solver can be
+// plugged in through SATSolver for harder instances.
+type DPLL struct{}
+
+// State of a DPLL search.
+type dpll struct {
+	clauses [][]int
+	// clauses watching each literal, see watch()
+	watches [][]int
+	// value of each variable: 1 true, -1 false, 0 unassigned
+	value []int8
+	// literals made true, in order
+	trail []int
+	// start of each decision level in the trail, and whether its decision
+	// was already flipped
+	levels  []int
+	flipped []bool
+	// next literal of the trail to propagate
+	head int
+}
+
+// Returns the index of the literal l in the watch lists.
+func watch(l int) int {
+	if l < 0 {
+		return -2 * l
+	}
+	return 2*l + 1
+}
+
+func (d *dpll) val(l int) int8 {
+	if l < 0 {
+		return -d.value[-l]
+	}
+	return d.value[l]
+}
+
+func (d *dpll) assign(l int) {
+	if l < 0 {
+		d.value[-l] = -1
+	} else {
+		d.value[l] = 1
+	}
+	d.trail = append(d.trail, l)
+}
+
+// Propagates the literals of the trail not propagated yet. Returns false on
+// a conflict.
+func (d *dpll) propagate() bool {
+	for ; d.head < len(d.trail); d.head++ {
+		f := -d.trail[d.head]
+		ws := d.watches[watch(f)]
+		kept := ws[:0]
+		conflict := false
+		for i, ci := range ws {
+			if conflict {
+				kept = append(kept, ws[i:]...)
+				break
+			}
+			c := d.clauses[ci]
+			if c[0] == f {
+				c[0], c[1] = c[1], c[0]
+			}
+			if d.val(c[0]) > 0 {
+				kept = append(kept, ci)
+				continue
+			}
+			moved := false
+			for k := 2; k < len(c); k++ {
+				if d.val(c[k]) >= 0 {
+					c[1], c[k] = c[k], c[1]
+					d.watches[watch(c[1])] = append(d.watches[watch(c[1])], ci)
+					moved = true
+					break
+				}
+			}
+			if moved {
+				continue
+			}
+			kept = append(kept, ci)
+			if d.val(c[0]) < 0 {
+				conflict = true
+			} else {
+				d.assign(c[0])
+			}
+		}
+		d.watches[watch(f)] = kept
+		if conflict {
+			return false
+		}
+	}
+	return true
+}
+
+// Undoes the last decision level, and flips its decision unless it was
+// already. Returns false when there is no decision left to flip.
+func (d *dpll) backtrack() bool {
+	for len(d.levels) > 0 {
+		top := len(d.levels) - 1
+		start, flipped := d.levels[top], d.flipped[top]
+		decision := d.trail[start]
+		for _, l := range d.trail[start:] {
+			if l < 0 {
+				l = -l
+			}
+			d.value[l] = 0
+		}
+		d.trail = d.trail[:start]
+		d.head = start
+		d.levels, d.flipped = d.levels[:top], d.flipped[:top]
+		if !flipped {
+			d.levels = append(d.levels, start)
+			d.flipped = append(d.flipped, true)
+			d.assign(-decision)
+			return true
+		}
+	}
+	return false
+}
+
+func (DPLL) Solve(ctx context.Context, c *CNF) ([]bool, error) {
+	d := &dpll{
+		watches: make([][]int, 2*c.Vars+2),
+		value:   make([]int8, c.Vars+1),
+	}
+	for _, clause := range c.Clauses {
+		switch len(clause) {
+		case 0:
+			return nil, nil
+		case 1:
+			if v := d.val(clause[0]); v < 0 {
+				return nil, nil
+			} else if v == 0 {
+				d.assign(clause[0])
+			}
+		default:
+			ci := len(d.clauses)
+			d.clauses = append(d.clauses, append([]int{}, clause...))
+			d.watches[watch(clause[0])] = append(d.watches[watch(clause[0])], ci)
+			d.watches[watch(clause[1])] = append(d.watches[watch(clause[1])], ci)
+		}
+	}
+	next := 1
+	for decisions := 1; ; decisions++ {
+		if decisions%1024 == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if !d.propagate() {
+			if !d.backtrack() {
+				return nil, nil
+			}
+			// backtracking unassigns variables of any index
+			next = 1
+			continue
+		}
+		for next <= c.Vars && d.value[next] != 0 {
+			next++
+		}
+		if next > c.Vars {
+			model := make([]bool, c.Vars+1)
+			for v := 1; v <= c.Vars; v++ {
+				model[v] = d.value[v] > 0
+			}
+			return model, nil
+		}
+		d.levels = append(d.levels, len(d.trail))
+		d.flipped = append(d.flipped, false)
+		d.assign(next)
+	}
+}
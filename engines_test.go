@@ -0,0 +1,37 @@
+package cover
+
+import (
+	"testing"
+)
+
+func TestEngines(t *testing.T) {
+	m, h := GraecoLatinConstraintMatrix(3)
+	rows := SparseRows(m)
+	if count := NewArrayMatrix(rows, h).Count(); count.Int64() != 72 {
+		t.Errorf("Array engine finds %v solutions (wants %v)", count, 72)
+	}
+	if count := NewBitsetMatrix(rows, len(h)).Count(); count.Int64() != 72 {
+		t.Errorf("Bitset engine finds %v solutions (wants %v)", count, 72)
+	}
+	knuth := SparseRows([][]int{
+		{0, 0, 1, 0, 1, 1, 0},
+		{1, 0, 0, 1, 0, 0, 1},
+		{0, 1, 1, 0, 0, 1, 0},
+		{1, 0, 0, 1, 0, 0, 0},
+		{0, 1, 0, 0, 0, 0, 1},
+		{0, 0, 0, 1, 1, 0, 1},
+	})
+	headers := []string{"A", "B", "C", "D", "E", "F", "G"}
+	for name, rows := range map[string][]int{
+		"array":  NewArrayMatrix(knuth, headers).Solve(),
+		"bitset": NewBitsetMatrix(knuth, len(headers)).Solve(),
+	} {
+		found := map[int]bool{}
+		for _, r := range rows {
+			found[r] = true
+		}
+		if len(rows) != 3 || !found[0] || !found[3] || !found[4] {
+			t.Errorf("Wrong solution to Knuth example with %v engine: %v", name, rows)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package cover
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Estimate of the number of solutions, after Knuth: each probe walks down a
+// random branch of the search tree, choosing the columns as the search does
+// and a row uniformly at random, and the product of the sizes of the columns
+// met on the way is an unbiased estimate of the count, or 0 on a dead end.
+// The estimates of the probes vary wildly on irregular trees, so that the
+// confidence interval, which assumes their mean to be normal, needs many of
+// them to be trusted.
+type CountEstimate struct {
+	Probes int
+	// mean estimate of the probes and its standard error
+	Count, StdErr float64
+	// bounds of the 95% confidence interval, both being at least 1 once a
+	// probe found a solution
+	Low, High float64
+	// mean estimate of the number of nodes of the search tree
+	Nodes float64
+}
+
+func (e CountEstimate) String() string {
+	return fmt.Sprintf("%.4g solutions, 95%% in [%.4g, %.4g] from %v probes", e.Count, e.Low, e.High, e.Probes)
+}
+
+// Estimates the number of solutions from random probes of the search tree,
+// much faster than counting them when the tree is huge. Probes are drawn from
+// the seed, so that estimates can be reproduced.
+func (s *Solver) EstimateCount(probes int, seed int64) CountEstimate {
+	e := CountEstimate{Probes: probes}
+	if probes <= 0 {
+		return e
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	// running mean and sum of squared deviations, after Welford
+	var squares float64
+	for i := 1; i <= probes; i++ {
+		count, nodes := s.matrix.probeCount(rnd, 0)
+		delta := count - e.Count
+		e.Count += delta / float64(i)
+		squares += delta * (count - e.Count)
+		e.Nodes += (nodes - e.Nodes) / float64(i)
+	}
+	if probes > 1 {
+		e.StdErr = math.Sqrt(squares / float64(probes-1) / float64(probes))
+	}
+	e.Low = math.Max(0, e.Count-1.96*e.StdErr)
+	if e.Count > 0 {
+		e.Low = math.Max(1, e.Low)
+	}
+	e.High = math.Max(e.Low, e.Count+1.96*e.StdErr)
+	return e
+}
+
+// Walks down a random branch of the search tree from depth k, returning the
+// estimates of the number of solutions and of nodes of the subtree, and
+// restores the matrix.
+func (m *SparseMatrix) probeCount(rnd *rand.Rand, k int) (count, nodes float64) {
+	root := m.Root()
+	if root.Right == root {
+		return 1, 1
+	}
+	nodes = 1
+	var pick *Node
+	m.descend(nil, k, descent{tentative: true, keep: func(r *Node) bool {
+		// the first call gets the top row of the column, from which a row is
+		// drawn, the children counting as leaves unless the row is visited
+		if pick == nil {
+			pick = r
+			for i := rnd.Intn(int(r.Col.Size)); i > 0; i-- {
+				pick = pick.Down
+			}
+			nodes += float64(r.Col.Size)
+		}
+		return r == pick
+	}, visit: func(r *Node) bool {
+		size := float64(r.Col.Size)
+		sub, subNodes := m.probeCount(rnd, k+1)
+		count, nodes = size*sub, 1+size*subNodes
+		return false
+	}})
+	return count, nodes
+}
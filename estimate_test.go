@@ -0,0 +1,26 @@
+package cover
+
+import (
+	"testing"
+)
+
+func TestEstimateCount(t *testing.T) {
+	s := NewSolver(knuth(), []string{"A", "B", "C", "D", "E", "F", "G"})
+	if e := s.EstimateCount(1000, 1); e.Low > 1 || e.High < 1 {
+		t.Errorf("Estimate %v misses the single solution", e)
+	}
+	matrix, headers, _ := GeneratePlantedCover(16, 40, 0.2, 3)
+	s = NewSolver(matrix, headers)
+	exact := float64(s.Count().Int64())
+	e := s.EstimateCount(20000, 1)
+	if exact < e.Low || exact > e.High {
+		t.Errorf("Estimate %v misses the count %v", e, exact)
+	}
+	// probes restore the matrix
+	if n := s.Count().Int64(); float64(n) != exact {
+		t.Errorf("Count after probes is %v (wants %v)", n, exact)
+	}
+	if e := s.EstimateCount(0, 1); e.Count != 0 {
+		t.Errorf("Estimate without probes is %v", e)
+	}
+}
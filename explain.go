@@ -0,0 +1,164 @@
+package cover
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// Returns the kind of a sudoku constraint column: 'x' for a cell, or 'r',
+// 'c' or 'b' for a digit in a row, a column or a block.
+func unitKind(name string) byte {
+	if strings.Contains(name, ",") {
+		return 'x'
+	}
+	return name[strings.IndexAny(name, "rcb")]
+}
+
+// Finds a digit whose cells left in a unit all lie in a second unit, the other
+// cells of which lose the digit, and hides their rows. Returns false if there
+// is none.
+func (s *SudokuSolver) lockedCandidates() (Deduction, bool) {
+	m := s.matrix
+	root := m.Root()
+	for a := root.Right; a != root; a = a.Right {
+		ka := unitKind(a.Name)
+		if ka == 'x' || a.Size == 0 {
+			continue
+		}
+		// units shared by every row of a, among those of its first row
+		for _, b := range a.Down.RowNodes() {
+			kb := unitKind(b.Col.Name)
+			if kb == 'x' || kb == ka || (ka != 'b' && kb != 'b') {
+				continue
+			}
+			shared := true
+			for r := a.Down; r != a && shared; r = r.Down {
+				shared = rowHasCol(r, b.Col)
+			}
+			if !shared {
+				continue
+			}
+			others := make([]*Node, 0)
+			for r := b.Col.Down; r != b.Col; r = r.Down {
+				if !rowHasCol(r, a) {
+					others = append(others, r)
+				}
+			}
+			if len(others) == 0 {
+				continue
+			}
+			d := Deduction{Technique: PointingPair, Unit: a.Name + " in " + b.Col.Name}
+			if ka != 'b' {
+				d.Technique = BoxLineReduction
+			}
+			for _, r := range others {
+				d.Eliminated = append(d.Eliminated, SudokuDecoder(r).(SudokuCell))
+				m.hideRow(r)
+			}
+			return d, true
+		}
+	}
+	return Deduction{}, false
+}
+
+// Tells whether the row of the node has a node in the column.
+func rowHasCol(r *Node, col *Node) bool {
+	for _, n := range r.RowNodes() {
+		if n.Col == col {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the row of a solution of the remaining matrix filling the cell
+// having the fewest candidates, nil if there is no solution.
+func (s *SudokuSolver) guess() *Node {
+	m := s.matrix
+	var cell *Node
+	root := m.Root()
+	for col := root.Right; col != root; col = col.Right {
+		if unitKind(col.Name) == 'x' && (cell == nil || col.Size < cell.Size) {
+			cell = col
+		}
+	}
+	g := &racer{matrix: m, stop: new(atomic.Bool)}
+	m.Search(m.NewSolution(), 0, g)
+	if g.solution == nil {
+		return nil
+	}
+	for _, r := range *g.solution {
+		if rowHasCol(r, cell) {
+			return r
+		}
+	}
+	return nil
+}
+
+// Solves the grid the way a human would, applying the simplest technique
+// available at each step: singles, then pointing pairs and box/line
+// reductions, and only guessing with the search once they are exhausted.
+// Returns the grid and the path of deductions, which explains the solution
+// step by step. The matrix is restored afterwards. Fails with ErrConflict if
+// the givens contradict each other, and returns the partial grid along with
+// ErrConflict if there is no solution.
+func (s *SudokuSolver) Explain(sudoku [][]int) ([][]int, []Deduction, error) {
+	s.Reset()
+	m := s.matrix
+	O := m.NewSolution()
+	rows := s.givens(sudoku)
+	if rows == nil {
+		return nil, nil, ErrConflict
+	}
+	k, err := m.Require(O, rows...)
+	if err != nil {
+		return nil, nil, err
+	}
+	steps := make([]Deduction, 0)
+	// undo log, true for a row placed and false for a row hidden
+	undo := make([]bool, 0)
+	root := m.Root()
+	for root.Right != root && !m.DeadEnd() {
+		if n := s.singles(O, k, &steps); n > 0 {
+			for ; n > 0; n-- {
+				undo = append(undo, true)
+				k++
+			}
+			continue
+		}
+		if d, ok := s.lockedCandidates(); ok {
+			steps = append(steps, d)
+			for range d.Eliminated {
+				undo = append(undo, false)
+			}
+			continue
+		}
+		r := s.guess()
+		if r == nil {
+			break
+		}
+		steps = append(steps, Deduction{Technique: Guess, Cell: SudokuDecoder(r).(SudokuCell), Unit: r.Col.Name})
+		O.Set(k, r)
+		m.cover(r.Col, k)
+		for j := r.Right; j != r; j = j.Right {
+			m.cover(j.Col, k)
+		}
+		undo = append(undo, true)
+		k++
+	}
+	solved := root.Right == root
+	grid := s.Grid(O)
+	for i := len(undo) - 1; i >= 0; i-- {
+		if undo[i] {
+			k--
+			m.Unpropagate(O, k, 1)
+		} else {
+			m.unhideRow()
+		}
+	}
+	m.Release(O, k)
+	if !solved {
+		return grid, steps, ErrConflict
+	}
+	return grid, steps, nil
+}
@@ -0,0 +1,70 @@
+package cover
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	s := NewSudokuSolver(9)
+	for _, p := range []string{
+		easySudoku,
+		"1....7.9..3..2...8..96..5....53..9...1..8...26....4...3......1..4......7..7...3..",
+		"8..........36......7..9.2...5...7.......457.....1...3...1....68..85...1..9....4..",
+	} {
+		given := parseGrid(p)
+		grid, steps, err := s.Explain(given)
+		if err != nil {
+			t.Fatalf("Cannot explain %v: %v", p, err)
+		}
+		checkGrid(t, given, grid)
+		placed, guesses := 0, 0
+		for _, d := range steps {
+			if len(d.Eliminated) == 0 {
+				placed++
+			}
+			if d.Technique == Guess {
+				guesses++
+			}
+			if d.String() == "" {
+				t.Errorf("Deduction %#v has no text", d)
+			}
+		}
+		if empty := strings.Count(p, "."); placed != empty {
+			t.Errorf("Path places %v digits (wants %v)", placed, empty)
+		}
+		if p == easySudoku && guesses > 0 {
+			t.Errorf("Easy sudoku needs %v guesses", guesses)
+		}
+	}
+	if _, _, err := s.Explain(parseGrid("11" + strings.Repeat(".", 79))); err == nil {
+		t.Errorf("Contradicting givens explained")
+	}
+	if cols := s.matrix.ColCount(); cols != 324 || len(s.matrix.hidden) != 0 {
+		t.Errorf("Matrix has %v columns and %v hidden rows after explaining", cols, len(s.matrix.hidden))
+	}
+}
+
+func TestLockedCandidates(t *testing.T) {
+	s := NewSudokuSolver(9)
+	// a puzzle the singles leave with pointing pairs to find
+	p := "4.....8.5.3..........7......2.....6.....8.4......1.......6.3.7.5..2.....1.4......"
+	_, steps, err := s.Explain(parseGrid(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	locked := 0
+	for _, d := range steps {
+		if d.Technique == PointingPair || d.Technique == BoxLineReduction {
+			locked++
+			for _, c := range d.Eliminated {
+				if c.Digit != d.Eliminated[0].Digit {
+					t.Errorf("%v removes several digits", d)
+				}
+			}
+		}
+	}
+	if locked == 0 {
+		t.Errorf("No locked candidates in %v steps", len(steps))
+	}
+}
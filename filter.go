@@ -0,0 +1,29 @@
+package cover
+
+// Hides the remaining rows, by index, for which keep returns false, until
+// RestoreRows(). This answers what-if questions, such as solving without the
+// shifts of an employee, without rebuilding the matrix. The matrix must be
+// restored, since hidden rows must be put back on the matrix they left.
+// Returns the number of rows hidden.
+func (m *SparseMatrix) FilterRows(keep func(row int) bool) int {
+	rows := m.rowNodes()
+	n, count := 0, m.rowCount()
+	for i := 0; i < count; i++ {
+		if r, ok := rows[i]; ok && !keep(i) {
+			m.hideRow(r)
+			n++
+		}
+	}
+	return n
+}
+
+// Hides the rows for which keep returns false, see SparseMatrix.FilterRows().
+// Call Reset() then RestoreRows() to go back to the whole problem.
+func (s *Solver) FilterRows(keep func(row int) bool) int {
+	return s.matrix.FilterRows(keep)
+}
+
+// Puts back the rows hidden by FilterRows() or Simplify().
+func (s *Solver) RestoreRows() {
+	s.matrix.RestoreRows()
+}
@@ -0,0 +1,29 @@
+package cover
+
+import (
+	"testing"
+)
+
+func TestFilterRows(t *testing.T) {
+	h := []string{"A", "B", "C", "D", "E", "F", "G"}
+	// row 3 is in the only solution
+	if n := NewSolver(knuth(), h, WithForbiddenRows(3)).Count(); n.Int64() != 0 {
+		t.Errorf("Knuth without row 3 has %v solutions (wants %v)", n, 0)
+	}
+	odd := func(row int) bool { return row%2 == 1 }
+	if n := NewSolver(knuth(), h, WithOnlyRows(odd)).Count(); n.Int64() != 0 {
+		t.Errorf("Knuth odd rows have %v solutions (wants %v)", n, 0)
+	}
+	s := NewSolver(knuth(), h)
+	if n := s.FilterRows(func(row int) bool { return row != 2 && row != 5 }); n != 2 {
+		t.Errorf("Filter hides %v rows (wants %v)", n, 2)
+	}
+	if O := s.Solve(); O.Len() != 3 {
+		t.Errorf("Knuth without unused rows has no solution")
+	}
+	s.Reset()
+	s.RestoreRows()
+	if n := s.matrix.rowCount(); n != 6 || s.Count().Int64() != 1 {
+		t.Errorf("Restored matrix has %v rows and %v solutions", n, s.Count())
+	}
+}
@@ -0,0 +1,190 @@
+package cover
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Text representation of a sudoku grid.
+type Format int
+
+const (
+	// One char per cell on a single line, 81 chars for a classic sudoku.
+	// Empty cells are dots, values above 9 are letters.
+	Line Format = iota
+	// Lines of digits framed by ASCII boxes, see FormatBox().
+	Box
+	// One comma separated line per row, empty cells being 0.
+	CSV
+	// Object holding the grid and the cells given by the puzzle.
+	JSON
+	// Rows removed and added since the previous solution, for solution
+	// streams only, see WriteSolutions(). Grids fall back to Box.
+	Delta
+	// Lines of digits framed by Unicode boxes.
+	Unicode
+)
+
+func (f Format) String() string {
+	switch f {
+	case Line:
+		return "line"
+	case Box:
+		return "box"
+	case CSV:
+		return "csv"
+	case JSON:
+		return "json"
+	case Delta:
+		return "delta"
+	case Unicode:
+		return "unicode"
+	}
+	return "Format(" + strconv.Itoa(int(f)) + ")"
+}
+
+// Formats a grid, 0 being an empty cell. The puzzle gives the givens flagged
+// by the JSON format, it may be nil when there are none.
+func FormatGrid(grid, puzzle [][]int, f Format) string {
+	switch f {
+	case Line:
+		var b strings.Builder
+		for _, line := range grid {
+			for _, cell := range line {
+				if cell == 0 {
+					b.WriteByte('.')
+				} else {
+					b.WriteString(strings.ToUpper(strconv.FormatInt(int64(cell), 36)))
+				}
+			}
+		}
+		return b.String()
+	case CSV:
+		var b bytes.Buffer
+		w := csv.NewWriter(&b)
+		for _, line := range grid {
+			record := make([]string, len(line))
+			for j, cell := range line {
+				record[j] = strconv.Itoa(cell)
+			}
+			w.Write(record)
+		}
+		w.Flush()
+		return b.String()
+	case JSON:
+		given := make([][]bool, len(grid))
+		for i, line := range grid {
+			given[i] = make([]bool, len(line))
+			for j := range line {
+				given[i][j] = i < len(puzzle) && j < len(puzzle[i]) && puzzle[i][j] != 0
+			}
+		}
+		data, _ := json.Marshal(struct {
+			Grid  [][]int  `json:"grid"`
+			Given [][]bool `json:"given"`
+		}{grid, given})
+		return string(data)
+	case Unicode:
+		return FormatBox(grid, BoxStyle{Unicode: true})
+	}
+	return FormatBox(grid, BoxStyle{})
+}
+
+// Options of the box format.
+type BoxStyle struct {
+	// draws the borders with Unicode box drawing characters rather than ASCII
+	Unicode bool
+	// writes the digits of hexadokus from 0 to F, empty cells being dots
+	Hex bool
+}
+
+// Formats a grid framed by boxes, the cells being right aligned so that grids
+// of dimension 16 or 25 line up.
+func FormatBox(grid [][]int, style BoxStyle) string {
+	var b strings.Builder
+	sdim := int(math.Sqrt(float64(len(grid))))
+	cell := func(v int) string {
+		if !style.Hex {
+			return strconv.Itoa(v)
+		}
+		if v == 0 {
+			return "."
+		}
+		return strings.ToUpper(strconv.FormatInt(int64(v-1), 16))
+	}
+	width := len(cell(len(grid)))
+	// borders: left, line, crossing and right, for the top, middle and bottom
+	borders := [3]string{"+-++", "+-++", "+-++"}
+	vertical := "|"
+	if style.Unicode {
+		borders = [3]string{"┌─┬┐", "├─┼┤", "└─┴┘"}
+		vertical = "│"
+	}
+	delim := func(i int) string {
+		r := []rune(borders[i])
+		segment := strings.Repeat(string(r[1]), sdim*(width+1)+1)
+		return string(r[0]) + strings.Repeat(segment+string(r[2]), sdim-1) + segment + string(r[3]) + "\n"
+	}
+	for i, line := range grid {
+		if i == 0 {
+			b.WriteString(delim(0))
+		} else if i%sdim == 0 {
+			b.WriteString(delim(1))
+		}
+		for j, v := range line {
+			if j%sdim == 0 {
+				if j > 0 {
+					b.WriteString(" ")
+				}
+				b.WriteString(vertical)
+			}
+			b.WriteString(fmt.Sprintf(" %*v", width, cell(v)))
+		}
+		b.WriteString(" " + vertical + "\n")
+	}
+	b.WriteString(delim(2))
+	return b.String()
+}
+
+// Parses a grid copied from a newspaper or a web page. Digits are values, and
+// any of . _ 0 * ? is an empty cell. Everything else, like pipes, spaces or
+// box drawing characters, separates the cells and is ignored, as are the
+// lines having a letter, e.g. a title. The grid must be square, with a square
+// dimension like 4 or 9.
+func ParseGrid(s string) ([][]int, error) {
+	cells := make([]int, 0, 81)
+	for _, line := range strings.Split(s, "\n") {
+		if strings.IndexFunc(line, unicode.IsLetter) >= 0 {
+			continue
+		}
+		for _, c := range line {
+			switch {
+			case c >= '1' && c <= '9':
+				cells = append(cells, int(c-'0'))
+			case strings.ContainsRune("._0*?", c):
+				cells = append(cells, 0)
+			}
+		}
+	}
+	dim := int(math.Sqrt(float64(len(cells))))
+	sdim := int(math.Sqrt(float64(dim)))
+	if dim < 4 || dim*dim != len(cells) || sdim*sdim != dim {
+		return nil, fmt.Errorf("grid has %v cells, not a sudoku", len(cells))
+	}
+	grid := make([][]int, dim)
+	for i := range grid {
+		grid[i] = cells[i*dim : (i+1)*dim]
+		for j, v := range grid[i] {
+			if v > dim {
+				return nil, fmt.Errorf("cell %v,%v is %v, above %v", i, j, v, dim)
+			}
+		}
+	}
+	return grid, nil
+}
@@ -0,0 +1,155 @@
+package cover
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Sudoku imported from the JSON of f-puzzles, which SudokuPad imports too:
+// its givens and the variants its constraints map to.
+type FPuzzle struct {
+	Title   string
+	Size    int
+	Givens  [][]int
+	Variant Stack
+	// constraints which map to no variant, sorted
+	Unsupported []string
+}
+
+// Cell of f-puzzles, like "R1C2".
+type fpuzzlesCell struct {
+	Cell string `json:"cell"`
+}
+
+// Keys of the f-puzzles JSON which are no constraints: metadata, or
+// decorations which do not restrict the digits.
+var fpuzzlesIgnored = map[string]bool{
+	"size": true, "grid": true, "title": true, "author": true, "ruleset": true,
+	"solution": true, "text": true, "line": true, "rectangle": true,
+	"circle": true, "cage": true, "disabledlogic": true, "truecandidatesoptions": true,
+}
+
+// Parses the JSON of an f-puzzles sudoku, not its compressed form. The
+// constraints X, diagonal, anti-knight, odd and even map to variants, the
+// others are listed as unsupported. Only sizes whose boxes are square, like
+// 4x4 and 9x9, are supported.
+func ParseFPuzzles(data []byte) (*FPuzzle, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("f-puzzles: %w", err)
+	}
+	var doc struct {
+		Size  int    `json:"size"`
+		Title string `json:"title"`
+		Grid  [][]struct {
+			Value  int  `json:"value"`
+			Given  bool `json:"given"`
+			Region *int `json:"region"`
+		} `json:"grid"`
+		DiagonalPlus  bool           `json:"diagonal+"`
+		DiagonalMinus bool           `json:"diagonal-"`
+		AntiKnight    bool           `json:"antiknight"`
+		Odd           []fpuzzlesCell `json:"odd"`
+		Even          []fpuzzlesCell `json:"even"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("f-puzzles: %w", err)
+	}
+	sdim := int(math.Sqrt(float64(doc.Size)))
+	if doc.Size < 1 || sdim*sdim != doc.Size {
+		return nil, fmt.Errorf("f-puzzles: size %v: %w", doc.Size, ErrUnsupported)
+	}
+	if len(doc.Grid) != doc.Size {
+		return nil, fmt.Errorf("f-puzzles: grid has %v rows (wants %v)", len(doc.Grid), doc.Size)
+	}
+	p := &FPuzzle{Title: doc.Title, Size: doc.Size, Givens: make([][]int, doc.Size), Variant: Stack{}}
+	unsupported := map[string]bool{}
+	for i, row := range doc.Grid {
+		if len(row) != doc.Size {
+			return nil, fmt.Errorf("f-puzzles: row %v has %v cells (wants %v)", i, len(row), doc.Size)
+		}
+		p.Givens[i] = make([]int, doc.Size)
+		for j, c := range row {
+			if c.Given {
+				p.Givens[i][j] = c.Value
+			}
+			if c.Region != nil {
+				unsupported["region"] = true
+			}
+		}
+	}
+	switch {
+	case doc.DiagonalPlus && doc.DiagonalMinus:
+		p.Variant = append(p.Variant, XVariant{})
+	case doc.DiagonalPlus:
+		// bottom left to top right
+		p.Variant = append(p.Variant, DiagonalVariant{Anti: true})
+	case doc.DiagonalMinus:
+		p.Variant = append(p.Variant, DiagonalVariant{})
+	}
+	if doc.AntiKnight {
+		p.Variant = append(p.Variant, AntiKnightVariant{})
+	}
+	if len(doc.Odd) > 0 || len(doc.Even) > 0 {
+		parity := make([][]int, doc.Size)
+		for i := range parity {
+			parity[i] = make([]int, doc.Size)
+		}
+		for k, cells := range [][]fpuzzlesCell{doc.Odd, doc.Even} {
+			for _, c := range cells {
+				var x, y int
+				if n, err := fmt.Sscanf(c.Cell, "R%dC%d", &x, &y); n != 2 || err != nil || x < 1 || x > doc.Size || y < 1 || y > doc.Size {
+					return nil, fmt.Errorf("f-puzzles: bad cell %q", c.Cell)
+				}
+				parity[x-1][y-1] = k + 1
+			}
+		}
+		p.Variant = append(p.Variant, OddEvenVariant{parity})
+	}
+	for key, value := range raw {
+		switch key {
+		case "diagonal+", "diagonal-", "antiknight", "odd", "even":
+			continue
+		}
+		if !fpuzzlesIgnored[key] && !emptyJSON(value) {
+			unsupported[key] = true
+		}
+	}
+	for key := range unsupported {
+		p.Unsupported = append(p.Unsupported, key)
+	}
+	sort.Strings(p.Unsupported)
+	return p, nil
+}
+
+// Tells whether a JSON value sets nothing: false, null, an empty array or
+// object.
+func emptyJSON(value json.RawMessage) bool {
+	var v interface{}
+	if json.Unmarshal(value, &v) != nil {
+		return false
+	}
+	switch v := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// Returns the solver of the puzzle, see NewVariantSolver(). Returns
+// ErrUnsupported if the puzzle has unsupported constraints, which the
+// solutions would ignore.
+func (p *FPuzzle) Solver(opts ...Option) (*SudokuSolver, error) {
+	if len(p.Unsupported) > 0 {
+		return nil, fmt.Errorf("f-puzzles constraints %v: %w", p.Unsupported, ErrUnsupported)
+	}
+	return NewVariantSolver(p.Size, p.Variant, opts...)
+}
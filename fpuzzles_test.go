@@ -0,0 +1,71 @@
+package cover
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const fpuzzlesX = `{
+	"size": 4,
+	"title": "Tiny X",
+	"author": "test",
+	"grid": [
+		[{"value": 1, "given": true}, {}, {}, {}],
+		[{}, {}, {"value": 3}, {}],
+		[{}, {}, {}, {}],
+		[{}, {}, {}, {}]
+	],
+	"diagonal+": true,
+	"diagonal-": true,
+	"even": [{"cell": "R4C4"}],
+	"killercage": [],
+	"text": [{"cells": ["R1C1"], "value": "note"}]
+}`
+
+func TestParseFPuzzles(t *testing.T) {
+	p, err := ParseFPuzzles([]byte(fpuzzlesX))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Title != "Tiny X" || p.Size != 4 || len(p.Unsupported) > 0 {
+		t.Errorf("Parsed %+v", p)
+	}
+	// the value entered by the solver is no given
+	if want := [][]int{{1, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}}; !reflect.DeepEqual(p.Givens, want) {
+		t.Errorf("Givens %v (wants %v)", p.Givens, want)
+	}
+	s, err := p.Solver()
+	if err != nil {
+		t.Fatal(err)
+	}
+	grid := s.Grid(s.Solve(p.Givens))
+	main, anti := map[int]bool{}, map[int]bool{}
+	for i := 0; i < 4; i++ {
+		main[grid[i][i]] = true
+		anti[grid[i][3-i]] = true
+	}
+	if len(main) != 4 || len(anti) != 4 {
+		t.Errorf("Diagonals repeat digits: %v", grid)
+	}
+	if grid[0][0] != 1 || grid[3][3]%2 != 0 {
+		t.Errorf("Givens or parity not met: %v", grid)
+	}
+}
+
+func TestParseFPuzzlesUnsupported(t *testing.T) {
+	p, err := ParseFPuzzles([]byte(`{"size": 4, "grid": [[{}, {}, {}, {}], [{}, {}, {}, {}], [{}, {}, {}, {}], [{}, {}, {}, {"region": 1}]],
+		"killercage": [{"cells": ["R1C1", "R1C2"], "value": "3"}], "thermometer": [{"lines": [["R1C1", "R2C1"]]}], "antiking": false}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"killercage", "region", "thermometer"}; !reflect.DeepEqual(p.Unsupported, want) {
+		t.Errorf("Unsupported %v (wants %v)", p.Unsupported, want)
+	}
+	if _, err := p.Solver(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Solver gives %v (wants ErrUnsupported)", err)
+	}
+	if _, err := ParseFPuzzles([]byte(`{"size": 6, "grid": []}`)); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Size 6 gives %v (wants ErrUnsupported)", err)
+	}
+}
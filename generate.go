@@ -0,0 +1,79 @@
+package cover
+
+import (
+	"hash/fnv"
+	"math/rand"
+	"time"
+)
+
+// Returns the grid of the given dimension having the given cells of the
+// complete grid, by index x*dim+y, the others being empty.
+func gridFromClues(grid [][]int, clues map[int]bool) [][]int {
+	dim := len(grid)
+	puzzle := make([][]int, dim)
+	for i := range puzzle {
+		puzzle[i] = make([]int, dim)
+		for j := range puzzle[i] {
+			if clues[i*dim+j] {
+				puzzle[i][j] = grid[i][j]
+			}
+		}
+	}
+	return puzzle
+}
+
+// Generates a 9x9 sudoku having a unique solution, rated with the default
+// calibration. Each attempt fills a random grid and removes its cells in a
+// random order as long as the solution stays unique, keeping the puzzle of
+// the fewest clues having the difficulty. Puzzles of the difficulty are
+// rare when it is high, so after a few attempts the puzzle of the closest
+// difficulty is returned. The same seed gives the same puzzle.
+func GeneratePuzzle(seed int64, d Difficulty) (puzzle, solution [][]int) {
+	rnd := rand.New(rand.NewSource(seed))
+	empty := make([][]int, 9)
+	for i := range empty {
+		empty[i] = make([]int, 9)
+	}
+	gap := -1
+	for attempt := 0; attempt < 10 && gap != 0; attempt++ {
+		s := NewSudokuSolver(9, WithRandomRows(rnd.Int63()))
+		grid := s.Grid(s.Solve(empty))
+		clues := map[int]bool{}
+		for cell := 0; cell < 81; cell++ {
+			clues[cell] = true
+		}
+		for _, cell := range rnd.Perm(81) {
+			delete(clues, cell)
+			// in order, for the search to be reproducible
+			kept := make([]int, 0, len(clues))
+			for c := 0; c < 81; c++ {
+				if clues[c] {
+					kept = append(kept, c)
+				}
+			}
+			if s.otherCompletion(grid, kept) != nil {
+				clues[cell] = true
+				continue
+			}
+			p := gridFromClues(grid, clues)
+			r := int(s.Rate(p, &DefaultCalibration) - d)
+			if r < 0 {
+				r = -r
+			}
+			if gap < 0 || r <= gap {
+				puzzle, solution, gap = p, grid, r
+			}
+		}
+	}
+	return puzzle, solution
+}
+
+// Generates the puzzle of the day of the given difficulty, see
+// GeneratePuzzle(). The seed only depends on the calendar date, in the
+// location of the time, and on the difficulty, so that every server
+// generates the same puzzle without coordination.
+func GenerateDaily(date time.Time, d Difficulty) (puzzle, solution [][]int) {
+	h := fnv.New64a()
+	h.Write([]byte(date.Format("2006-01-02") + "/" + d.String()))
+	return GeneratePuzzle(int64(h.Sum64()), d)
+}
@@ -0,0 +1,30 @@
+package cover
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGenerateDaily(t *testing.T) {
+	day := time.Date(2024, 3, 14, 8, 0, 0, 0, time.UTC)
+	s := NewSudokuSolver(9)
+	for _, d := range []Difficulty{Easy, Medium} {
+		puzzle, solution := GenerateDaily(day, d)
+		checkGrid(t, puzzle, solution)
+		if r := s.Rate(puzzle, &DefaultCalibration); r != d {
+			t.Errorf("Daily %v puzzle rated %v", d, r)
+		}
+		if s.Solve(puzzle); s.CountCompletions(puzzle).Int64() != 1 {
+			t.Errorf("Daily %v puzzle has several solutions", d)
+		}
+		again, _ := GenerateDaily(day.Add(10*time.Hour), d)
+		if !reflect.DeepEqual(again, puzzle) {
+			t.Errorf("Daily %v puzzle changes within the day", d)
+		}
+		next, _ := GenerateDaily(day.AddDate(0, 0, 1), d)
+		if reflect.DeepEqual(next, puzzle) {
+			t.Errorf("Daily %v puzzle is the same the next day", d)
+		}
+	}
+}
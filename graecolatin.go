@@ -0,0 +1,72 @@
+package cover
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Builds a constraint matrix for a pair of orthogonal Latin squares of order n.
+// Each row puts the pair of symbols (a, b) in the cell (x, y).
+// Constraint order is cell, a per row, a per col, b per row, b per col, pair.
+func GraecoLatinConstraintMatrix(n int) (matrix [][]int, headers []string) {
+	sq := n * n
+	rowCount := sq * sq
+	colCount := sq * 6
+	headers = make([]string, 0, colCount)
+	for i := 0; i < sq; i++ {
+		headers = append(headers, fmt.Sprintf("%v,%v", i/n, i%n))
+	}
+	for _, p := range []string{"ar", "ac", "br", "bc"} {
+		for i := 0; i < sq; i++ {
+			headers = append(headers, fmt.Sprintf("%v%v:%v", p, i/n, i%n))
+		}
+	}
+	for i := 0; i < sq; i++ {
+		headers = append(headers, fmt.Sprintf("%v:%v", i/n, i%n))
+	}
+	matrix = make([][]int, rowCount)
+	for i := 0; i < rowCount; i++ {
+		matrix[i] = make([]int, colCount)
+		cell := i / sq
+		x, y := cell/n, cell%n
+		a, b := (i%sq)/n, i%n
+		matrix[i][cell] = 1
+		matrix[i][sq+x*n+a] = 1
+		matrix[i][2*sq+y*n+a] = 1
+		matrix[i][3*sq+x*n+b] = 1
+		matrix[i][4*sq+y*n+b] = 1
+		matrix[i][5*sq+a*n+b] = 1
+	}
+	return
+}
+
+// Searches a pair of orthogonal Latin squares of order n.
+// The last return value is false when no such pair exists. Beware that
+// proving it for n = 6 means exhausting a huge search tree.
+func FindGraecoLatin(n int) (a, b [][]int, ok bool) {
+	solver := NewSolver(GraecoLatinConstraintMatrix(n))
+	solver.Solve()
+	if len(solver.Solutions) == 0 {
+		return nil, nil, false
+	}
+	a = make([][]int, n)
+	b = make([][]int, n)
+	for i := 0; i < n; i++ {
+		a[i] = make([]int, n)
+		b[i] = make([]int, n)
+	}
+	for _, r := range *solver.Solutions[0] {
+		var x, y, p, q int
+		for _, m := range r.RowNodes() {
+			name := m.Col.Name
+			if strings.Contains(name, ",") {
+				fmt.Sscanf(name, "%d,%d", &x, &y)
+			} else if name[0] >= '0' && name[0] <= '9' {
+				fmt.Sscanf(name, "%d:%d", &p, &q)
+			}
+		}
+		a[x][y] = p
+		b[x][y] = q
+	}
+	return a, b, true
+}
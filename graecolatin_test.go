@@ -0,0 +1,37 @@
+package cover
+
+import (
+	"testing"
+)
+
+func TestFindGraecoLatin(t *testing.T) {
+	for _, n := range []int{3, 4, 5} {
+		a, b, ok := FindGraecoLatin(n)
+		if !ok {
+			t.Fatalf("No Graeco-Latin square of order %v found", n)
+		}
+		pairs := map[[2]int]bool{}
+		for i := 0; i < n; i++ {
+			rowA, rowB, colA, colB := map[int]bool{}, map[int]bool{}, map[int]bool{}, map[int]bool{}
+			for j := 0; j < n; j++ {
+				rowA[a[i][j]] = true
+				rowB[b[i][j]] = true
+				colA[a[j][i]] = true
+				colB[b[j][i]] = true
+				pairs[[2]int{a[i][j], b[i][j]}] = true
+			}
+			if len(rowA) != n || len(rowB) != n || len(colA) != n || len(colB) != n {
+				t.Errorf("Order %v: squares are not latin at index %v\n%v\n%v", n, i, a, b)
+			}
+		}
+		if len(pairs) != n*n {
+			t.Errorf("Order %v: squares are not orthogonal, %v distinct pairs (wants %v)", n, len(pairs), n*n)
+		}
+	}
+}
+
+func TestFindGraecoLatinNone(t *testing.T) {
+	if _, _, ok := FindGraecoLatin(2); ok {
+		t.Errorf("Order 2 has no Graeco-Latin square")
+	}
+}
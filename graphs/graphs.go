@@ -0,0 +1,193 @@
+/*
+Package graphs encodes graph problems as exact cover problems, and decodes the
+rows of their solutions back into edges and vertices.
+*/
+package graphs
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/qur2/go-cover"
+	"github.com/qur2/go-cover/problems"
+)
+
+// Undirected simple graph over the vertices 0 to N-1.
+type Graph struct {
+	N   int
+	adj []map[int]bool
+}
+
+// Creates a graph of n vertices and no edge.
+func New(n int) *Graph {
+	g := &Graph{N: n, adj: make([]map[int]bool, n)}
+	for v := range g.adj {
+		g.adj[v] = map[int]bool{}
+	}
+	return g
+}
+
+// Adds the edge between u and v, ignoring loops.
+func (g *Graph) AddEdge(u, v int) {
+	if u != v {
+		g.adj[u][v] = true
+		g.adj[v][u] = true
+	}
+}
+
+// Tells whether u and v are adjacent.
+func (g *Graph) Adjacent(u, v int) bool {
+	return g.adj[u][v]
+}
+
+// Returns the edges, each one as its two vertices in increasing order, sorted.
+func (g *Graph) Edges() []Edge {
+	edges := make([]Edge, 0)
+	for u := 0; u < g.N; u++ {
+		for _, v := range g.Neighbors(u) {
+			if u < v {
+				edges = append(edges, Edge{u, v})
+			}
+		}
+	}
+	return edges
+}
+
+// Returns the neighbors of v, sorted.
+func (g *Graph) Neighbors(v int) []int {
+	n := make([]int, 0, len(g.adj[v]))
+	for u := range g.adj[v] {
+		n = append(n, u)
+	}
+	sort.Ints(n)
+	return n
+}
+
+// Edge given by its two vertices.
+type Edge [2]int
+
+// Returns one column per vertex, named after it.
+func vertexHeaders(n int) []string {
+	h := make([]string, n)
+	for v := range h {
+		h[v] = fmt.Sprint(v)
+	}
+	return h
+}
+
+// Builds a problem whose rows are the given vertex sets over the vertex
+// columns, each row decoding to obj(i) for the set of index i.
+func vertexSets(name string, n int, sets [][]int, obj func(i int) interface{}) *problems.Problem {
+	matrix := make([][]int, len(sets))
+	for i, set := range sets {
+		matrix[i] = make([]int, n)
+		for _, v := range set {
+			matrix[i][v] = 1
+		}
+	}
+	return &problems.Problem{
+		Name:    name,
+		Matrix:  matrix,
+		Headers: vertexHeaders(n),
+		Decoder: cover.DecoderFunc(func(row *cover.Node) interface{} {
+			return obj(row.Row)
+		}),
+	}
+}
+
+// Encodes the perfect matchings of the graph: each edge is a row covering its
+// two vertices. Rows decode to Edge values.
+func PerfectMatching(g *Graph) *problems.Problem {
+	edges := g.Edges()
+	sets := make([][]int, len(edges))
+	for i, e := range edges {
+		sets[i] = e[:]
+	}
+	return vertexSets("perfect-matching", g.N, sets, func(i int) interface{} {
+		return edges[i]
+	})
+}
+
+// Returns the cliques of at most max vertices, max being 0 for no limit, each
+// one sorted, in lexicographic order.
+func (g *Graph) Cliques(max int) [][]int {
+	cliques := make([][]int, 0)
+	var grow func(clique []int, from int)
+	grow = func(clique []int, from int) {
+		cliques = append(cliques, append([]int{}, clique...))
+		if max > 0 && len(clique) == max {
+			return
+		}
+		for v := from; v < g.N; v++ {
+			ok := true
+			for _, u := range clique {
+				if !g.adj[u][v] {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				grow(append(clique, v), v+1)
+			}
+		}
+	}
+	for v := 0; v < g.N; v++ {
+		grow([]int{v}, v+1)
+	}
+	return cliques
+}
+
+// Encodes the partitions of the vertices into cliques of at most max vertices,
+// max being 0 for no limit: each clique is a row covering its vertices. The
+// number of cliques grows fast with dense graphs. A partition into the fewest
+// cliques is found by SolveMinCost() with a cost of 1 per row. Rows decode to
+// the sorted vertices of their clique.
+func CliquePartition(g *Graph, max int) *problems.Problem {
+	cliques := g.Cliques(max)
+	return vertexSets("clique-partition", g.N, cliques, func(i int) interface{} {
+		return cliques[i]
+	})
+}
+
+// Encodes the perfect dominating sets of the graph, in which every vertex is
+// either in the set or adjacent to exactly one vertex of it, and not both:
+// each vertex is a row covering itself and its neighbors. Rows decode to
+// their vertex.
+func PerfectDominatingSet(g *Graph) *problems.Problem {
+	sets := make([][]int, g.N)
+	for v := range sets {
+		sets[v] = append([]int{v}, g.Neighbors(v)...)
+	}
+	return vertexSets("perfect-dominating-set", g.N, sets, func(i int) interface{} {
+		return i
+	})
+}
+
+// Returns the edges decoded from the rows of a solution of PerfectMatching().
+func DecodeEdges(objs []interface{}) []Edge {
+	edges := make([]Edge, len(objs))
+	for i, obj := range objs {
+		edges[i] = obj.(Edge)
+	}
+	return edges
+}
+
+// Returns the sorted vertices decoded from the rows of a solution of
+// PerfectDominatingSet().
+func DecodeVertices(objs []interface{}) []int {
+	vs := make([]int, len(objs))
+	for i, obj := range objs {
+		vs[i] = obj.(int)
+	}
+	sort.Ints(vs)
+	return vs
+}
+
+// Returns the cliques decoded from the rows of a solution of CliquePartition().
+func DecodeCliques(objs []interface{}) [][]int {
+	cliques := make([][]int, len(objs))
+	for i, obj := range objs {
+		cliques[i] = obj.([]int)
+	}
+	return cliques
+}
@@ -0,0 +1,83 @@
+package graphs
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Returns the complete graph of n vertices.
+func complete(n int) *Graph {
+	g := New(n)
+	for u := 0; u < n; u++ {
+		for v := u + 1; v < n; v++ {
+			g.AddEdge(u, v)
+		}
+	}
+	return g
+}
+
+// Returns the cycle of n vertices.
+func cycle(n int) *Graph {
+	g := New(n)
+	for v := 0; v < n; v++ {
+		g.AddEdge(v, (v+1)%n)
+	}
+	return g
+}
+
+func TestPerfectMatching(t *testing.T) {
+	for _, c := range []struct {
+		g    *Graph
+		want int64
+	}{{complete(4), 3}, {complete(6), 15}, {cycle(6), 2}, {cycle(5), 0}} {
+		if n := PerfectMatching(c.g).Solver().Count(); n.Int64() != c.want {
+			t.Errorf("Graph of %v vertices has %v perfect matchings (wants %v)", c.g.N, n, c.want)
+		}
+	}
+	s := PerfectMatching(cycle(4)).Solver()
+	edges := DecodeEdges(s.SolveDecoded())
+	if len(edges) != 2 || edges[0][0] == edges[1][0] || edges[0][1] == edges[1][1] {
+		t.Errorf("Perfect matching of C4 decoded as %v", edges)
+	}
+}
+
+func TestCliquePartition(t *testing.T) {
+	// two triangles joined by the edge 2-3
+	g := New(6)
+	for _, e := range []Edge{{0, 1}, {1, 2}, {0, 2}, {3, 4}, {4, 5}, {3, 5}, {2, 3}} {
+		g.AddEdge(e[0], e[1])
+	}
+	p := CliquePartition(g, 0)
+	costs := make([]float64, len(p.Matrix))
+	for i := range costs {
+		costs[i] = 1
+	}
+	s := p.Solver()
+	O, n := s.SolveMinCost(costs, true)
+	cliques := DecodeCliques(s.Decode(O))
+	if n != 2 || !reflect.DeepEqual(cliques, [][]int{{0, 1, 2}, {3, 4, 5}}) && !reflect.DeepEqual(cliques, [][]int{{3, 4, 5}, {0, 1, 2}}) {
+		t.Errorf("Fewest cliques are %v (wants the two triangles)", cliques)
+	}
+	if pairs := CliquePartition(complete(4), 2); len(pairs.Matrix) != 10 {
+		t.Errorf("K4 has %v cliques of at most 2 vertices (wants %v)", len(pairs.Matrix), 10)
+	}
+}
+
+func TestPerfectDominatingSet(t *testing.T) {
+	// path 0-1-2-3-4-5: {1, 4} dominates every vertex once
+	g := New(6)
+	for v := 0; v < 5; v++ {
+		g.AddEdge(v, v+1)
+	}
+	s := PerfectDominatingSet(g).Solver()
+	all := s.SolveAllDecoded()
+	found := false
+	for _, objs := range all {
+		if reflect.DeepEqual(DecodeVertices(objs), []int{1, 4}) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Perfect dominating sets of P6 are %v, missing [1 4]", all)
+	}
+}
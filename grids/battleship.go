@@ -0,0 +1,338 @@
+package grids
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/qur2/go-cover"
+)
+
+// Fleet of the usual 10x10 battleship solitaire: a battleship, two cruisers,
+// three destroyers and four submarines.
+var StandardFleet = []int{4, 3, 3, 2, 2, 2, 1, 1, 1, 1}
+
+// Ship of a battleship solution, by its top left cell.
+type Ship struct {
+	Cell
+	Length   int
+	Vertical bool
+}
+
+func (s Ship) String() string {
+	if s.Vertical {
+		return fmt.Sprintf("%v|%v", s.Cell, s.Length)
+	}
+	return fmt.Sprintf("%v-%v", s.Cell, s.Length)
+}
+
+// Returns the cells of the ship.
+func (s Ship) Cells() []Cell {
+	cells := make([]Cell, s.Length)
+	for i := range cells {
+		if s.Vertical {
+			cells[i] = Cell{s.Row + i, s.Col}
+		} else {
+			cells[i] = Cell{s.Row, s.Col + i}
+		}
+	}
+	return cells
+}
+
+// Battleship solitaire puzzle: place the ships of the fleet, straight and not
+// touching each other, not even diagonally, so that the rows and columns hold
+// the given numbers of ship cells and the hints are met.
+type Battleship struct {
+	Rows, Cols int
+	// ship lengths
+	Fleet []int
+	// ship cells of each row and column, -1 when unknown
+	RowCounts, ColCounts []int
+	// hint of each cell, see ParseBattleship()
+	Hints [][]byte
+}
+
+// Parses a battleship puzzle from the ship cells of the columns, on the first
+// line, and the lines of the board, each followed by the ship cells of its
+// row, placing the standard fleet when none is given. A cell is unknown (.),
+// water (~), a submarine (o), an end of a ship pointing left, right, up or
+// down (<, >, ^, v), or a middle part of a ship (#). An unknown number is
+// written "?":
+//
+//	  1 0 2 0
+//	.... 1
+//	.... 0
+//	^... 2
+//	.... 0
+func ParseBattleship(s string, fleet ...int) (*Battleship, error) {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) < 2 {
+		return nil, errors.New("battleship has no row")
+	}
+	if len(fleet) == 0 {
+		fleet = StandardFleet
+	}
+	count := func(f string) (int, error) {
+		if f == "?" {
+			return -1, nil
+		}
+		n, err := strconv.Atoi(f)
+		if err == nil && n < 0 {
+			err = fmt.Errorf("negative count %v", n)
+		}
+		return n, err
+	}
+	p := &Battleship{Rows: len(lines) - 1, Fleet: fleet}
+	for _, f := range strings.Fields(lines[0]) {
+		n, err := count(f)
+		if err != nil {
+			return nil, fmt.Errorf("columns: %v", err)
+		}
+		p.ColCounts = append(p.ColCounts, n)
+	}
+	p.Cols = len(p.ColCounts)
+	for i, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || len(fields[0]) != p.Cols {
+			return nil, fmt.Errorf("row %v: wants %v cells and a count", i, p.Cols)
+		}
+		if j := strings.IndexFunc(fields[0], func(c rune) bool { return !strings.ContainsRune(".~o<>^v#", c) }); j >= 0 {
+			return nil, fmt.Errorf("row %v: unknown hint %q", i, fields[0][j])
+		}
+		n, err := count(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("row %v: %v", i, err)
+		}
+		p.RowCounts = append(p.RowCounts, n)
+		p.Hints = append(p.Hints, []byte(fields[0]))
+	}
+	return p, nil
+}
+
+// Returns the hint of the k-th cell of the ship, as drawn on the board.
+func (s Ship) hint(k int) byte {
+	switch {
+	case s.Length == 1:
+		return 'o'
+	case k > 0 && k < s.Length-1:
+		return '#'
+	case s.Vertical && k == 0:
+		return '^'
+	case s.Vertical:
+		return 'v'
+	case k == 0:
+		return '<'
+	}
+	return '>'
+}
+
+// Returns the placements of a ship of the length meeting the hints and the
+// zero counts, in row-major order, horizontal ones first.
+func (p *Battleship) ships(length int) []Ship {
+	ships := make([]Ship, 0)
+	for _, vertical := range []bool{false, true} {
+		if vertical && length == 1 {
+			break
+		}
+		for i := 0; i < p.Rows; i++ {
+		next:
+			for j := 0; j < p.Cols; j++ {
+				s := Ship{Cell{i, j}, length, vertical}
+				for k, c := range s.Cells() {
+					if c.Row >= p.Rows || c.Col >= p.Cols || p.RowCounts[c.Row] == 0 || p.ColCounts[c.Col] == 0 {
+						continue next
+					}
+					if h := p.Hints[c.Row][c.Col]; h != '.' && h != s.hint(k) {
+						continue next
+					}
+				}
+				ships = append(ships, s)
+			}
+		}
+	}
+	return ships
+}
+
+// Returns the items of each choice of width consecutive slots among the k
+// slots of a count, for the cells at the positions pos to pos+width-1. The
+// slots take the cells in the order of their positions through secondary
+// items, like the slots of Template.Count().
+func slots(name string, k, pos, width, n int) [][]string {
+	alternatives := make([][]string, 0, k)
+	for first := 1; first+width-1 <= k; first++ {
+		items := make([]string, 0)
+		for slot := first; slot < first+width; slot++ {
+			q := pos + slot - first
+			items = append(items, fmt.Sprintf("%v/%v", name, slot))
+			if slot < k {
+				for before := 0; before < q; before++ {
+					items = append(items, fmt.Sprintf("%v/%v<%v", name, slot, before))
+				}
+			}
+			if slot > 1 {
+				for after := q; after < n; after++ {
+					items = append(items, fmt.Sprintf("%v/%v<%v", name, slot-1, after))
+				}
+			}
+		}
+		alternatives = append(alternatives, items)
+	}
+	return alternatives
+}
+
+// Declares the slot items of a count over n positions.
+func slotItems(s *cover.Spec, name string, k, n int) {
+	for slot := 1; slot <= k; slot++ {
+		s.Items = append(s.Items, cover.ItemSpec{Name: fmt.Sprintf("%v/%v", name, slot)})
+	}
+	for slot := 1; slot < k; slot++ {
+		for pos := 0; pos < n; pos++ {
+			s.Items = append(s.Items, cover.ItemSpec{Name: fmt.Sprintf("%v/%v<%v", name, slot, pos), Secondary: true})
+		}
+	}
+}
+
+// Describes the problem, whose options are ship placements, and returns the
+// ship of each option. Items have no multiplicities, see Spec.Solver(), so the
+// ships of a length take the slots of the fleet in the order of their
+// placements, and the ship cells of a line the slots of its count, which
+// multiplies the options but keeps each fleet one solution. A ship leaves its cells uncolored
+// and colors the cells around it water, so that ships neither overlap nor
+// touch. A hinted cell is an item only the ships meeting the hint cover.
+func (p *Battleship) build() (*cover.Spec, []Ship) {
+	s := &cover.Spec{Name: "battleship"}
+	for i := 0; i < p.Rows; i++ {
+		for j := 0; j < p.Cols; j++ {
+			s.Items = append(s.Items, cover.ItemSpec{Name: Cell{i, j}.String(), Secondary: true, Colors: []string{"water"}})
+			if h := p.Hints[i][j]; h != '.' && h != '~' {
+				s.Items = append(s.Items, cover.ItemSpec{Name: "hint " + Cell{i, j}.String()})
+			}
+		}
+	}
+	fleet := map[int]int{}
+	for _, length := range p.Fleet {
+		fleet[length]++
+	}
+	for length := 1; length <= p.Rows || length <= p.Cols; length++ {
+		if fleet[length] > 0 {
+			slotItems(s, fmt.Sprintf("ship %v", length), fleet[length], len(p.ships(length)))
+		}
+	}
+	for i, k := range p.RowCounts {
+		slotItems(s, fmt.Sprintf("row %v", i), k, p.Cols)
+	}
+	for j, k := range p.ColCounts {
+		slotItems(s, fmt.Sprintf("col %v", j), k, p.Rows)
+	}
+	ships := make([]Ship, 0)
+	for length := 1; length <= p.Rows || length <= p.Cols; length++ {
+		n := fleet[length]
+		if n == 0 {
+			continue
+		}
+		for pos, ship := range p.ships(length) {
+			cells := ship.Cells()
+			items := make([]string, 0)
+			for _, c := range cells {
+				items = append(items, c.String())
+				if h := p.Hints[c.Row][c.Col]; h != '.' {
+					items = append(items, "hint "+c.String())
+				}
+			}
+			last := cells[len(cells)-1]
+			for i := ship.Row - 1; i <= last.Row+1; i++ {
+				for j := ship.Col - 1; j <= last.Col+1; j++ {
+					inside := i >= ship.Row && i <= last.Row && j >= ship.Col && j <= last.Col
+					if !inside && i >= 0 && i < p.Rows && j >= 0 && j < p.Cols {
+						items = append(items, Cell{i, j}.String()+":water")
+					}
+				}
+			}
+			// an option per combination of the slots
+			combos := [][]string{items}
+			choose := func(alternatives [][]string) {
+				next := make([][]string, 0, len(combos)*len(alternatives))
+				for _, items := range combos {
+					for _, alt := range alternatives {
+						next = append(next, append(items[:len(items):len(items)], alt...))
+					}
+				}
+				combos = next
+			}
+			choose(slots(fmt.Sprintf("ship %v", length), n, pos, 1, len(p.ships(length))))
+			if ship.Vertical {
+				if k := p.ColCounts[ship.Col]; k > 0 {
+					choose(slots(fmt.Sprintf("col %v", ship.Col), k, ship.Row, length, p.Rows))
+				}
+				for _, c := range cells {
+					if k := p.RowCounts[c.Row]; k > 0 {
+						choose(slots(fmt.Sprintf("row %v", c.Row), k, c.Col, 1, p.Cols))
+					}
+				}
+			} else {
+				if k := p.RowCounts[ship.Row]; k > 0 {
+					choose(slots(fmt.Sprintf("row %v", ship.Row), k, ship.Col, length, p.Cols))
+				}
+				for _, c := range cells {
+					if k := p.ColCounts[c.Col]; k > 0 {
+						choose(slots(fmt.Sprintf("col %v", c.Col), k, c.Row, 1, p.Rows))
+					}
+				}
+			}
+			for _, items := range combos {
+				s.Options = append(s.Options, cover.OptionSpec{Items: items})
+				ships = append(ships, ship)
+			}
+		}
+	}
+	return s, ships
+}
+
+// Describes the problem, see build().
+func (p *Battleship) Spec() *cover.Spec {
+	s, _ := p.build()
+	return s
+}
+
+// Returns a solver of the problem, whose solutions decode into ships.
+func (p *Battleship) Solver(opts ...cover.Option) (*cover.Solver, error) {
+	s, ships := p.build()
+	decoder := cover.DecoderFunc(func(row *cover.Node) interface{} {
+		return ships[row.Row]
+	})
+	return s.Solver(append([]cover.Option{cover.WithDecoder(decoder)}, opts...)...)
+}
+
+// Solves the puzzle, returning the ships of the fleet, or nil if there is no
+// solution.
+func (p *Battleship) Solve() []Ship {
+	s, err := p.Solver()
+	if err != nil {
+		// the spec only declares supported items
+		panic(err)
+	}
+	O := s.Solve()
+	if O.Len() == 0 {
+		return nil
+	}
+	ships := make([]Ship, 0, O.Len())
+	for _, obj := range s.Decode(O) {
+		ships = append(ships, obj.(Ship))
+	}
+	return ships
+}
+
+// Counts the solutions, e.g. to check that a puzzle has a single one.
+func (p *Battleship) Count() int {
+	s, err := p.Solver()
+	if err != nil {
+		panic(err)
+	}
+	return int(s.Count().Int64())
+}
@@ -0,0 +1,177 @@
+package grids
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestBattleship(t *testing.T) {
+	p, err := ParseBattleship(`
+		  4 1 2 0 2 1
+		<....o 4
+		...... 0
+		^..... 2
+		...... 2
+		....v. 1
+		...... 1
+	`, 3, 2, 2, 1, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkShips(t, p, p.Solve())
+	if n := p.Count(); n != 1 {
+		t.Errorf("%v solutions (wants 1)", n)
+	}
+}
+
+func TestBattleshipStandard(t *testing.T) {
+	p, err := ParseBattleship(`
+		  5 1 4 3 2 0 1 0 2 2
+		.........o 5
+		.......... 0
+		^......... 5
+		.......... 2
+		...<...... 4
+		.......... 0
+		.......... 2
+		..v....... 2
+		.......... 0
+		.......... 0
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkShips(t, p, p.Solve())
+}
+
+// Checks that the ships solve the puzzle.
+func checkShips(t *testing.T, p *Battleship, ships []Ship) {
+	t.Helper()
+	if len(ships) != len(p.Fleet) {
+		t.Fatalf("%v ships (wants %v)", len(ships), len(p.Fleet))
+	}
+	board := make([][]int, p.Rows)
+	for i := range board {
+		board[i] = make([]int, p.Cols)
+	}
+	lengths := map[int]int{}
+	for n, s := range ships {
+		lengths[s.Length]++
+		for k, c := range s.Cells() {
+			if h := p.Hints[c.Row][c.Col]; h != '.' && h != s.hint(k) {
+				t.Errorf("Ship %v misses hint %c at %v", s, h, c)
+			}
+			for i := c.Row - 1; i <= c.Row+1; i++ {
+				for j := c.Col - 1; j <= c.Col+1; j++ {
+					if i >= 0 && i < p.Rows && j >= 0 && j < p.Cols && board[i][j] != 0 {
+						t.Errorf("Ship %v touches ship %v", s, ships[board[i][j]-1])
+					}
+				}
+			}
+		}
+		for _, c := range s.Cells() {
+			board[c.Row][c.Col] = n + 1
+		}
+	}
+	for _, length := range p.Fleet {
+		lengths[length]--
+	}
+	for length, n := range lengths {
+		if n != 0 {
+			t.Errorf("%v extra ships of length %v", n, length)
+		}
+	}
+	for i := 0; i < p.Rows; i++ {
+		rows, cols := 0, 0
+		for j := 0; j < p.Cols; j++ {
+			if board[i][j] != 0 {
+				rows++
+			}
+			if board[j][i] != 0 {
+				cols++
+			}
+		}
+		if rows != p.RowCounts[i] || cols != p.ColCounts[i] {
+			t.Errorf("Line %v holds %v and %v ship cells (wants %v and %v)", i, rows, cols, p.RowCounts[i], p.ColCounts[i])
+		}
+	}
+}
+
+// Counts the fleets meeting the rules of the battleship puzzle by trying the
+// placements of every ship, those of ships of the same length in order.
+func bruteShips(p *Battleship) int {
+	fleet := append([]int{}, p.Fleet...)
+	sort.Sort(sort.Reverse(sort.IntSlice(fleet)))
+	board := make([][]int, p.Rows)
+	for i := range board {
+		board[i] = make([]int, p.Cols)
+	}
+	var place func(n, from int) int
+	place = func(n, from int) int {
+		if n == len(fleet) {
+			for i := 0; i < p.Rows; i++ {
+				rows, cols := 0, 0
+				for j := 0; j < p.Cols; j++ {
+					rows += board[i][j]
+					cols += board[j][i]
+					if h := p.Hints[i][j]; h != '.' && h != '~' && board[i][j] == 0 {
+						return 0
+					}
+				}
+				if p.RowCounts[i] >= 0 && rows != p.RowCounts[i] || p.ColCounts[i] >= 0 && cols != p.ColCounts[i] {
+					return 0
+				}
+			}
+			return 1
+		}
+		if n > 0 && fleet[n] != fleet[n-1] {
+			from = 0
+		}
+		count := 0
+		ships := p.ships(fleet[n])
+	next:
+		for pos := from; pos < len(ships); pos++ {
+			cells := ships[pos].Cells()
+			for _, c := range cells {
+				for i := c.Row - 1; i <= c.Row+1; i++ {
+					for j := c.Col - 1; j <= c.Col+1; j++ {
+						if i >= 0 && i < p.Rows && j >= 0 && j < p.Cols && board[i][j] != 0 {
+							continue next
+						}
+					}
+				}
+			}
+			for _, c := range cells {
+				board[c.Row][c.Col] = 1
+			}
+			count += place(n+1, pos+1)
+			for _, c := range cells {
+				board[c.Row][c.Col] = 0
+			}
+		}
+		return count
+	}
+	return place(0, 0)
+}
+
+func TestBattleshipCount(t *testing.T) {
+	p, err := ParseBattleship(`
+		  ? ? ? ? ?
+		..... 3
+		..... ?
+		..... ?
+		..... 2
+		..... ?
+	`, 2, 2, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// each fleet once, the slots of the ships and of the counts taking them
+	// in order
+	if want := bruteShips(p); want != 264 {
+		t.Fatalf("%v fleets (wants 264)", want)
+	}
+	if n := p.Count(); n != 264 {
+		t.Errorf("%v solutions (wants 264)", n)
+	}
+}
@@ -0,0 +1,434 @@
+/*
+Package grids declares grid puzzles by the shape of their board, the values
+of their cells and the rules of their regions, and builds the exact cover
+problems solving them with their decoder. A sudoku, for instance:
+
+	t := grids.New("sudoku", 9, 9, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	t.Unique("row", t.RowCells()...)
+	t.Unique("col", t.ColCells()...)
+	t.Unique("box", t.BoxCells(3, 3)...)
+	solver, err := t.Solver()
+*/
+package grids
+
+import (
+	"fmt"
+
+	"github.com/qur2/go-cover"
+)
+
+// Cell of a board, by row and column.
+type Cell struct {
+	Row, Col int
+}
+
+func (c Cell) String() string {
+	return fmt.Sprintf("%v,%v", c.Row, c.Col)
+}
+
+// Value placed in a cell, the object a row of a template decodes into.
+type Placement struct {
+	Cell
+	Value int
+}
+
+// Rule of a set of placements: at most one of them holds, or exactly count of
+// them when required.
+type rule struct {
+	name       string
+	placements []Placement
+	required   bool
+	count      int
+}
+
+// Declaration of a grid puzzle: every cell of the board holds exactly one
+// value of the domain, and rules restrict the values of sets of cells.
+type Template struct {
+	Name       string
+	Rows, Cols int
+	values     []int
+	holes      map[Cell]bool
+	givens     map[Cell]int
+	excluded   map[Placement]bool
+	rules      []rule
+	// names of the rules, to reject duplicates
+	names map[string]bool
+}
+
+// Starts the template of a rows by cols board whose cells hold one of the
+// values.
+func New(name string, rows, cols int, values ...int) *Template {
+	return &Template{Name: name, Rows: rows, Cols: cols, values: values,
+		holes: map[Cell]bool{}, givens: map[Cell]int{}, excluded: map[Placement]bool{}, names: map[string]bool{}}
+}
+
+// Removes the cells from the board, e.g. to shape it, so that they hold no
+// value and the rules ignore them.
+func (t *Template) Hole(cells ...Cell) *Template {
+	for _, c := range cells {
+		t.holes[c] = true
+	}
+	return t
+}
+
+// Fixes the value of a cell, e.g. a clue of the puzzle.
+func (t *Template) Given(c Cell, v int) *Template {
+	t.givens[c] = v
+	return t
+}
+
+// Rules out the placements, e.g. the values a cell cannot hold. Givens are
+// kept.
+func (t *Template) Exclude(placements ...Placement) *Template {
+	for _, p := range placements {
+		t.excluded[p] = true
+	}
+	return t
+}
+
+// Fixes the values of the cells of a grid which are not zero.
+func (t *Template) Givens(grid [][]int) *Template {
+	for i, row := range grid {
+		for j, v := range row {
+			if v != 0 {
+				t.Given(Cell{i, j}, v)
+			}
+		}
+	}
+	return t
+}
+
+// Adds a rule per value, or per set and value, and panics on a name already
+// used since the headers would collide.
+func (t *Template) add(r rule) {
+	if t.names[r.name] {
+		panic(fmt.Sprintf("grids: rule %v declared twice", r.name))
+	}
+	t.names[r.name] = true
+	t.rules = append(t.rules, r)
+}
+
+// Requires the cells of every set to hold distinct values, like the rows of a
+// sudoku. A set having as many cells as the domain has values holds each one
+// exactly once, a smaller set at most once. The sets are named name#i.
+func (t *Template) Unique(name string, sets ...[]Cell) *Template {
+	for i, cells := range sets {
+		required := len(t.onBoard(cells)) == len(t.values)
+		for _, v := range t.values {
+			t.add(rule{fmt.Sprintf("%v#%v=%v", name, i, v), Placements(cells, v), required, 1})
+		}
+	}
+	return t
+}
+
+// Requires exactly one cell of every set to hold the value, like the queens of
+// the rows of a chessboard. The sets are named name#i.
+func (t *Template) Exactly(name string, value int, sets ...[]Cell) *Template {
+	return t.Count(name, value, 1, sets...)
+}
+
+// Requires exactly k cells of every set to hold the value, like the stars of
+// the rows of a star battle. The engine covers items once, and Spec.Solver()
+// rejects the multiplicities of items, so the count is not an item of
+// multiplicity k: the cells holding the value take the k slots of the set in
+// the order of its cells, which secondary items enforce, so that each board
+// is one solution. The slots cost size: a set of n cells adds k primary items
+// and (k-1)n secondary ones, and a placement gets an option per choice of
+// slots in the sets holding it, i.e. the product of their counts, e.g. 8 per
+// cell for 2 stars per row, column and region. The rules thus suit small
+// multiplicities. The sets are named name#i.
+func (t *Template) Count(name string, value, k int, sets ...[]Cell) *Template {
+	for i, cells := range sets {
+		t.add(rule{fmt.Sprintf("%v#%v=%v", name, i, value), Placements(cells, value), true, k})
+	}
+	return t
+}
+
+// Allows at most one cell of every set to hold the value, like the queens of
+// the diagonals of a chessboard. The sets are named name#i.
+func (t *Template) AtMost(name string, value int, sets ...[]Cell) *Template {
+	for i, cells := range sets {
+		t.add(rule{fmt.Sprintf("%v#%v=%v", name, i, value), Placements(cells, value), false, 1})
+	}
+	return t
+}
+
+// Requires exactly k placements of every set to hold, like Count() but over
+// placements of distinct values, e.g. the tents of a row whose values tell
+// their tree. The placements take the slots of the set in order, since items
+// have no multiplicities, which costs as much as Count(). The sets are named
+// name#i.
+func (t *Template) CountOf(name string, k int, sets ...[]Placement) *Template {
+	for i, placements := range sets {
+		t.add(rule{fmt.Sprintf("%v#%v", name, i), placements, true, k})
+	}
+	return t
+}
+
+// Allows at most one placement of every set to hold, like AtMost() but over
+// placements of distinct values. The sets are named name#i.
+func (t *Template) AtMostOf(name string, sets ...[]Placement) *Template {
+	for i, placements := range sets {
+		t.add(rule{fmt.Sprintf("%v#%v", name, i), placements, false, 1})
+	}
+	return t
+}
+
+// Returns the placements of each value in each cell.
+func Placements(cells []Cell, values ...int) []Placement {
+	placements := make([]Placement, 0, len(cells)*len(values))
+	for _, c := range cells {
+		for _, v := range values {
+			placements = append(placements, Placement{c, v})
+		}
+	}
+	return placements
+}
+
+// Returns the cells which are on the board.
+func (t *Template) onBoard(cells []Cell) []Cell {
+	on := make([]Cell, 0, len(cells))
+	for _, c := range cells {
+		if c.Row >= 0 && c.Row < t.Rows && c.Col >= 0 && c.Col < t.Cols && !t.holes[c] {
+			on = append(on, c)
+		}
+	}
+	return on
+}
+
+// Returns the cells of every row of the board.
+func (t *Template) RowCells() [][]Cell {
+	sets := make([][]Cell, t.Rows)
+	for i := range sets {
+		for j := 0; j < t.Cols; j++ {
+			sets[i] = append(sets[i], Cell{i, j})
+		}
+	}
+	return sets
+}
+
+// Returns the cells of every column of the board.
+func (t *Template) ColCells() [][]Cell {
+	sets := make([][]Cell, t.Cols)
+	for j := range sets {
+		for i := 0; i < t.Rows; i++ {
+			sets[j] = append(sets[j], Cell{i, j})
+		}
+	}
+	return sets
+}
+
+// Returns the cells of every h by w box tiling the board, like the boxes of a
+// sudoku, row by row.
+func (t *Template) BoxCells(h, w int) [][]Cell {
+	sets := make([][]Cell, 0)
+	for i := 0; i < t.Rows; i += h {
+		for j := 0; j < t.Cols; j += w {
+			sets = append(sets, t.block(i, j, h, w))
+		}
+	}
+	return sets
+}
+
+// Returns the cells of every h by w block of the board, overlapping ones
+// included, like the 2x2 blocks holding at most one star of a star battle.
+func (t *Template) BlockCells(h, w int) [][]Cell {
+	sets := make([][]Cell, 0)
+	for i := 0; i+h <= t.Rows; i++ {
+		for j := 0; j+w <= t.Cols; j++ {
+			sets = append(sets, t.block(i, j, h, w))
+		}
+	}
+	return sets
+}
+
+func (t *Template) block(i, j, h, w int) []Cell {
+	cells := make([]Cell, 0, h*w)
+	for di := 0; di < h; di++ {
+		for dj := 0; dj < w; dj++ {
+			cells = append(cells, Cell{i + di, j + dj})
+		}
+	}
+	return cells
+}
+
+// Returns the cells of each region of a board labelled by region, in the
+// order of the labels, e.g. the irregular regions of a star battle. Negative
+// labels belong to no region.
+func RegionCells(labels [][]int) [][]Cell {
+	sets := make([][]Cell, 0)
+	for i, row := range labels {
+		for j, l := range row {
+			if l < 0 {
+				continue
+			}
+			for len(sets) <= l {
+				sets = append(sets, nil)
+			}
+			sets[l] = append(sets[l], Cell{i, j})
+		}
+	}
+	return sets
+}
+
+// Returns the cells of every diagonal of the board with at least two cells,
+// both directions, like the lines of a chessboard a bishop moves along.
+func (t *Template) DiagonalCells() [][]Cell {
+	sets := make([][]Cell, 0)
+	for d := -(t.Rows - 1); d < t.Cols; d++ {
+		down, up := make([]Cell, 0), make([]Cell, 0)
+		for i := 0; i < t.Rows; i++ {
+			if j := i + d; j >= 0 && j < t.Cols {
+				down = append(down, Cell{i, j})
+			}
+			if j := t.Cols - 1 - i - d; j >= 0 && j < t.Cols {
+				up = append(up, Cell{i, j})
+			}
+		}
+		for _, cells := range [][]Cell{down, up} {
+			if len(cells) > 1 {
+				sets = append(sets, cells)
+			}
+		}
+	}
+	return sets
+}
+
+// Returns the placements of the rows of the problem, in row order: every
+// value of the domain but the excluded ones for each cell of the board in
+// row-major order, or its given value only.
+func (t *Template) placements() []Placement {
+	rows := make([]Placement, 0)
+	for i := 0; i < t.Rows; i++ {
+		for j := 0; j < t.Cols; j++ {
+			c := Cell{i, j}
+			if t.holes[c] {
+				continue
+			}
+			if v, ok := t.givens[c]; ok {
+				rows = append(rows, Placement{c, v})
+				continue
+			}
+			for _, v := range t.values {
+				if p := (Placement{c, v}); !t.excluded[p] {
+					rows = append(rows, p)
+				}
+			}
+		}
+	}
+	return rows
+}
+
+// Describes the problem, see Spec(), and returns the placement of each
+// option.
+func (t *Template) build() (*cover.Spec, []Placement) {
+	s := &cover.Spec{Name: t.Name}
+	for i := 0; i < t.Rows; i++ {
+		for j := 0; j < t.Cols; j++ {
+			if c := (Cell{i, j}); !t.holes[c] {
+				s.Items = append(s.Items, cover.ItemSpec{Name: c.String()})
+			}
+		}
+	}
+	// alternative item lists of the rules of each placement, one per slot
+	choices := map[Placement][][][]string{}
+	for _, r := range t.rules {
+		placements := make([]Placement, 0, len(r.placements))
+		for _, p := range r.placements {
+			if len(t.onBoard([]Cell{p.Cell})) > 0 {
+				placements = append(placements, p)
+			}
+		}
+		if r.count <= 1 {
+			s.Items = append(s.Items, cover.ItemSpec{Name: r.name, Secondary: !r.required})
+			for _, p := range placements {
+				choices[p] = append(choices[p], [][]string{{r.name}})
+			}
+			continue
+		}
+		for slot := 1; slot <= r.count; slot++ {
+			s.Items = append(s.Items, cover.ItemSpec{Name: fmt.Sprintf("%v/%v", r.name, slot)})
+		}
+		// slot s at position f and slot s+1 at position g share the item
+		// of a position p when g <= p < f, which orders the slots
+		for slot := 1; slot < r.count; slot++ {
+			for pos := range placements {
+				s.Items = append(s.Items, cover.ItemSpec{Name: fmt.Sprintf("%v/%v<%v", r.name, slot, pos), Secondary: true})
+			}
+		}
+		for pos, p := range placements {
+			alternatives := make([][]string, r.count)
+			for slot := 1; slot <= r.count; slot++ {
+				items := []string{fmt.Sprintf("%v/%v", r.name, slot)}
+				if slot < r.count {
+					for before := 0; before < pos; before++ {
+						items = append(items, fmt.Sprintf("%v/%v<%v", r.name, slot, before))
+					}
+				}
+				if slot > 1 {
+					for after := pos; after < len(placements); after++ {
+						items = append(items, fmt.Sprintf("%v/%v<%v", r.name, slot-1, after))
+					}
+				}
+				alternatives[slot-1] = items
+			}
+			choices[p] = append(choices[p], alternatives)
+		}
+	}
+	rows := make([]Placement, 0)
+	for _, p := range t.placements() {
+		// an option per combination of the alternatives
+		combos := [][]string{{p.Cell.String()}}
+		for _, alternatives := range choices[p] {
+			next := make([][]string, 0, len(combos)*len(alternatives))
+			for _, items := range combos {
+				for _, alt := range alternatives {
+					next = append(next, append(items[:len(items):len(items)], alt...))
+				}
+			}
+			combos = next
+		}
+		for _, items := range combos {
+			s.Options = append(s.Options, cover.OptionSpec{Items: items})
+			rows = append(rows, p)
+		}
+	}
+	return s, rows
+}
+
+// Describes the problem: a primary item per cell, a primary item per
+// required rule, or per slot of a rule requiring several cells, a secondary
+// item per other rule, and an option per placement and choice of slots.
+func (t *Template) Spec() *cover.Spec {
+	s, _ := t.build()
+	return s
+}
+
+// Builds a solver for the problem, applying the options after the decoder of
+// the placements. Returns the error of Spec.Solver().
+func (t *Template) Solver(opts ...cover.Option) (*cover.Solver, error) {
+	s, rows := t.build()
+	decoder := cover.DecoderFunc(func(row *cover.Node) interface{} {
+		return rows[row.Row]
+	})
+	return s.Solver(append([]cover.Option{cover.WithDecoder(decoder)}, opts...)...)
+}
+
+// Returns the grid of the placements decoded from a solution, the holes
+// holding -1.
+func (t *Template) Grid(objs []interface{}) [][]int {
+	grid := make([][]int, t.Rows)
+	for i := range grid {
+		grid[i] = make([]int, t.Cols)
+		for j := range grid[i] {
+			if t.holes[Cell{i, j}] {
+				grid[i][j] = -1
+			}
+		}
+	}
+	for _, obj := range objs {
+		p := obj.(Placement)
+		grid[p.Row][p.Col] = p.Value
+	}
+	return grid
+}
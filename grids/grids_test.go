@@ -0,0 +1,86 @@
+package grids
+
+import (
+	"testing"
+
+	"github.com/qur2/go-cover"
+)
+
+func count(t *testing.T, tmpl *Template) int64 {
+	s, err := tmpl.Solver()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s.Count().Int64()
+}
+
+func TestTemplates(t *testing.T) {
+	latin := New("latin", 4, 4, 1, 2, 3, 4)
+	latin.Unique("row", latin.RowCells()...).Unique("col", latin.ColCells()...)
+	if n := count(t, latin); n != 576 {
+		t.Errorf("Latin squares of order 4 number %v (wants 576)", n)
+	}
+	sudoku := New("sudoku", 4, 4, 1, 2, 3, 4)
+	sudoku.Unique("row", sudoku.RowCells()...).Unique("col", sudoku.ColCells()...)
+	sudoku.Unique("box", sudoku.BoxCells(2, 2)...)
+	if n := count(t, sudoku); n != 288 {
+		t.Errorf("Sudokus of order 4 number %v (wants 288)", n)
+	}
+	queens := New("queens", 8, 8, 0, 1)
+	queens.Exactly("row", 1, queens.RowCells()...).Exactly("col", 1, queens.ColCells()...)
+	queens.AtMost("diag", 1, queens.DiagonalCells()...)
+	if n := count(t, queens); n != 92 {
+		t.Errorf("Eight queens have %v solutions (wants 92)", n)
+	}
+	// a 3x3 board without its corners holding 5 distinct digits
+	cross := New("cross", 3, 3, 1, 2, 3, 4, 5).Hole(Cell{0, 0}, Cell{0, 2}, Cell{2, 0}, Cell{2, 2})
+	cross.Unique("all", append(cross.RowCells()[0], append(cross.RowCells()[1], cross.RowCells()[2]...)...))
+	if n := count(t, cross); n != 120 {
+		t.Errorf("Crosses of distinct digits number %v (wants 120)", n)
+	}
+}
+
+func TestTemplateGrid(t *testing.T) {
+	tmpl := New("sudoku", 9, 9, 1, 2, 3, 4, 5, 6, 7, 8, 9)
+	tmpl.Unique("row", tmpl.RowCells()...).Unique("col", tmpl.ColCells()...)
+	tmpl.Unique("box", tmpl.BoxCells(3, 3)...)
+	puzzle, err := cover.ParseGrid("..53.....8......2..7..1.5..4....53...1..7...6..32...8..6.5....9..4....3......97..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl.Givens(puzzle)
+	s, err := tmpl.Solver(cover.WithUnitPropagation())
+	if err != nil {
+		t.Fatal(err)
+	}
+	O := s.Solve()
+	grid := tmpl.Grid(s.Decode(O))
+	for i, row := range puzzle {
+		for j, v := range row {
+			if v != 0 && grid[i][j] != v {
+				t.Errorf("Cell %v,%v holds %v (given %v)", i, j, grid[i][j], v)
+			}
+		}
+	}
+	for _, sets := range [][][]Cell{tmpl.RowCells(), tmpl.ColCells(), tmpl.BoxCells(3, 3)} {
+		for _, cells := range sets {
+			seen := map[int]bool{}
+			for _, c := range cells {
+				seen[grid[c.Row][c.Col]] = true
+			}
+			if len(seen) != 9 {
+				t.Errorf("Cells %v hold %v distinct digits", cells, len(seen))
+			}
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+	// 2 queens per row and column of a 4x4 board: the 90 0-1 matrices having
+	// two ones in every row and column
+	tmpl := New("twos", 4, 4, 0, 1)
+	tmpl.Count("row", 1, 2, tmpl.RowCells()...).Count("col", 1, 2, tmpl.ColCells()...)
+	if n := count(t, tmpl); n != 90 {
+		t.Errorf("Matrices number %v (wants 90)", n)
+	}
+}
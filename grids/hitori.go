@@ -0,0 +1,194 @@
+package grids
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/qur2/go-cover"
+)
+
+// Hitori puzzle: shade cells so that no number repeats among the unshaded
+// cells of a row or column, no two shaded cells touch orthogonally, and the
+// unshaded cells are connected.
+type Hitori struct {
+	Numbers [][]int
+}
+
+// Parses a hitori from the lines of its board, holding numbers separated by
+// spaces, or a digit per cell when no line holds a space.
+func ParseHitori(s string) (*Hitori, error) {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return nil, errors.New("hitori has no row")
+	}
+	spaced := strings.Contains(s, " ") && len(strings.Fields(lines[0])) > 1
+	p := &Hitori{Numbers: make([][]int, len(lines))}
+	for i, line := range lines {
+		fields := strings.Split(line, "")
+		if spaced {
+			fields = strings.Fields(line)
+		}
+		if len(fields) != len(lines) {
+			return nil, fmt.Errorf("row %v has %v cells (wants %v)", i, len(fields), len(lines))
+		}
+		p.Numbers[i] = make([]int, len(fields))
+		for j, f := range fields {
+			n, err := strconv.Atoi(f)
+			if err != nil {
+				return nil, fmt.Errorf("row %v: %v", i, err)
+			}
+			p.Numbers[i][j] = n
+		}
+	}
+	return p, nil
+}
+
+// Color names of the shade items.
+var shades = []string{"white", "black"}
+
+// Describes the problem with colored items: every cell has a primary item,
+// and a secondary item colored by its shade. A cell has a white option and a
+// black one, in this order and row by row, the black one coloring the shade
+// items of its neighbors white so that shaded cells never touch. A number
+// repeated in a line gets a secondary item covered by the white options of
+// its cells. Connectivity is left to the search, see Solve().
+func (p *Hitori) Spec() *cover.Spec {
+	n := len(p.Numbers)
+	s := &cover.Spec{Name: "hitori"}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			s.Items = append(s.Items, cover.ItemSpec{Name: Cell{i, j}.String()})
+		}
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			s.Items = append(s.Items, cover.ItemSpec{Name: "shade " + Cell{i, j}.String(), Secondary: true, Colors: shades})
+		}
+	}
+	// count of each number in each line
+	repeats := map[string]int{}
+	for i, row := range p.Numbers {
+		for j, v := range row {
+			repeats[fmt.Sprintf("row %v=%v", i, v)]++
+			repeats[fmt.Sprintf("col %v=%v", j, v)]++
+		}
+	}
+	for name, count := range repeats {
+		if count > 1 {
+			s.Items = append(s.Items, cover.ItemSpec{Name: name, Secondary: true})
+		}
+	}
+	// map iteration has no order
+	lines := s.Items[2*n*n:]
+	sort.Slice(lines, func(a, b int) bool { return lines[a].Name < lines[b].Name })
+	for i, row := range p.Numbers {
+		for j, v := range row {
+			c := Cell{i, j}
+			white := []string{c.String(), "shade " + c.String() + ":white"}
+			for _, name := range []string{fmt.Sprintf("row %v=%v", i, v), fmt.Sprintf("col %v=%v", j, v)} {
+				if repeats[name] > 1 {
+					white = append(white, name)
+				}
+			}
+			black := []string{c.String(), "shade " + c.String() + ":black"}
+			for _, d := range []Cell{{i - 1, j}, {i + 1, j}, {i, j - 1}, {i, j + 1}} {
+				if d.Row >= 0 && d.Row < n && d.Col >= 0 && d.Col < n {
+					black = append(black, "shade "+d.String()+":white")
+				}
+			}
+			s.Options = append(s.Options, cover.OptionSpec{Items: white}, cover.OptionSpec{Items: black})
+		}
+	}
+	return s
+}
+
+// Tells whether the unshaded cells are connected.
+func connected(shaded [][]bool) bool {
+	n := len(shaded)
+	seen := make([][]bool, n)
+	for i := range seen {
+		seen[i] = make([]bool, n)
+	}
+	stack := make([]Cell, 0)
+	whites := 0
+	for i := range shaded {
+		for j := range shaded[i] {
+			if !shaded[i][j] {
+				whites++
+				if len(stack) == 0 && whites == 1 {
+					stack = append(stack, Cell{i, j})
+					seen[i][j] = true
+				}
+			}
+		}
+	}
+	reached := 0
+	for len(stack) > 0 {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		reached++
+		for _, d := range []Cell{{c.Row - 1, c.Col}, {c.Row + 1, c.Col}, {c.Row, c.Col - 1}, {c.Row, c.Col + 1}} {
+			if d.Row >= 0 && d.Row < n && d.Col >= 0 && d.Col < n && !shaded[d.Row][d.Col] && !seen[d.Row][d.Col] {
+				seen[d.Row][d.Col] = true
+				stack = append(stack, d)
+			}
+		}
+	}
+	return reached == whites
+}
+
+// Calls f with the shaded cells of each solution, until it returns false.
+// The colored cover enforces the line and touching rules, and the solutions
+// whose unshaded cells are not connected are skipped.
+func (p *Hitori) each(f func(shaded [][]bool) bool) {
+	s, err := p.Spec().Solver(cover.WithSizeTracking())
+	if err != nil {
+		// the spec only uses supported items
+		panic(err)
+	}
+	n := len(p.Numbers)
+	s.Each(func(O *cover.Solution) bool {
+		shaded := make([][]bool, n)
+		for i := range shaded {
+			shaded[i] = make([]bool, n)
+		}
+		for _, r := range O.Rows() {
+			if r%2 == 1 {
+				shaded[r/2/n][r/2%n] = true
+			}
+		}
+		if !connected(shaded) {
+			return true
+		}
+		return f(shaded)
+	})
+}
+
+// Solves the puzzle, returning the shaded cells, or nil if there is no
+// solution.
+func (p *Hitori) Solve() [][]bool {
+	var solution [][]bool
+	p.each(func(shaded [][]bool) bool {
+		solution = shaded
+		return false
+	})
+	return solution
+}
+
+// Counts the solutions, e.g. to check that a puzzle has a single one.
+func (p *Hitori) Count() int {
+	count := 0
+	p.each(func([][]bool) bool {
+		count++
+		return true
+	})
+	return count
+}
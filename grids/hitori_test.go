@@ -0,0 +1,61 @@
+package grids
+
+import (
+	"testing"
+)
+
+func TestHitori(t *testing.T) {
+	p, err := ParseHitori(`
+		1232
+		2133
+		3221
+		4214
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shaded := p.Solve()
+	if shaded == nil {
+		t.Fatal("No solution")
+	}
+	n := len(p.Numbers)
+	for i := 0; i < n; i++ {
+		rows, cols := map[int]bool{}, map[int]bool{}
+		for j := 0; j < n; j++ {
+			if !shaded[i][j] {
+				if rows[p.Numbers[i][j]] {
+					t.Errorf("Row %v repeats %v", i, p.Numbers[i][j])
+				}
+				rows[p.Numbers[i][j]] = true
+			} else if j+1 < n && shaded[i][j+1] || i+1 < n && shaded[i+1][j] {
+				t.Errorf("Shaded cell %v,%v touches another", i, j)
+			}
+			if !shaded[j][i] {
+				if cols[p.Numbers[j][i]] {
+					t.Errorf("Column %v repeats %v", i, p.Numbers[j][i])
+				}
+				cols[p.Numbers[j][i]] = true
+			}
+		}
+	}
+	if !connected(shaded) {
+		t.Errorf("Unshaded cells are not connected: %v", shaded)
+	}
+	if n := p.Count(); n != 1 {
+		t.Errorf("%v solutions (wants 1)", n)
+	}
+}
+
+func TestHitoriConnected(t *testing.T) {
+	p, err := ParseHitori("1 1\n1 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// shading both cells next to the bottom right corner isolates it
+	if connected([][]bool{{false, true}, {true, false}}) {
+		t.Error("Isolated corner is connected")
+	}
+	if shaded := p.Solve(); shaded == nil || !shaded[0][0] {
+		t.Errorf("Solved with %v (wants the top left cell shaded)", shaded)
+	}
+}
@@ -0,0 +1,197 @@
+package grids
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Star battle puzzle: place stars so that every row, column and region of
+// the board holds the same number of them, no two stars touching, not even
+// diagonally.
+type StarBattle struct {
+	Rows, Cols, Stars int
+	// region of each cell, numbered from 0
+	Regions [][]int
+}
+
+// Parses a star battle from the lines of its board, one char per cell, the
+// cells of a region sharing the same char, which may be any Unicode char.
+func ParseStarBattle(s string, stars int) (*StarBattle, error) {
+	lines := strings.Fields(s)
+	if len(lines) == 0 {
+		return nil, errors.New("star battle has no row")
+	}
+	p := &StarBattle{Rows: len(lines), Cols: utf8.RuneCountInString(lines[0]), Stars: stars, Regions: make([][]int, len(lines))}
+	index := map[rune]int{}
+	for i, line := range lines {
+		cells := []rune(line)
+		if len(cells) != p.Cols {
+			return nil, fmt.Errorf("row %v has %v cells (wants %v)", i, len(cells), p.Cols)
+		}
+		p.Regions[i] = make([]int, p.Cols)
+		for j, c := range cells {
+			if _, ok := index[c]; !ok {
+				index[c] = len(index)
+			}
+			p.Regions[i][j] = index[c]
+		}
+	}
+	return p, nil
+}
+
+// Parses a star battle from its puzz.link URL, e.g.
+// "https://puzz.link/p?starbattle/6/6/1/...", holding the columns, the rows,
+// the stars and the borders between the regions, 5 per base 32 digit: those
+// between the columns row by row, then those between the rows.
+func ParseStarBattleURL(url string) (*StarBattle, error) {
+	if i := strings.Index(url, "?"); i >= 0 {
+		url = url[i+1:]
+	}
+	parts := strings.Split(url, "/")
+	if len(parts) < 5 || parts[0] != "starbattle" {
+		return nil, fmt.Errorf("not a star battle url: %q", url)
+	}
+	dims := make([]int, 3)
+	for i := range dims {
+		n, err := strconv.Atoi(parts[i+1])
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("star battle url: bad number %q", parts[i+1])
+		}
+		dims[i] = n
+	}
+	cols, rows := dims[0], dims[1]
+	verticals, horizontals := (cols-1)*rows, cols*(rows-1)
+	borders := make([]bool, 0, verticals+horizontals+4)
+	data := parts[4]
+	for _, n := range []int{verticals, horizontals} {
+		chars := (n + 4) / 5
+		if len(data) < chars {
+			return nil, errors.New("star battle url: borders are truncated")
+		}
+		start := len(borders)
+		for i := 0; i < chars; i++ {
+			d, err := strconv.ParseInt(data[i:i+1], 32, 0)
+			if err != nil {
+				return nil, fmt.Errorf("star battle url: bad digit %q", data[i])
+			}
+			for bit := 4; bit >= 0; bit-- {
+				borders = append(borders, d&(1<<uint(bit)) != 0)
+			}
+		}
+		// the last digit pads the borders with zeros
+		borders = borders[:start+n]
+		data = data[chars:]
+	}
+	p := &StarBattle{Rows: rows, Cols: cols, Stars: dims[2]}
+	p.Regions = regionsFromBorders(rows, cols, borders[:verticals], borders[verticals:])
+	return p, nil
+}
+
+// Labels the regions of a board by flood fill, given whether there is a
+// border right of each cell but the last column, row by row, and below each
+// cell but the last row.
+func regionsFromBorders(rows, cols int, right, below []bool) [][]int {
+	regions := make([][]int, rows)
+	for i := range regions {
+		regions[i] = make([]int, cols)
+		for j := range regions[i] {
+			regions[i][j] = -1
+		}
+	}
+	n := 0
+	for i := range regions {
+		for j := range regions[i] {
+			if regions[i][j] >= 0 {
+				continue
+			}
+			stack := []Cell{{i, j}}
+			regions[i][j] = n
+			for len(stack) > 0 {
+				c := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				next := make([]Cell, 0, 4)
+				if c.Col+1 < cols && !right[c.Row*(cols-1)+c.Col] {
+					next = append(next, Cell{c.Row, c.Col + 1})
+				}
+				if c.Col > 0 && !right[c.Row*(cols-1)+c.Col-1] {
+					next = append(next, Cell{c.Row, c.Col - 1})
+				}
+				if c.Row+1 < rows && !below[c.Row*cols+c.Col] {
+					next = append(next, Cell{c.Row + 1, c.Col})
+				}
+				if c.Row > 0 && !below[(c.Row-1)*cols+c.Col] {
+					next = append(next, Cell{c.Row - 1, c.Col})
+				}
+				for _, d := range next {
+					if regions[d.Row][d.Col] < 0 {
+						regions[d.Row][d.Col] = n
+						stack = append(stack, d)
+					}
+				}
+			}
+			n++
+		}
+	}
+	return regions
+}
+
+// Returns the puzz.link URL of the puzzle.
+func (p *StarBattle) URL() string {
+	bits := make([]bool, 0)
+	for i := 0; i < p.Rows; i++ {
+		for j := 0; j+1 < p.Cols; j++ {
+			bits = append(bits, p.Regions[i][j] != p.Regions[i][j+1])
+		}
+	}
+	var b strings.Builder
+	pack := func(bits []bool) {
+		for i := 0; i < len(bits); i += 5 {
+			d := 0
+			for k := 0; k < 5; k++ {
+				if i+k < len(bits) && bits[i+k] {
+					d |= 1 << uint(4-k)
+				}
+			}
+			b.WriteString(strconv.FormatInt(int64(d), 32))
+		}
+	}
+	pack(bits)
+	bits = bits[:0]
+	for i := 0; i+1 < p.Rows; i++ {
+		for j := 0; j < p.Cols; j++ {
+			bits = append(bits, p.Regions[i][j] != p.Regions[i+1][j])
+		}
+	}
+	pack(bits)
+	return fmt.Sprintf("https://puzz.link/p?starbattle/%v/%v/%v/%v", p.Cols, p.Rows, p.Stars, b.String())
+}
+
+// Returns the template of the puzzle, whose cells hold 1 for a star and 0
+// otherwise.
+func (p *StarBattle) Template() *Template {
+	t := New("starbattle", p.Rows, p.Cols, 0, 1)
+	t.Count("row", 1, p.Stars, t.RowCells()...)
+	t.Count("col", 1, p.Stars, t.ColCells()...)
+	t.Count("region", 1, p.Stars, RegionCells(p.Regions)...)
+	t.AtMost("touch", 1, t.BlockCells(2, 2)...)
+	return t
+}
+
+// Solves the puzzle, returning the board with 1 for a star and 0 otherwise,
+// or nil if there is no solution.
+func (p *StarBattle) Solve() [][]int {
+	t := p.Template()
+	s, err := t.Solver()
+	if err != nil {
+		// the template only declares supported items
+		panic(err)
+	}
+	O := s.Solve()
+	if O.Len() == 0 {
+		return nil
+	}
+	return t.Grid(s.Decode(O))
+}
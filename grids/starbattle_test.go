@@ -0,0 +1,183 @@
+package grids
+
+import (
+	"testing"
+)
+
+// Checks the stars of a solved star battle against its rules.
+func checkStars(t *testing.T, p *StarBattle, grid [][]int) {
+	rows, cols := make([]int, p.Rows), make([]int, p.Cols)
+	regions := map[int]int{}
+	for i, row := range grid {
+		for j, v := range row {
+			if v != 1 {
+				continue
+			}
+			rows[i]++
+			cols[j]++
+			regions[p.Regions[i][j]]++
+			for di := -1; di <= 1; di++ {
+				for dj := -1; dj <= 1; dj++ {
+					ni, nj := i+di, j+dj
+					if (di != 0 || dj != 0) && ni >= 0 && ni < p.Rows && nj >= 0 && nj < p.Cols && grid[ni][nj] == 1 {
+						t.Errorf("Stars %v,%v and %v,%v touch", i, j, ni, nj)
+					}
+				}
+			}
+		}
+	}
+	for _, counts := range [][]int{rows, cols} {
+		for i, n := range counts {
+			if n != p.Stars {
+				t.Errorf("Line %v holds %v stars (wants %v)", i, n, p.Stars)
+			}
+		}
+	}
+	for r, n := range regions {
+		if n != p.Stars {
+			t.Errorf("Region %v holds %v stars (wants %v)", r, n, p.Stars)
+		}
+	}
+}
+
+func TestStarBattle(t *testing.T) {
+	p, err := ParseStarBattle(`
+		AABBB
+		AACBB
+		DCCCE
+		DDCEE
+		DDEEE`, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	grid := p.Solve()
+	if grid == nil {
+		t.Fatal("Star battle has no solution")
+	}
+	checkStars(t, p, grid)
+
+	q, err := ParseStarBattleURL(p.URL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.URL() != p.URL() || q.Rows != 5 || q.Cols != 5 || q.Stars != 1 {
+		t.Errorf("URL %v parses into %v", p.URL(), q.URL())
+	}
+	for i := range p.Regions {
+		for j := range p.Regions[i] {
+			if p.Regions[i][j] != q.Regions[i][j] {
+				t.Errorf("Cell %v,%v is in region %v (wants %v)", i, j, q.Regions[i][j], p.Regions[i][j])
+			}
+		}
+	}
+	if _, err := ParseStarBattleURL("https://puzz.link/p?nurikabe/5/5/1/0"); err == nil {
+		t.Errorf("Nurikabe url parses as a star battle")
+	}
+	// regions may be labelled by any char, one per cell
+	r, err := ParseStarBattle(`
+		ααβββ
+		ααγββ
+		δγγγε
+		δδγεε
+		δδεεε`, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Cols != 5 || r.URL() != p.URL() {
+		t.Errorf("Greek board has %v columns and URL %v (wants %v)", r.Cols, r.URL(), p.URL())
+	}
+}
+
+func TestStarBattleTwoStars(t *testing.T) {
+	// 8x8 board of 8 regions of 2x4 cells
+	p, err := ParseStarBattle(`
+		AAAABBBB
+		AAAABBBB
+		CCCCDDDD
+		CCCCDDDD
+		EEEEFFFF
+		EEEEFFFF
+		GGGGHHHH
+		GGGGHHHH`, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	grid := p.Solve()
+	if grid == nil {
+		t.Fatal("Star battle has no solution")
+	}
+	checkStars(t, p, grid)
+}
+
+// Counts the boards meeting the rules of the star battle by trying every
+// choice of stars row by row, cutting the lines and regions holding too many.
+func bruteStars(p *StarBattle) int {
+	board := make([][]int, p.Rows)
+	for i := range board {
+		board[i] = make([]int, p.Cols)
+	}
+	cols, regions := make([]int, p.Cols), map[int]int{}
+	var place func(i, j, left int) int
+	place = func(i, j, left int) int {
+		if i == p.Rows {
+			for _, n := range cols {
+				if n != p.Stars {
+					return 0
+				}
+			}
+			for _, n := range regions {
+				if n != p.Stars {
+					return 0
+				}
+			}
+			return 1
+		}
+		if left == 0 {
+			return place(i+1, 0, p.Stars)
+		}
+		n := 0
+		for ; j < p.Cols; j++ {
+			touch := j > 0 && board[i][j-1] == 1
+			for dj := -1; dj <= 1; dj++ {
+				if i > 0 && j+dj >= 0 && j+dj < p.Cols && board[i-1][j+dj] == 1 {
+					touch = true
+				}
+			}
+			r := p.Regions[i][j]
+			if touch || cols[j] == p.Stars || regions[r] == p.Stars {
+				continue
+			}
+			board[i][j] = 1
+			cols[j]++
+			regions[r]++
+			n += place(i, j+1, left-1)
+			board[i][j] = 0
+			cols[j]--
+			regions[r]--
+		}
+		return n
+	}
+	return place(0, 0, p.Stars)
+}
+
+func TestStarBattleCount(t *testing.T) {
+	p, err := ParseStarBattle(`
+		AAAABBBB
+		AAAABBBB
+		CCCCDDDD
+		CCCCDDDD
+		EEEEFFFF
+		EEEEFFFF
+		GGGGHHHH
+		GGGGHHHH`, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// each board once, the slots of the counts taking its stars in order
+	if want := bruteStars(p); want != 2 {
+		t.Fatalf("Star battle has %v boards (wants 2)", want)
+	}
+	if n := count(t, p.Template()); n != 2 {
+		t.Errorf("Star battle has %v solutions (wants 2)", n)
+	}
+}
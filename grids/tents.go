@@ -0,0 +1,158 @@
+package grids
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Values of the cells of a tents board: grass, or a tent whose tree is in the
+// given direction. Trees are holes of the board.
+const (
+	Grass = iota
+	TentUp
+	TentDown
+	TentLeft
+	TentRight
+)
+
+// Tents puzzle: pair every tree with a tent next to it, orthogonally, so
+// that the rows and columns hold the given numbers of tents and no two tents
+// touch, not even diagonally.
+type Tents struct {
+	Trees [][]bool
+	// tents of each row and column, -1 when unknown
+	RowCounts, ColCounts []int
+}
+
+// Parses a tents puzzle from the tents of the columns, on the first line, and
+// the lines of the board, one char per cell with T for a tree, each followed
+// by the tents of its row. An unknown number is written "?":
+//
+//	  1 0 1
+//	.T. 1
+//	..T 0
+//	T.. 1
+func ParseTents(s string) (*Tents, error) {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) < 2 {
+		return nil, errors.New("tents has no row")
+	}
+	count := func(f string) (int, error) {
+		if f == "?" {
+			return -1, nil
+		}
+		n, err := strconv.Atoi(f)
+		if err == nil && n < 0 {
+			err = fmt.Errorf("negative count %v", n)
+		}
+		return n, err
+	}
+	p := &Tents{}
+	for _, f := range strings.Fields(lines[0]) {
+		n, err := count(f)
+		if err != nil {
+			return nil, fmt.Errorf("columns: %v", err)
+		}
+		p.ColCounts = append(p.ColCounts, n)
+	}
+	for i, line := range lines[1:] {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || len(fields[0]) != len(p.ColCounts) {
+			return nil, fmt.Errorf("row %v: wants %v cells and a count", i, len(p.ColCounts))
+		}
+		n, err := count(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("row %v: %v", i, err)
+		}
+		p.RowCounts = append(p.RowCounts, n)
+		trees := make([]bool, len(fields[0]))
+		for j, c := range fields[0] {
+			trees[j] = c == 'T'
+		}
+		p.Trees = append(p.Trees, trees)
+	}
+	return p, nil
+}
+
+// Declares the puzzle: a cell holds grass or a tent telling its tree, the
+// tents telling no tree being excluded, which makes the pairing a rule of the
+// placements next to each tree. The tents of a line take their count by
+// slots, and the 2x2 blocks hold at most one tent through secondary items.
+func (p *Tents) Template() *Template {
+	t := New("tents", len(p.RowCounts), len(p.ColCounts), Grass, TentUp, TentDown, TentLeft, TentRight)
+	trees := make([][]Placement, 0)
+	for i, row := range p.Trees {
+		for j, tree := range row {
+			if tree {
+				t.Hole(Cell{i, j})
+				trees = append(trees, []Placement{
+					{Cell{i + 1, j}, TentUp}, {Cell{i - 1, j}, TentDown},
+					{Cell{i, j + 1}, TentLeft}, {Cell{i, j - 1}, TentRight},
+				})
+			}
+		}
+	}
+	// a tent tells a tree
+	for i := range p.Trees {
+		for j := range p.Trees[i] {
+			for v, tree := range map[int]Cell{TentUp: {i - 1, j}, TentDown: {i + 1, j}, TentLeft: {i, j - 1}, TentRight: {i, j + 1}} {
+				if tree.Row < 0 || tree.Row >= len(p.Trees) || tree.Col < 0 || tree.Col >= len(p.Trees[i]) || !p.Trees[tree.Row][tree.Col] {
+					t.Exclude(Placement{Cell{i, j}, v})
+				}
+			}
+		}
+	}
+	t.CountOf("tree", 1, trees...)
+	lines := func(name string, counts []int, sets [][]Cell) {
+		for i, k := range counts {
+			switch {
+			case k == 0:
+				for _, c := range t.onBoard(sets[i]) {
+					t.Given(c, Grass)
+				}
+			case k > 0:
+				t.CountOf(fmt.Sprintf("%v%v", name, i), k, Placements(sets[i], TentUp, TentDown, TentLeft, TentRight))
+			}
+		}
+	}
+	lines("row", p.RowCounts, t.RowCells())
+	lines("col", p.ColCounts, t.ColCells())
+	blocks := make([][]Placement, 0)
+	for _, cells := range t.BlockCells(2, 2) {
+		blocks = append(blocks, Placements(cells, TentUp, TentDown, TentLeft, TentRight))
+	}
+	t.AtMostOf("touch", blocks...)
+	return t
+}
+
+// Solves the puzzle, returning the board with the value of each cell and -1
+// for the trees, or nil if there is no solution.
+func (p *Tents) Solve() [][]int {
+	t := p.Template()
+	s, err := t.Solver()
+	if err != nil {
+		// the template only declares supported items
+		panic(err)
+	}
+	O := s.Solve()
+	if O.Len() == 0 {
+		return nil
+	}
+	return t.Grid(s.Decode(O))
+}
+
+// Counts the solutions, e.g. to check that a puzzle has a single one.
+func (p *Tents) Count() int {
+	s, err := p.Template().Solver()
+	if err != nil {
+		panic(err)
+	}
+	return int(s.Count().Int64())
+}
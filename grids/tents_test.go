@@ -0,0 +1,126 @@
+package grids
+
+import (
+	"testing"
+)
+
+func TestTents(t *testing.T) {
+	p, err := ParseTents(`
+		  2 0 1 0 2
+		..... 2
+		T...T 0
+		.T... 1
+		T...T 0
+		..... 2
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	board := p.Solve()
+	if board == nil {
+		t.Fatal("No solution")
+	}
+	rows, cols := make([]int, len(p.RowCounts)), make([]int, len(p.ColCounts))
+	paired := map[Cell]bool{}
+	for i, row := range board {
+		for j, v := range row {
+			if v <= Grass {
+				continue
+			}
+			rows[i]++
+			cols[j]++
+			tree := map[int]Cell{TentUp: {i - 1, j}, TentDown: {i + 1, j}, TentLeft: {i, j - 1}, TentRight: {i, j + 1}}[v]
+			if !p.Trees[tree.Row][tree.Col] || paired[tree] {
+				t.Errorf("Tent %v,%v pairs with %v", i, j, tree)
+			}
+			paired[tree] = true
+			for _, d := range []Cell{{i, j + 1}, {i + 1, j - 1}, {i + 1, j}, {i + 1, j + 1}} {
+				if d.Row < len(board) && d.Col >= 0 && d.Col < len(row) && board[d.Row][d.Col] > Grass {
+					t.Errorf("Tent %v,%v touches %v", i, j, d)
+				}
+			}
+		}
+	}
+	for i := range rows {
+		if rows[i] != p.RowCounts[i] || cols[i] != p.ColCounts[i] {
+			t.Errorf("Line %v holds %v and %v tents (wants %v and %v)", i, rows[i], cols[i], p.RowCounts[i], p.ColCounts[i])
+		}
+	}
+	if len(paired) != 5 {
+		t.Errorf("%v trees paired (wants 5)", len(paired))
+	}
+	if n := p.Count(); n != 1 {
+		t.Errorf("%v solutions (wants 1)", n)
+	}
+}
+
+// Counts the boards meeting the rules of the tents puzzle by trying every
+// tent of every tree.
+func bruteTents(p *Tents) int {
+	trees := make([]Cell, 0)
+	for i, row := range p.Trees {
+		for j, tree := range row {
+			if tree {
+				trees = append(trees, Cell{i, j})
+			}
+		}
+	}
+	tents := make([]Cell, len(trees))
+	var pair func(n int) int
+	pair = func(n int) int {
+		if n == len(trees) {
+			rows, cols := make([]int, len(p.RowCounts)), make([]int, len(p.ColCounts))
+			for a, c := range tents {
+				rows[c.Row]++
+				cols[c.Col]++
+				for _, d := range tents[:a] {
+					if d.Row-c.Row <= 1 && c.Row-d.Row <= 1 && d.Col-c.Col <= 1 && c.Col-d.Col <= 1 {
+						return 0
+					}
+				}
+			}
+			for i, k := range p.RowCounts {
+				if k >= 0 && rows[i] != k {
+					return 0
+				}
+			}
+			for j, k := range p.ColCounts {
+				if k >= 0 && cols[j] != k {
+					return 0
+				}
+			}
+			return 1
+		}
+		count := 0
+		for _, d := range []Cell{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+			c := Cell{trees[n].Row + d.Row, trees[n].Col + d.Col}
+			if c.Row >= 0 && c.Row < len(p.RowCounts) && c.Col >= 0 && c.Col < len(p.ColCounts) && !p.Trees[c.Row][c.Col] {
+				tents[n] = c
+				count += pair(n + 1)
+			}
+		}
+		return count
+	}
+	return pair(0)
+}
+
+func TestTentsCount(t *testing.T) {
+	p, err := ParseTents(`
+		  ? ? ? ? ?
+		.T.T. 2
+		..... ?
+		T...T ?
+		..... ?
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// each board once, the slots of the count taking its tents in order, and
+	// no tent without a tree where the lines are unknown
+	if want := bruteTents(p); want != 16 {
+		t.Fatalf("%v boards (wants 16)", want)
+	}
+	if n := p.Count(); n != 16 {
+		t.Errorf("%v solutions (wants 16)", n)
+	}
+}
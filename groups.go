@@ -0,0 +1,240 @@
+package cover
+
+import (
+	"errors"
+)
+
+// Error returned when naming a column group which is not defined.
+var ErrNoGroup = errors.New("no such column group")
+
+// Named sets of columns, like the row constraints of a sudoku.
+type columnGroups struct {
+	names []string
+	cols  map[string][]*Node
+	// group index of each column, and branching steps per group
+	of       map[*Node]int
+	branches []int64
+	// headers of the disabled columns, in the order disabled
+	disabled []*Node
+	// whether each disabled column was secondary
+	wasSecondary []bool
+	// primary columns the search branches on first, nil if none
+	preferred []*Node
+}
+
+// Statistics of a column group.
+type GroupStats struct {
+	Name string
+	// columns of the group, and those still to be covered
+	Cols, Active int
+	// rows left in the active columns, counted once per column
+	Rows int
+	// branching steps on a column of the group since the last ResetStats()
+	Branches int64
+}
+
+// Defines a group of the named columns, replacing any group of the same
+// name, e.g. to disable them at once or to branch on them first. A column
+// belongs to a single group, the last one defined. It must be called between
+// searches. Returns ErrNoColumn if a name is unknown, in which case no group
+// is defined.
+func (m *SparseMatrix) Group(name string, cols ...string) error {
+	nodes := make([]*Node, len(cols))
+	for i, c := range cols {
+		if nodes[i] = m.findCol(c); nodes[i] == nil {
+			return ErrNoColumn
+		}
+	}
+	g := m.groups
+	if g == nil {
+		g = &columnGroups{cols: map[string][]*Node{}, of: map[*Node]int{}}
+		m.groups = g
+	}
+	if _, ok := g.cols[name]; !ok {
+		g.names = append(g.names, name)
+		g.branches = append(g.branches, 0)
+	}
+	index := 0
+	for i, n := range g.names {
+		if n == name {
+			index = i
+		}
+	}
+	for _, c := range g.cols[name] {
+		delete(g.of, c)
+	}
+	g.cols[name] = nodes
+	for _, c := range nodes {
+		if old, ok := g.of[c]; ok && old != index {
+			// the column leaves its previous group
+			prev := g.cols[g.names[old]]
+			for i, p := range prev {
+				if p == c {
+					g.cols[g.names[old]] = append(prev[:i:i], prev[i+1:]...)
+					break
+				}
+			}
+		}
+		g.of[c] = index
+	}
+	return nil
+}
+
+// Groups every column by the name f returns for it, skipping the columns for
+// which it returns an empty name, e.g. to group the sudoku constraints by
+// their kind from the headers.
+func (m *SparseMatrix) GroupBy(f func(col string) string) {
+	byName := map[string][]string{}
+	names := make([]string, 0)
+	m.forEachCol(func(col *Node) {
+		name := f(col.Name)
+		if name == "" {
+			return
+		}
+		if _, ok := byName[name]; !ok {
+			names = append(names, name)
+		}
+		byName[name] = append(byName[name], col.Name)
+	})
+	for _, name := range names {
+		m.Group(name, byName[name]...)
+	}
+}
+
+// Returns the names of the groups, in the order defined.
+func (m *SparseMatrix) Groups() []string {
+	if m.groups == nil {
+		return nil
+	}
+	return append([]string{}, m.groups.names...)
+}
+
+// Returns the columns of the group, or ErrNoGroup.
+func (m *SparseMatrix) groupCols(name string) ([]*Node, error) {
+	if m.groups == nil {
+		return nil, ErrNoGroup
+	}
+	cols, ok := m.groups.cols[name]
+	if !ok {
+		return nil, ErrNoGroup
+	}
+	return cols, nil
+}
+
+// Drops the constraints of the columns of the group still in the matrix, as
+// if they had not been built: the rows no longer cover them, and solutions
+// need not cover them. It must be called between searches, and undone by
+// EnableGroups(). Returns ErrNoGroup if the group is not defined.
+func (m *SparseMatrix) DisableGroup(name string) error {
+	cols, err := m.groupCols(name)
+	if err != nil {
+		return err
+	}
+	secondary := m.secondaryCols()
+	g := m.groups
+	for _, c := range cols {
+		if c.Left.Right != c {
+			// already disabled or covered
+			continue
+		}
+		if c.bucket != nil {
+			c.bucket.remove(c)
+		}
+		c.Right.Left = c.Left
+		c.Left.Right = c.Right
+		for n := c.Down; n != c; n = n.Down {
+			n.Right.Left = n.Left
+			n.Left.Right = n.Right
+		}
+		g.disabled = append(g.disabled, c)
+		g.wasSecondary = append(g.wasSecondary, secondary[c])
+	}
+	return nil
+}
+
+// Restores the columns of all the disabled groups, in the reverse order of
+// their removal as the dancing links require.
+func (m *SparseMatrix) EnableGroups() {
+	g := m.groups
+	if g == nil {
+		return
+	}
+	for i := len(g.disabled) - 1; i >= 0; i-- {
+		c := g.disabled[i]
+		for n := c.Up; n != c; n = n.Up {
+			n.Right.Left = n
+			n.Left.Right = n
+		}
+		c.Right.Left = c
+		c.Left.Right = c
+		if c.bucket != nil && !g.wasSecondary[i] {
+			c.bucket.insert(c)
+		}
+	}
+	g.disabled, g.wasSecondary = g.disabled[:0], g.wasSecondary[:0]
+}
+
+// Makes the search branch on the smallest active primary column of the group
+// while there is one, before the other columns, e.g. to fill a puzzle region
+// by region. An empty name restores the default choice. The columns are the
+// primary ones when called. Returns ErrNoGroup if the group is not defined.
+func (m *SparseMatrix) PreferGroup(name string) error {
+	if name == "" {
+		if m.groups != nil {
+			m.groups.preferred = nil
+		}
+		return nil
+	}
+	cols, err := m.groupCols(name)
+	if err != nil {
+		return err
+	}
+	secondary := m.secondaryCols()
+	preferred := make([]*Node, 0, len(cols))
+	for _, c := range cols {
+		if !secondary[c] {
+			preferred = append(preferred, c)
+		}
+	}
+	m.groups.preferred = preferred
+	return nil
+}
+
+// Returns the smallest active column of the preferred group, nil if none.
+func (g *columnGroups) smallestPreferred() *Node {
+	var r *Node
+	for _, c := range g.preferred {
+		if c.Left.Right == c && (r == nil || c.Size < r.Size) {
+			r = c
+		}
+	}
+	return r
+}
+
+// Counts a branching step on the column.
+func (g *columnGroups) branched(c *Node) {
+	if i, ok := g.of[c]; ok {
+		g.branches[i]++
+	}
+}
+
+// Returns the statistics of every group, in the order defined. The active
+// columns are those neither covered nor disabled.
+func (m *SparseMatrix) GroupStats() []GroupStats {
+	if m.groups == nil {
+		return nil
+	}
+	g := m.groups
+	stats := make([]GroupStats, len(g.names))
+	for i, name := range g.names {
+		s := GroupStats{Name: name, Cols: len(g.cols[name]), Branches: g.branches[i]}
+		for _, c := range g.cols[name] {
+			if c.Left.Right == c {
+				s.Active++
+				s.Rows += int(c.Size)
+			}
+		}
+		stats[i] = s
+	}
+	return stats
+}
@@ -0,0 +1,63 @@
+package cover
+
+import (
+	"strings"
+	"testing"
+)
+
+// Groups the columns of a sudoku matrix by the kind of their constraint.
+func sudokuGroup(col string) string {
+	switch {
+	case strings.Contains(col, ","):
+		return "cell"
+	case strings.Contains(col, "r"):
+		return "row"
+	case strings.Contains(col, "c"):
+		return "col"
+	}
+	return "box"
+}
+
+func TestGroups(t *testing.T) {
+	m, h := SudokuConstraintMatrix(4)
+	s := NewSolver(m, h, WithSizeTracking())
+	s.matrix.GroupBy(sudokuGroup)
+	if groups := strings.Join(s.matrix.Groups(), " "); groups != "cell row col box" {
+		t.Fatalf("Groups are %v", groups)
+	}
+	if n := s.Count(); n.Int64() != 288 {
+		t.Errorf("Sudoku has %v solutions (wants 288)", n)
+	}
+	if err := s.matrix.DisableGroup("box"); err != nil {
+		t.Fatal(err)
+	}
+	if n := s.Count(); n.Int64() != 576 {
+		t.Errorf("Latin squares number %v (wants 576)", n)
+	}
+	for _, st := range s.matrix.GroupStats() {
+		if st.Cols != 16 || st.Name == "box" && st.Active != 0 || st.Name != "box" && st.Active != 16 {
+			t.Errorf("Group stats are %+v", st)
+		}
+	}
+	s.matrix.EnableGroups()
+	if err := s.matrix.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.matrix.PreferGroup("box"); err != nil {
+		t.Fatal(err)
+	}
+	if n := s.Count(); n.Int64() != 288 {
+		t.Errorf("Sudoku has %v solutions preferring boxes (wants 288)", n)
+	}
+	for _, st := range s.matrix.GroupStats() {
+		if (st.Name == "box") != (st.Branches > 0) {
+			t.Errorf("Group %v has %v branches preferring boxes", st.Name, st.Branches)
+		}
+	}
+	if err := s.matrix.DisableGroup("nope"); err != ErrNoGroup {
+		t.Errorf("Unknown group is disabled with %v", err)
+	}
+	if err := s.matrix.Group("bad", "A"); err != ErrNoColumn {
+		t.Errorf("Group of an unknown column is defined with %v", err)
+	}
+}
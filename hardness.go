@@ -0,0 +1,72 @@
+package cover
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// How constraining a primary column is, from a lookahead of two levels of the
+// search tree under it, see Hardness().
+type ColumnHardness struct {
+	Name string
+	// rows covering the column, and the ones not leading to a dead end
+	Size, Viable int
+	// mean size of the smallest column left by the viable rows, which is the
+	// expected branching factor of the search once the column is covered
+	Branching float64
+	// bits of choice of both levels, log2(Viable) + log2(Branching), 0 when
+	// no row is viable
+	Entropy float64
+}
+
+func (h ColumnHardness) String() string {
+	return fmt.Sprintf("%v: %v rows, %v viable, branching %.3g, %.3g bits", h.Name, h.Size, h.Viable, h.Branching, h.Entropy)
+}
+
+// Reports how constraining each primary column is, the most constraining
+// first. The search gains from branching on the first ones, while the last
+// ones, which leave many rows and much branching below them, often point to a
+// weak encoding, e.g. a constraint better split into several columns. Each
+// column costs a cover per row, so the report is cheap next to a search.
+func (s *Solver) Hardness() []ColumnHardness {
+	m := s.matrix
+	root := m.Root()
+	cols := make([]*Node, 0)
+	for col := root.Right; col != root; col = col.Right {
+		cols = append(cols, col)
+	}
+	report := make([]ColumnHardness, len(cols))
+	for i, c := range cols {
+		report[i] = m.hardness(c)
+	}
+	sort.SliceStable(report, func(i, j int) bool {
+		a, b := report[i], report[j]
+		if a.Entropy != b.Entropy {
+			return a.Entropy < b.Entropy
+		}
+		return a.Viable < b.Viable
+	})
+	return report
+}
+
+// Tries each row of the column, measuring the branching left below it.
+func (m *SparseMatrix) hardness(c *Node) ColumnHardness {
+	h := ColumnHardness{Name: c.Name, Size: int(c.Size)}
+	root := m.Root()
+	m.descend(nil, 0, descent{col: c, tentative: true, visit: func(*Node) bool {
+		h.Viable++
+		// covering the last columns leaves a single branch, the solution
+		branching := 1.0
+		if root.Right != root {
+			branching = float64(m.SmallestCol().Size)
+		}
+		h.Branching += branching
+		return false
+	}})
+	if h.Viable > 0 {
+		h.Branching /= float64(h.Viable)
+		h.Entropy = math.Log2(float64(h.Viable)) + math.Log2(h.Branching)
+	}
+	return h
+}
@@ -0,0 +1,31 @@
+package cover
+
+import (
+	"testing"
+)
+
+func TestHardness(t *testing.T) {
+	s := NewSolver(knuth(), []string{"A", "B", "C", "D", "E", "F", "G"})
+	report := s.Hardness()
+	if len(report) != 7 {
+		t.Fatalf("Report has %v columns", len(report))
+	}
+	for i := 1; i < len(report); i++ {
+		if report[i-1].Entropy > report[i].Entropy {
+			t.Errorf("Report is not sorted: %v before %v", report[i-1], report[i])
+		}
+	}
+	for _, h := range report {
+		if h.Viable > h.Size || h.Viable > 0 && h.Branching < 1 {
+			t.Errorf("Column hardness %v", h)
+		}
+		// A has the rows A D G and A D, both leaving E with the single row C E F
+		if h.Name == "A" && (h.Size != 2 || h.Viable != 2 || h.Branching != 1) {
+			t.Errorf("Column A hardness %v", h)
+		}
+	}
+	// the report restores the matrix
+	if n := s.Count().Int64(); n != 1 {
+		t.Errorf("Count after the report is %v", n)
+	}
+}
@@ -0,0 +1,51 @@
+package cover
+
+import (
+	"fmt"
+)
+
+// Kind of event reported to hooks.
+type Event int
+
+const (
+	CoverEvent Event = iota
+	UncoverEvent
+)
+
+func (e Event) String() string {
+	if e == CoverEvent {
+		return "cover"
+	}
+	return "uncover"
+}
+
+// Called each time the search covers or uncovers a column, with the depth of
+// the search. A hook may stop the search with Abort(), e.g. to prune it with
+// custom logic, but must not modify the matrix.
+type Hook func(e Event, col *Node, depth int)
+
+// Registers a hook, fired after the hooks already registered.
+func (m *SparseMatrix) AddHook(h Hook) {
+	m.hooks = append(m.hooks, h)
+}
+
+// Covers the column on behalf of the search, firing the hooks.
+func (m *SparseMatrix) cover(c *Node, depth int) {
+	c.Cover()
+	for _, h := range m.hooks {
+		h(CoverEvent, c, depth)
+	}
+}
+
+// Uncovers the column on behalf of the search, firing the hooks.
+func (m *SparseMatrix) uncover(c *Node, depth int) {
+	for _, h := range m.hooks {
+		h(UncoverEvent, c, depth)
+	}
+	c.Uncover()
+	if m.selfCheck {
+		if err := m.check(); err != nil {
+			panic(fmt.Sprintf("uncover %v at depth %v: %v", c.Name, depth, err))
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package cover
+
+import (
+	"fmt"
+)
+
+// Appends a node to the column, keeping its size bucket up to date.
+func (m *SparseMatrix) appendToCol(c, n *Node) {
+	b := c.bucket
+	if b != nil {
+		b.remove(c)
+	}
+	c.ColAppend(n)
+	if b != nil {
+		for int(c.Size) >= len(m.sizes.heads) {
+			m.sizes.heads = append(m.sizes.heads, nil)
+		}
+		b.insert(c)
+	}
+	m.nodes++
+}
+
+// Returns the number of rows, hidden ones included.
+func (m *SparseMatrix) rowCount() int {
+	n := 0
+	count := func(r *Node) {
+		if r.Row >= n {
+			n = r.Row + 1
+		}
+	}
+	m.forEachCol(func(col *Node) {
+		for r := col.Down; r != col; r = r.Down {
+			count(r)
+		}
+	})
+	for _, r := range m.hidden {
+		count(r)
+	}
+	return n
+}
+
+// Adds a row covering the named columns, whose index follows the existing
+// rows. It must be called between searches, e.g. to try another placement
+// after inspecting a solution, which avoids rebuilding the matrix.
+// Returns ErrNoColumn if a name is unknown, in which case nothing is added.
+func (m *SparseMatrix) AddRow(names ...string) (*Node, error) {
+	cols := make([]*Node, len(names))
+	for i, name := range names {
+		if cols[i] = m.findCol(name); cols[i] == nil {
+			return nil, ErrNoColumn
+		}
+	}
+	i := m.rowCount()
+	var first *Node
+	for _, c := range cols {
+		n := NewNode()
+		n.Row = i
+		m.appendToCol(c, n)
+		if first != nil {
+			first.RowAppend(n)
+		} else {
+			first = n
+		}
+	}
+	return first, nil
+}
+
+// Adds a column covered by the given rows, given by any of their nodes.
+// A primary column requires one of the rows in every solution, a secondary
+// one forbids more than one of them. It must be called between searches.
+func (m *SparseMatrix) AddColumn(name string, secondary bool, rows ...*Node) *Node {
+	c := NewColNode(name)
+	m.Root().RowAppend(c)
+	if m.sizes != nil {
+		c.bucket = &bucket{index: m.sizes}
+		c.bucket.insert(c)
+	}
+	m.cols++
+	for _, r := range rows {
+		n := NewNode()
+		n.Row = r.Row
+		m.appendToCol(c, n)
+		// last when walking the row from the given node
+		r.RowAppend(n)
+	}
+	if secondary {
+		m.setSecondary(c)
+	}
+	return c
+}
+
+// Forbids solutions holding more than one of the rows, e.g. a combination of
+// two rows a user rejected, by adding a secondary column they all cover.
+func (m *SparseMatrix) Forbid(rows ...*Node) *Node {
+	return m.AddColumn(fmt.Sprintf("forbid#%v", m.cols), true, rows...)
+}
@@ -0,0 +1,59 @@
+package cover
+
+import (
+	"testing"
+)
+
+func TestForbid(t *testing.T) {
+	s := dominoes(2, 3)
+	all := s.SolveAll()
+	O := s.Solve()
+	a, b := O.Get(0), O.Get(1)
+	s.Reset()
+	s.matrix.Forbid(a, b)
+	want := 0
+	for _, sol := range all {
+		rows := map[int]bool{}
+		for _, r := range *sol {
+			rows[r.Row] = true
+		}
+		if !rows[a.Row] || !rows[b.Row] {
+			want++
+		}
+	}
+	if n := s.Count(); n.Int64() != int64(want) {
+		t.Errorf("Tilings without rows %v and %v: %v (wants %v)", a.Row, b.Row, n, want)
+	}
+	O = s.Solve()
+	rows := map[int]bool{}
+	for _, r := range *O {
+		rows[r.Row] = true
+	}
+	if O.Len() != 3 || rows[a.Row] && rows[b.Row] {
+		t.Errorf("Re-solving finds %v", O)
+	}
+}
+
+func TestAddRowAndColumn(t *testing.T) {
+	s := dominoes(2, 3)
+	s.matrix.TrackSizes()
+	square, err := s.matrix.AddRow("0,0", "0,1", "1,0", "1,1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.matrix.AddRow("0,0", "9,9"); err != ErrNoColumn {
+		t.Errorf("Adding a row with an unknown column returns %v (wants %v)", err, ErrNoColumn)
+	}
+	// the square and a vertical domino
+	if n := s.Count(); n.Int64() != 4 {
+		t.Errorf("Tilings with a square: %v (wants %v)", n, 4)
+	}
+	s.matrix.AddColumn("square", false, square)
+	if O := s.Solve(); O.Len() != 2 || O.Get(0).Row != square.Row {
+		t.Errorf("Square column is covered by %v", O)
+	}
+	s.Reset()
+	if n := s.Count(); n.Int64() != 1 {
+		t.Errorf("Tilings requiring the square: %v (wants %v)", n, 1)
+	}
+}
@@ -0,0 +1,316 @@
+package cover
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Append-only record of the decisions of the searches of a matrix, one per
+// line:
+//
+//	start               a search begins
+//	resume              a search resumes the one journaled above
+//	try <depth> <row>   the row is tried at the depth
+//	back <depth> <row>  the subtree of the row is exhausted
+//	solution [<rows>]   a solution is found, with its sorted rows
+//	end <aborted>       the search is over
+//
+// Lines are written out in blocks, after every solution and at the end of
+// each search. A crash may lose the last decisions, which a resumed search
+// then explores again, but never a solution.
+type Journal struct {
+	w   io.Writer
+	buf []byte
+	// first write error, after which the journal is discarded
+	err error
+}
+
+// Starts journaling the following searches to w, replacing any previous
+// journal, which is flushed. Forced rows of unit propagation are not
+// journaled.
+func (m *SparseMatrix) Journal(w io.Writer) *Journal {
+	m.journal.Flush()
+	m.journal = &Journal{w: w}
+	return m.journal
+}
+
+// Buffers a line, writing out the buffered lines once they fill a block.
+// A nil journal discards it.
+func (j *Journal) printf(format string, a ...interface{}) {
+	if j == nil || j.err != nil {
+		return
+	}
+	j.buf = fmt.Appendf(j.buf, format, a...)
+	j.buf = append(j.buf, '\n')
+	if len(j.buf) >= traceBlock {
+		j.Flush()
+	}
+}
+
+// Writes out the buffered lines and returns the first write error of the
+// journal.
+func (j *Journal) Flush() error {
+	if j == nil {
+		return nil
+	}
+	if j.err == nil && len(j.buf) > 0 {
+		_, j.err = j.w.Write(j.buf)
+	}
+	j.buf = j.buf[:0]
+	return j.err
+}
+
+// Records the start of a search, telling whether it resumes a journal.
+func (m *SparseMatrix) journalStart() {
+	if m.resume != nil {
+		m.journal.printf("resume")
+	} else {
+		m.journal.printf("start")
+	}
+}
+
+// Records the end of a search and flushes the journal. The point to resume
+// from only applies to one search.
+func (m *SparseMatrix) journalEnd() {
+	m.resume = nil
+	if m.journal == nil {
+		return
+	}
+	m.journal.printf("end %v", m.aborted)
+	m.journal.Flush()
+}
+
+// Records a solution and flushes the journal.
+func (m *SparseMatrix) journalSolution(O *Solution) {
+	if m.journal == nil {
+		return
+	}
+	m.journal.printf("solution %v", O.Rows())
+	m.journal.Flush()
+}
+
+// Decision read back from a journal.
+type JournalEntry struct {
+	// start, resume, try, back, solution or end
+	Kind string
+	// depth and row index of a try or a back
+	Depth, Row int
+	// sorted rows of a solution
+	Rows []int
+	// whether the search of an end was aborted
+	Aborted bool
+}
+
+// Last search of a journal, resumed searches included.
+type JournalLog struct {
+	// decisions since the last start
+	Entries []JournalEntry
+	// rows of the branch explored last, by depth, -1 at the depths of forced
+	// rows, and whether the deepest one was backtracked
+	Path     []int
+	Finished bool
+	// solutions found so far
+	Solutions [][]int
+	// whether the search returned without being aborted, so that resuming it
+	// finds nothing more
+	Complete bool
+	// bytes of the whole lines read, to truncate a journal cut by a crash to
+	// before appending to it
+	Size int64
+}
+
+// Reads a journal written by a matrix. A last line cut by a crash is ignored.
+func ReadJournal(r io.Reader) (*JournalLog, error) {
+	l := &JournalLog{}
+	br := bufio.NewReader(r)
+	for n := 1; ; n++ {
+		line, err := br.ReadString('\n')
+		if err == io.EOF {
+			return l, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		e, ok := parseJournalEntry(strings.TrimSuffix(line, "\n"))
+		if !ok {
+			return nil, fmt.Errorf("journal line %v: bad entry %q", n, line)
+		}
+		l.add(e)
+		l.Size += int64(len(line))
+	}
+}
+
+// Parses a line of a journal.
+func parseJournalEntry(line string) (JournalEntry, bool) {
+	kind, rest, _ := strings.Cut(line, " ")
+	e := JournalEntry{Kind: kind}
+	switch kind {
+	case "start", "resume":
+		return e, rest == ""
+	case "try", "back":
+		depth, row, ok := strings.Cut(rest, " ")
+		var err1, err2 error
+		e.Depth, err1 = strconv.Atoi(depth)
+		e.Row, err2 = strconv.Atoi(row)
+		return e, ok && err1 == nil && err2 == nil && e.Depth >= 0
+	case "solution":
+		if !strings.HasPrefix(rest, "[") || !strings.HasSuffix(rest, "]") {
+			return e, false
+		}
+		e.Rows = make([]int, 0)
+		for _, f := range strings.Fields(rest[1 : len(rest)-1]) {
+			row, err := strconv.Atoi(f)
+			if err != nil {
+				return e, false
+			}
+			e.Rows = append(e.Rows, row)
+		}
+		return e, true
+	case "end":
+		aborted, err := strconv.ParseBool(rest)
+		e.Aborted = aborted
+		return e, err == nil
+	}
+	return e, false
+}
+
+// Applies a decision to the log.
+func (l *JournalLog) add(e JournalEntry) {
+	switch e.Kind {
+	case "start":
+		*l = JournalLog{Size: l.Size}
+	case "resume":
+		l.Path, l.Finished, l.Complete = nil, false, false
+	case "try", "back":
+		for len(l.Path) < e.Depth {
+			l.Path = append(l.Path, -1)
+		}
+		l.Path = append(l.Path[:e.Depth], e.Row)
+		l.Finished = e.Kind == "back"
+	case "solution":
+		l.Solutions = append(l.Solutions, e.Rows)
+	case "end":
+		l.Complete = !e.Aborted
+	}
+	l.Entries = append(l.Entries, e)
+}
+
+// Branch of the search tree to resume from, and solutions already found.
+type resumePoint struct {
+	path     []int
+	finished bool
+	found    map[string]bool
+	// whether the journaled search ran to its end
+	complete bool
+}
+
+// Makes the next search resume the journaled one, from the branch explored
+// last, skipping the solutions already journaled. Rows are only tried in the
+// same order when the matrix and its options are the same as then, without
+// random choices. Resuming a complete log searches nothing.
+func (m *SparseMatrix) Resume(l *JournalLog) {
+	p := &resumePoint{path: l.Path, finished: l.Finished, found: map[string]bool{}, complete: l.Complete}
+	for _, rows := range l.Solutions {
+		parts := make([]string, len(rows))
+		for i, r := range rows {
+			parts[i] = fmt.Sprint(r)
+		}
+		p.found[strings.Join(parts, ",")] = true
+	}
+	m.resume = p
+}
+
+// Returns the row to start the branch on the column at depth k from.
+func (p *resumePoint) start(c *Node, k int) *Node {
+	if p != nil && p.complete {
+		return c
+	}
+	if p == nil || k >= len(p.path) {
+		return c.Down
+	}
+	for r := c.Down; r != c; r = r.Down {
+		if r.Row == p.path[k] {
+			if p.finished && k == len(p.path)-1 {
+				return r.Down
+			}
+			return r
+		}
+	}
+	// the search went another way than journaled
+	p.path = p.path[:k]
+	return c.Down
+}
+
+// Forgets the journaled branch below depth k, once left.
+func (p *resumePoint) left(k int) {
+	if p != nil && len(p.path) > k {
+		p.path = p.path[:k]
+	}
+}
+
+// Tells whether the solution was found before resuming, and forgets it.
+func (p *resumePoint) skips(O *Solution) bool {
+	if p == nil || !p.found[O.Key()] {
+		return false
+	}
+	delete(p.found, O.Key())
+	return true
+}
+
+// Resumes the enumeration journaled in the log, see Resume(). Returns the
+// solutions found since, the ones found before being in the log.
+func (s *Solver) Resume(l *JournalLog) []*Solution {
+	s.matrix.Resume(l)
+	return s.SolveAll()
+}
+
+// Replays the tries and backtracks of the log on the matrix, recording an
+// animation of them, see Animate(). The branch explored last is then
+// backtracked, which the last frames show, restoring the matrix.
+func (m *SparseMatrix) AnimateJournal(l *JournalLog) *Animation {
+	a := m.Animate()
+	nodes := map[int]*Node{}
+	root := m.Root()
+	for col := root.Right; col != root; col = col.Right {
+		for _, r := range col.ColNodes() {
+			nodes[r.Row] = r
+		}
+	}
+	// rows of the branch being replayed, with their depths
+	path, depths := make([]*Node, 0), make([]int, 0)
+	back := func() {
+		r, k := path[len(path)-1], depths[len(depths)-1]
+		path, depths = path[:len(path)-1], depths[:len(depths)-1]
+		for j := r.Left; j != r; j = j.Left {
+			m.uncommit(j, k)
+		}
+		m.uncover(r.Col, k)
+	}
+	for _, e := range l.Entries {
+		r := nodes[e.Row]
+		if r == nil || e.Kind != "try" && e.Kind != "back" {
+			continue
+		}
+		for len(path) > 0 && depths[len(depths)-1] > e.Depth {
+			back()
+		}
+		if len(path) > 0 && depths[len(depths)-1] == e.Depth {
+			back()
+		}
+		if e.Kind == "back" {
+			continue
+		}
+		m.cover(r.Col, e.Depth)
+		for j := r.Right; j != r; j = j.Right {
+			m.commit(j, e.Depth)
+		}
+		path, depths = append(path, r), append(depths, e.Depth)
+	}
+	for len(path) > 0 {
+		back()
+	}
+	return a
+}
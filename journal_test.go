@@ -0,0 +1,89 @@
+package cover
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestJournal(t *testing.T) {
+	var out bytes.Buffer
+	s := NewSolver(knuth(), []string{"A", "B", "C", "D", "E", "F", "G"}, WithJournal(&out))
+	s.SolveAll()
+	l, err := ReadJournal(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !l.Complete || len(l.Solutions) != 1 || len(l.Solutions[0]) != 3 {
+		t.Errorf("Journal log is %+v", l)
+	}
+	if first := l.Entries[0]; first.Kind != "start" {
+		t.Errorf("Journal starts with %+v", first)
+	}
+	if _, err := ReadJournal(strings.NewReader("start\ntry x 1\n")); err == nil {
+		t.Error("Bad journal line is read")
+	}
+}
+
+func TestJournalResume(t *testing.T) {
+	matrix, headers, _ := GeneratePlantedCover(16, 40, 0.2, 3)
+	all := NewSolver(matrix, headers).SolveAll()
+	if len(all) < 2 {
+		t.Fatalf("Planted cover has %v solutions", len(all))
+	}
+	var out bytes.Buffer
+	s := NewSolver(matrix, headers, WithJournal(&out))
+	s.Each(func(*Solution) bool { return false })
+	// a crash cuts the last line of the journal
+	cut := out.Bytes()[:out.Len()-3]
+	l, err := ReadJournal(bytes.NewReader(cut))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.Complete || len(l.Solutions) != 1 || len(l.Path) == 0 {
+		t.Fatalf("Journal log of the crash is %+v", l)
+	}
+	out.Reset()
+	out.Write(cut[:l.Size])
+	resumed := NewSolver(matrix, headers, WithJournal(&out))
+	rest := resumed.Resume(l)
+	if len(rest)+1 != len(all) {
+		t.Errorf("Resuming finds %v solutions (wants %v)", len(rest), len(all)-1)
+	}
+	seen := map[string]bool{strings.ReplaceAll(strings.Trim(fmt.Sprint(l.Solutions[0]), "[]"), " ", ","): true}
+	for _, O := range rest {
+		if seen[O.Key()] {
+			t.Errorf("Solution %v is found twice", O.Rows())
+		}
+		seen[O.Key()] = true
+	}
+	// the journal holds both searches, the resumed one being complete
+	l, err = ReadJournal(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !l.Complete || len(l.Solutions) != len(all) {
+		t.Errorf("Journal log has %v solutions, complete %v", len(l.Solutions), l.Complete)
+	}
+	if rest := NewSolver(matrix, headers).Resume(l); len(rest) != 0 {
+		t.Errorf("Resuming a complete search finds %v solutions", len(rest))
+	}
+}
+
+func TestAnimateJournal(t *testing.T) {
+	var out bytes.Buffer
+	s := NewSolver(knuth(), []string{"A", "B", "C", "D", "E", "F", "G"}, WithJournal(&out))
+	s.SolveAll()
+	l, err := ReadJournal(&out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := s.matrix.AnimateJournal(l)
+	if len(a.Frames) == 0 || len(a.Frames)%2 != 0 {
+		t.Fatalf("Animation has %v frames", len(a.Frames))
+	}
+	if f := a.Frames[len(a.Frames)-1]; f.Event != "uncover" || len(f.Cols) != 7 || len(f.Rows) != 6 {
+		t.Errorf("Last frame is %+v", f)
+	}
+}
@@ -0,0 +1,25 @@
+package cover
+
+// Aborts each following search once it has tried n rows by branching, zero
+// meaning no limit. Unlike a deadline, the budget gives the same partial
+// search on every run, which suits fuzzers and rating heuristics bounding
+// their worst case. The statistics describe the partial search.
+func (m *SparseMatrix) SetNodeLimit(n int64) {
+	m.nodeLimit = n
+}
+
+// Aborts each following search once it would branch at depth d, i.e. with d
+// rows chosen, zero meaning no limit.
+func (m *SparseMatrix) SetDepthLimit(d int) {
+	m.depthLimit = d
+}
+
+// Tells whether the last search was aborted by its node or depth limit.
+func (m *SparseMatrix) LimitReached() bool {
+	return m.limited
+}
+
+// Tells whether the last search was aborted by its node or depth limit.
+func (s *Solver) LimitReached() bool {
+	return s.matrix.limited
+}
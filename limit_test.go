@@ -0,0 +1,38 @@
+package cover
+
+import (
+	"testing"
+)
+
+func TestNodeLimit(t *testing.T) {
+	rows, h := SudokuConstraintRows(4)
+	s := &Solver{matrix: NewSparseMatrixFromRows(rows, h)}
+	WithNodeLimit(100)(s)
+	n := s.Count()
+	stats := s.Stats()
+	if !s.LimitReached() || stats.Candidates() != 100 || n.Int64() >= 288 {
+		t.Errorf("Limit of 100 nodes tries %v rows and counts %v grids", stats.Candidates(), n)
+	}
+	if again := s.Count(); again.Cmp(n) != 0 {
+		t.Errorf("Limited counts differ: %v then %v", n, again)
+	}
+	if cols := s.matrix.ColCount(); cols != len(h) {
+		t.Errorf("Matrix has %v columns after aborting (wants %v)", cols, len(h))
+	}
+	WithNodeLimit(0)(s)
+	if n := s.Count(); n.Int64() != 288 || s.LimitReached() {
+		t.Errorf("Unlimited count is %v", n)
+	}
+}
+
+func TestDepthLimit(t *testing.T) {
+	h := []string{"A", "B", "C", "D", "E", "F", "G"}
+	// the solution has 3 rows
+	if s := NewSolver(knuth(), h, WithDepthLimit(3)); s.Count().Int64() != 1 || s.LimitReached() {
+		t.Errorf("Depth limit of 3 misses the solution of Knuth example")
+	}
+	s := NewSolver(knuth(), h, WithDepthLimit(1))
+	if n := s.Count(); n.Int64() != 0 || !s.LimitReached() {
+		t.Errorf("Depth limit of 1 counts %v solutions", n)
+	}
+}
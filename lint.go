@@ -0,0 +1,203 @@
+package cover
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Kind of suspicious encoding reported by Lint().
+type Issue int
+
+const (
+	// A primary column no row covers, so there is no solution.
+	EmptyColumn Issue = iota
+	// A column covered by every row, so a solution has a single row.
+	UniversalColumn
+	// A row whose columns are all in another row.
+	SubsumedRow
+	// Columns covered by the same rows, which are equivalent.
+	TwinColumns
+	// Groups of columns sharing no row, which are independent problems.
+	Components
+)
+
+func (i Issue) String() string {
+	switch i {
+	case EmptyColumn:
+		return "empty column"
+	case UniversalColumn:
+		return "universal column"
+	case SubsumedRow:
+		return "subsumed row"
+	case TwinColumns:
+		return "twin columns"
+	case Components:
+		return "components"
+	}
+	return "Issue(" + strconv.Itoa(int(i)) + ")"
+}
+
+// Suspicious encoding found by Lint(), with a suggested fix.
+type Diagnostic struct {
+	Issue Issue
+	Cols  []string
+	// row indexes
+	Rows []int
+	Fix  string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%v: columns %v, rows %v: %v", d.Issue, d.Cols, d.Rows, d.Fix)
+}
+
+// Reports the suspicious encodings of a problem: empty and universal columns,
+// rows subsumed by other rows, columns having the same rows, and columns
+// splitting into independent components. They are not errors but often point
+// to a mistake in a builder, or to a cheaper encoding.
+func Lint(s *Spec) []Diagnostic {
+	index := s.index()
+	rows := make([][]int, len(s.Options))
+	cols := make([][]int, len(s.Items))
+	// rows giving an item the same color do not exclude each other, so the
+	// rows are also compared as item:color pairs, an item alone being the
+	// pair of its index and the colored pairs numbered after the items
+	pairs := make([][]int, len(s.Options))
+	pairCols := make([][]int, len(s.Items))
+	pairNames := make([]string, len(s.Items))
+	for j, item := range s.Items {
+		pairNames[j] = item.Name
+	}
+	colored := map[string]int{}
+	for i, o := range s.Options {
+		for _, name := range o.Items {
+			j, color, ok := resolve(index, name)
+			if !ok {
+				continue
+			}
+			rows[i] = append(rows[i], j)
+			cols[j] = append(cols[j], i)
+			p := j
+			if color != "" {
+				name = s.Items[j].Name + ":" + color
+				if p, ok = colored[name]; !ok {
+					p = len(pairCols)
+					colored[name] = p
+					pairCols = append(pairCols, nil)
+					pairNames = append(pairNames, name)
+				}
+			}
+			pairs[i] = append(pairs[i], p)
+			pairCols[p] = append(pairCols[p], i)
+		}
+		sort.Ints(rows[i])
+		sort.Ints(pairs[i])
+	}
+	diags := make([]Diagnostic, 0)
+	for j, item := range s.Items {
+		switch {
+		case len(cols[j]) == 0 && !item.Secondary && (item.Min > 0 || item.Max == 0):
+			diags = append(diags, Diagnostic{Issue: EmptyColumn, Cols: []string{item.Name},
+				Fix: "add the rows covering it, or make it secondary"})
+		case len(pairCols[j]) == len(rows) && len(rows) > 1:
+			diags = append(diags, Diagnostic{Issue: UniversalColumn, Cols: []string{item.Name}, Rows: pairCols[j],
+				Fix: "check the rows, or drop the column if a single row is meant"})
+		}
+	}
+	// a row is only subsumed by rows sharing its first pair
+	for i, r := range pairs {
+		if len(r) == 0 {
+			continue
+		}
+		for _, k := range pairCols[r[0]] {
+			if k != i && subset(r, pairs[k]) && (len(r) < len(pairs[k]) || i > k) {
+				diags = append(diags, Diagnostic{Issue: SubsumedRow, Rows: []int{i, k}, Cols: names(pairNames, r),
+					Fix: fmt.Sprintf("drop row %v if it is a duplicate, or check its missing columns", i)})
+				break
+			}
+		}
+	}
+	twins := map[string][]string{}
+	keys := make([]string, 0)
+	for j, c := range pairCols[:len(s.Items)] {
+		if len(c) == 0 {
+			continue
+		}
+		key := fmt.Sprint(c)
+		if twins[key] == nil {
+			keys = append(keys, key)
+		}
+		twins[key] = append(twins[key], s.Items[j].Name)
+	}
+	for _, key := range keys {
+		if len(twins[key]) > 1 {
+			diags = append(diags, Diagnostic{Issue: TwinColumns, Cols: twins[key],
+				Fix: "merge them into one column"})
+		}
+	}
+	if groups := components(len(s.Items), rows); len(groups) > 1 {
+		for g, group := range groups {
+			diags = append(diags, Diagnostic{Issue: Components, Cols: names(pairNames, group),
+				Fix: fmt.Sprintf("solve component %v of %v separately", g+1, len(groups))})
+		}
+	}
+	return diags
+}
+
+// Tells whether the sorted list a is included in the sorted list b.
+func subset(a, b []int) bool {
+	i := 0
+	for _, x := range b {
+		if i < len(a) && a[i] == x {
+			i++
+		}
+	}
+	return i == len(a)
+}
+
+func names(all []string, cols []int) []string {
+	n := make([]string, len(cols))
+	for i, j := range cols {
+		n[i] = all[j]
+	}
+	return n
+}
+
+// Groups the columns linked by rows, each group listing its columns in order.
+// Columns having no row are left out.
+func components(n int, rows [][]int) [][]int {
+	parent := make([]int, n)
+	for j := range parent {
+		parent[j] = j
+	}
+	var find func(int) int
+	find = func(j int) int {
+		if parent[j] != j {
+			parent[j] = find(parent[j])
+		}
+		return parent[j]
+	}
+	used := make([]bool, n)
+	for _, r := range rows {
+		for _, j := range r {
+			parent[find(j)] = find(r[0])
+			used[j] = true
+		}
+	}
+	groups := make([][]int, 0)
+	group := map[int]int{}
+	for j := 0; j < n; j++ {
+		if !used[j] {
+			continue
+		}
+		root := find(j)
+		g, ok := group[root]
+		if !ok {
+			g = len(groups)
+			group[root] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], j)
+	}
+	return groups
+}
@@ -0,0 +1,57 @@
+package cover
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	spec := NewSpec([][]int{
+		{1, 1, 0, 0, 1, 0},
+		{1, 1, 0, 0, 1, 0},
+		{1, 0, 0, 0, 1, 0},
+		{0, 0, 1, 1, 0, 0},
+	}, []string{"A", "B", "C", "D", "E", "F"})
+	found := map[string]bool{}
+	for _, d := range Lint(spec) {
+		found[fmt.Sprint(d.Issue, d.Cols, d.Rows)] = true
+	}
+	for _, want := range []string{
+		"empty column [F] []",
+		"subsumed row [A B E] [1 0]",
+		"subsumed row [A E] [2 0]",
+		"twin columns [A E] []",
+		"twin columns [C D] []",
+		"components [A B E] []",
+		"components [C D] []",
+	} {
+		if !found[want] {
+			t.Errorf("Lint misses %v in %v", want, found)
+		}
+	}
+	if len(found) != 7 {
+		t.Errorf("Lint reports %v issues (wants %v)", len(found), 7)
+	}
+	// A D is in A D G, and C F always go together
+	diags := Lint(NewSpec(knuth(), []string{"A", "B", "C", "D", "E", "F", "G"}))
+	if len(diags) != 2 || diags[0].Issue != SubsumedRow || diags[1].Issue != TwinColumns {
+		t.Errorf("Knuth example lints as %v", diags)
+	}
+}
+
+func TestLintColors(t *testing.T) {
+	// x is not universal since its colors differ, and {b, x:g} is not in
+	// {b, x:r}
+	if diags := Lint(coloredSpec(t)); len(diags) != 0 {
+		t.Errorf("Colored spec lints as %v", diags)
+	}
+	spec := coloredSpec(t)
+	spec.Options = append(spec.Options, OptionSpec{Items: []string{"a", "b", "x:g"}})
+	found := map[string]bool{}
+	for _, d := range Lint(spec) {
+		found[fmt.Sprint(d.Issue, d.Cols, d.Rows)] = true
+	}
+	if !found["subsumed row [b x:g] [2 3]"] || len(found) != 1 {
+		t.Errorf("Lint reports %v (wants row 2 subsumed by row 3)", found)
+	}
+}
@@ -0,0 +1,25 @@
+package cover
+
+import (
+	"testing"
+)
+
+func TestLookahead(t *testing.T) {
+	for seed := int64(0); seed < 4; seed++ {
+		matrix, headers, _ := GeneratePlantedCover(16, 40, 0.2, seed)
+		plain := NewSolver(matrix, headers)
+		count := plain.Count()
+		s := NewSolver(matrix, headers, WithLookahead(4))
+		if n := s.Count(); n.Cmp(count) != 0 {
+			t.Errorf("Seed %v: lookahead counts %v solutions (wants %v)", seed, n, count)
+		}
+		a, b := s.Stats(), plain.Stats()
+		if a.Candidates() > b.Candidates() {
+			t.Errorf("Seed %v: lookahead tries %v rows, more than %v", seed, a.Candidates(), b.Candidates())
+		}
+	}
+	s := NewSolver(knuth(), []string{"A", "B", "C", "D", "E", "F", "G"}, WithLookahead(7))
+	if O := s.Solve(); len(O.Rows()) != 3 {
+		t.Errorf("Lookahead solution is %v", O.Rows())
+	}
+}
@@ -0,0 +1,56 @@
+package cover
+
+// Searches at most k pairwise disjoint rows covering as many primary columns
+// as possible, for planning with limited resources. Each search node branches
+// on the rows of a column and on leaving the column uncovered, and is cut
+// when even k-len(O) rows of the largest size could not beat the best
+// selection found. The matrix is restored afterwards. Returns the rows and
+// the number of primary columns they cover.
+func (s *Solver) SolveMaxCoverage(k int) (*Solution, int) {
+	m := s.matrix
+	secondary := m.secondaryCols()
+	// number of primary columns of each row
+	width := map[*Node]int{}
+	widest := 0
+	root := m.Root()
+	for col := root.Right; col != root; col = col.Right {
+		for r := col.Down; r != col; r = r.Down {
+			w := 0
+			for _, n := range r.RowNodes() {
+				if !secondary[n.Col] {
+					w++
+				}
+			}
+			width[r] = w
+			if w > widest {
+				widest = w
+			}
+		}
+	}
+	best := &incumbent{solution: Solution{}}
+	O := m.NewSolution()
+	m.begin(O)
+	m.maxCoverage(O, k, width, widest, 0, best)
+	return &best.solution, int(best.cost)
+}
+
+func (m *SparseMatrix) maxCoverage(O *Solution, k int, width map[*Node]int, widest, covered int, best *incumbent) {
+	if covered > int(best.cost) {
+		best.solution = append(Solution{}, *O...)
+		best.cost = float64(covered)
+	}
+	left := m.ColCount()
+	if len(*O) == k || left == 0 {
+		return
+	}
+	if bound := (k - len(*O)) * widest; covered+min(bound, left) <= int(best.cost) {
+		return
+	}
+	m.descend(O, len(*O), descent{partial: true, visit: func(r *Node) bool {
+		m.maxCoverage(O, k, width, widest, covered+width[r], best)
+		return false
+	}, leave: func(*Node) bool {
+		m.maxCoverage(O, k, width, widest, covered, best)
+		return false
+	}})
+}
@@ -0,0 +1,26 @@
+package cover
+
+import (
+	"testing"
+)
+
+func TestSolveMaxCoverage(t *testing.T) {
+	h := []string{"A", "B", "C", "D", "E", "F", "G"}
+	solver := NewSolver(knuth(), h)
+	// C E F or B C F, then A D G
+	for k, want := range []int{0, 3, 6, 7, 7} {
+		O, covered := solver.SolveMaxCoverage(k)
+		if covered != want || O.Len() > k {
+			t.Errorf("%v rows cover %v columns with %v (wants %v)", k, covered, O, want)
+		}
+	}
+	if cols := solver.matrix.ColCount(); cols != len(h) {
+		t.Errorf("Matrix has %v columns after search (wants %v)", cols, len(h))
+	}
+	for seed := int64(0); seed < 5; seed++ {
+		matrix, headers, planted := GeneratePlantedCover(16, 60, 0.2, seed)
+		if _, covered := NewSolver(matrix, headers).SolveMaxCoverage(len(planted)); covered != 16 {
+			t.Errorf("Seed %v: %v rows cover %v columns (wants %v)", seed, len(planted), covered, 16)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package cover
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// Error returned when a matrix would need more nodes than allowed.
+type BudgetError struct {
+	Nodes, Budget int
+}
+
+func (e *BudgetError) Error() string {
+	return fmt.Sprintf("matrix needs %v nodes, budget is %v", e.Nodes, e.Budget)
+}
+
+// Counts the nodes needed to build a matrix from the given rows, headers included.
+func CountNodes(rows [][]int, headers []string) int {
+	n := len(headers) + 1
+	for _, row := range rows {
+		n += len(row)
+	}
+	return n
+}
+
+// Same as NewSparseMatrixFromRows(), but fails with a *BudgetError rather than
+// building a matrix of more than budget nodes, so that services can reject
+// pathological requests before running out of memory.
+func NewSparseMatrixWithBudget(rows [][]int, headers []string, budget int) (*SparseMatrix, error) {
+	if n := CountNodes(rows, headers); n > budget {
+		return nil, &BudgetError{Nodes: n, Budget: budget}
+	}
+	return NewSparseMatrixFromRows(rows, headers), nil
+}
+
+// Estimates the memory used by the matrix in bytes: the nodes, the header
+// names and the size index when tracked.
+func (m *SparseMatrix) MemoryFootprint() uintptr {
+	size := unsafe.Sizeof(*m)
+	size += uintptr(m.nodes+m.cols+1) * unsafe.Sizeof(Node{})
+	root := m.Root()
+	for col := root.Right; col != root; col = col.Right {
+		size += uintptr(len(col.Name))
+	}
+	if m.sizes != nil {
+		size += uintptr(m.cols) * unsafe.Sizeof(bucket{})
+		size += uintptr(len(m.sizes.heads)) * unsafe.Sizeof(root)
+	}
+	return size
+}
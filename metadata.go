@@ -0,0 +1,95 @@
+package cover
+
+import (
+	"log/slog"
+	"sort"
+)
+
+// Names the problem of the matrix, which the statistics, the logs, the
+// profile labels, the exported solutions and the errors of the following
+// searches carry, so that pipelines solving many instances can tell which
+// one produced which output.
+func (m *SparseMatrix) SetName(name string) {
+	m.name = name
+}
+
+// Tags the problem of the matrix with metadata carried along its name, see
+// SetName(). An empty value removes the tag.
+func (m *SparseMatrix) SetTag(key, value string) {
+	if value == "" {
+		delete(m.tags, key)
+		return
+	}
+	if m.tags == nil {
+		m.tags = map[string]string{}
+	}
+	m.tags[key] = value
+}
+
+// Returns the name of the problem, empty if unnamed.
+func (m *SparseMatrix) Name() string {
+	return m.name
+}
+
+// Returns a copy of the tags of the problem, nil if there is none.
+func (m *SparseMatrix) Tags() map[string]string {
+	if len(m.tags) == 0 {
+		return nil
+	}
+	tags := make(map[string]string, len(m.tags))
+	for k, v := range m.tags {
+		tags[k] = v
+	}
+	return tags
+}
+
+// Returns the tags as sorted key and value pairs.
+func (m *SparseMatrix) tagPairs() []string {
+	keys := make([]string, 0, len(m.tags))
+	for k := range m.tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, 2*len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k, m.tags[k])
+	}
+	return pairs
+}
+
+// Returns the name and tags as log attributes.
+func (m *SparseMatrix) metaAttrs() []interface{} {
+	attrs := []interface{}{}
+	if m.name != "" {
+		attrs = append(attrs, slog.String("problem", m.name))
+	}
+	if len(m.tags) > 0 {
+		pairs := m.tagPairs()
+		tags := make([]interface{}, 0, len(pairs)/2)
+		for i := 0; i < len(pairs); i += 2 {
+			tags = append(tags, slog.String(pairs[i], pairs[i+1]))
+		}
+		attrs = append(attrs, slog.Group("tags", tags...))
+	}
+	return attrs
+}
+
+// Names the problem, see SparseMatrix.SetName().
+func (s *Solver) SetName(name string) {
+	s.matrix.SetName(name)
+}
+
+// Tags the problem, see SparseMatrix.SetTag().
+func (s *Solver) SetTag(key, value string) {
+	s.matrix.SetTag(key, value)
+}
+
+// Returns the name of the problem, empty if unnamed.
+func (s *Solver) Name() string {
+	return s.matrix.name
+}
+
+// Returns a copy of the tags of the problem, nil if there is none.
+func (s *Solver) Tags() map[string]string {
+	return s.matrix.Tags()
+}
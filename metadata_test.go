@@ -0,0 +1,46 @@
+package cover
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestMetadata(t *testing.T) {
+	var logs bytes.Buffer
+	s := NewSolver(knuth(), []string{"A", "B", "C", "D", "E", "F", "G"},
+		WithName("knuth"), WithTag("tenant", "test"), WithTag("batch", "7"),
+		WithLogger(slog.New(slog.NewJSONHandler(&logs, nil))))
+	var out bytes.Buffer
+	if _, err := s.WriteSolutions(&out, JSON); err != nil {
+		t.Fatal(err)
+	}
+	var rec SolutionRecord
+	if err := json.Unmarshal(out.Bytes(), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Problem != "knuth" || rec.Tags["tenant"] != "test" || rec.Tags["batch"] != "7" {
+		t.Errorf("Record is %+v", rec)
+	}
+	stats := s.Stats()
+	if stats.Problem != "knuth" || len(stats.Tags) != 2 || !strings.HasPrefix(stats.String(), "knuth\n") {
+		t.Errorf("Stats are %+v", stats)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		if !strings.Contains(line, `"problem":"knuth","tags":{"batch":"7","tenant":"test"}`) {
+			t.Errorf("Log record misses the metadata: %v", line)
+		}
+	}
+
+	s.SetTag("batch", "")
+	if tags := s.Tags(); len(tags) != 1 || tags["tenant"] != "test" {
+		t.Errorf("Tags are %v after removal", tags)
+	}
+	s = NewSolver([][]int{{1, 0}}, []string{"A", "B"}, WithName("broken"))
+	if _, err := s.SolveContext(context.Background()); err == nil || !strings.HasPrefix(err.Error(), "broken (1x2): ") {
+		t.Errorf("Unsolvable solve fails with %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package cover
+
+import (
+	"math"
+)
+
+// Best solution met so far by the min-cost search.
+type incumbent struct {
+	solution Solution
+	cost     float64
+}
+
+// Searches a solution of minimal total cost, costs being indexed by row and
+// non negative. Branches already costing as much as the best solution found
+// are cut. With bound set, each search node also computes a lower bound on the
+// cost of covering the remaining columns, which cuts far more branches on set
+// partitioning problems. The matrix is restored afterwards. Returns an empty
+// solution and an infinite cost if there is no solution. An aborted search,
+// e.g. by a node limit, returns the best solution found so far.
+func (s *Solver) SolveMinCost(costs []float64, bound bool) (*Solution, float64) {
+	m := s.matrix
+	best := &incumbent{solution: Solution{}, cost: math.Inf(1)}
+	O := m.NewSolution()
+	m.begin(O)
+	m.minCost(O, costs, m.shares(costs), 0, bound, best)
+	return &best.solution, best.cost
+}
+
+// Spreads the value of each row over its primary columns, so that the values
+// of the rows covering the columns sum up their shares.
+func (m *SparseMatrix) shares(values []float64) []float64 {
+	share := make([]float64, len(values))
+	root := m.Root()
+	for col := root.Right; col != root; col = col.Right {
+		for _, r := range col.ColNodes() {
+			share[r.Row]++
+		}
+	}
+	for i, n := range share {
+		if n > 0 {
+			share[i] = values[i] / n
+		}
+	}
+	return share
+}
+
+// Returns a lower bound on the cost of covering the remaining columns: each
+// column is covered by a row paying at least the smallest share of the rows
+// intersecting it. This is a feasible solution to the dual of the LP relaxation.
+// The bound is infinite when a column cannot be covered anymore.
+func (m *SparseMatrix) lowerBound(share []float64) float64 {
+	sum := 0.
+	root := m.Root()
+	for col := root.Right; col != root; col = col.Right {
+		min := math.Inf(1)
+		for r := col.Down; r != col; r = r.Down {
+			min = math.Min(min, share[r.Row])
+		}
+		sum += min
+	}
+	return sum
+}
+
+// Returns an upper bound on the value of covering the remaining columns, the
+// counterpart of lowerBound(). It is -Inf when a column cannot be covered
+// anymore.
+func (m *SparseMatrix) upperBound(share []float64) float64 {
+	sum := 0.
+	root := m.Root()
+	for col := root.Right; col != root; col = col.Right {
+		max := math.Inf(-1)
+		for r := col.Down; r != col; r = r.Down {
+			max = math.Max(max, share[r.Row])
+		}
+		sum += max
+	}
+	return sum
+}
+
+func (m *SparseMatrix) minCost(O *Solution, costs, share []float64, cost float64, bound bool, best *incumbent) {
+	root := m.Root()
+	if root.Right == root {
+		if cost < best.cost {
+			best.solution = append(Solution{}, *O...)
+			best.cost = cost
+		}
+		return
+	}
+	if cost >= best.cost || bound && cost+m.lowerBound(share) >= best.cost {
+		return
+	}
+	m.descend(O, len(*O), descent{visit: func(r *Node) bool {
+		m.minCost(O, costs, share, cost+costs[r.Row], bound, best)
+		return false
+	}})
+}
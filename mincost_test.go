@@ -0,0 +1,36 @@
+package cover
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSolveMinCost(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		matrix, headers, _ := GeneratePlantedCover(16, 80, 0.2, seed)
+		rnd := rand.New(rand.NewSource(seed))
+		costs := make([]float64, len(matrix))
+		for i := range costs {
+			costs[i] = float64(rnd.Intn(10))
+		}
+		solver := NewSolver(matrix, headers)
+		min := math.Inf(1)
+		for _, O := range solver.SolveAll() {
+			cost := 0.
+			for _, r := range *O {
+				cost += costs[r.Row]
+			}
+			min = math.Min(min, cost)
+		}
+		for _, bound := range []bool{false, true} {
+			O, cost := solver.SolveMinCost(costs, bound)
+			if cost != min || O.Len() == 0 {
+				t.Errorf("Seed %v, bound %v: min cost is %v with %v (wants %v)", seed, bound, cost, O, min)
+			}
+		}
+		if cols := solver.matrix.ColCount(); cols != len(headers) {
+			t.Errorf("Matrix has %v columns after search (wants %v)", cols, len(headers))
+		}
+	}
+}
@@ -0,0 +1,61 @@
+//go:build unix
+
+package cover
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// Array matrix whose nodes live in a memory mapped file rather than on the
+// heap, so that problems having more nodes than fit in memory can still be
+// searched, the system paging the nodes in and out. Slowly, but correctly.
+type MappedMatrix struct {
+	*ArrayMatrix
+	file *os.File
+	data []byte
+}
+
+// Builds a matrix mapped to the file at path, created or truncated, from the
+// rows given by each. Like for the problems builders, each row lists the
+// indexes of its columns. Since the rows may not fit in memory either, each
+// generates them, and is called twice: to count the nodes, then to link them.
+func NewMappedMatrix(path string, each func(yield func([]int)), headers []string) (*MappedMatrix, error) {
+	n := len(headers) + 1
+	each(func(r []int) {
+		n += len(r)
+	})
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	// 6 slices of n int32
+	size := 6 * 4 * n
+	if err := f.Truncate(int64(size)); err != nil {
+		f.Close()
+		return nil, err
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	offset := 0
+	alloc := func(n int) []int32 {
+		s := unsafe.Slice((*int32)(unsafe.Pointer(&data[offset])), n)
+		offset += 4 * n
+		return s
+	}
+	return &MappedMatrix{ArrayMatrix: newArrayMatrix(each, headers, alloc), file: f, data: data}, nil
+}
+
+// Unmaps and closes the file. The matrix must not be used afterwards.
+func (m *MappedMatrix) Close() error {
+	m.ArrayMatrix = nil
+	if err := syscall.Munmap(m.data); err != nil {
+		m.file.Close()
+		return err
+	}
+	return m.file.Close()
+}
@@ -0,0 +1,26 @@
+//go:build unix
+
+package cover
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestMappedMatrix(t *testing.T) {
+	m, h := GraecoLatinConstraintMatrix(3)
+	rows := SparseRows(m)
+	each := func(yield func([]int)) {
+		for _, r := range rows {
+			yield(r)
+		}
+	}
+	mapped, err := NewMappedMatrix(filepath.Join(t.TempDir(), "nodes"), each, h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mapped.Close()
+	if count := mapped.Count(); count.Int64() != 72 {
+		t.Errorf("Mapped matrix has %v solutions (wants %v)", count, 72)
+	}
+}
@@ -0,0 +1,64 @@
+package cover
+
+// Searches a solution differing from a previous one in at most maxChanges
+// rows, i.e. holding at most that many rows the previous one does not, e.g.
+// to re-plan a schedule after a small change of its matrix. Rows are matched
+// by index, so the previous solution may come from another build of the
+// matrix. The allowed changes grow from zero, which returns a solution of
+// minimal disruption, and the rows of the previous solution are tried first.
+// The matrix is restored afterwards. Returns an empty solution and -1 if there
+// is none within the changes.
+func (s *Solver) SolveNear(previous Solution, maxChanges int) (*Solution, int) {
+	m := s.matrix
+	kept := map[int]bool{}
+	for _, r := range previous {
+		kept[r.Row] = true
+	}
+	O := m.NewSolution()
+	m.begin(O)
+	for changes := 0; changes <= maxChanges && !m.aborted; changes++ {
+		if m.near(O, kept, changes) {
+			return O, s.changes(O, kept)
+		}
+	}
+	return &Solution{}, -1
+}
+
+// Counts the rows of the solution not kept.
+func (s *Solver) changes(O *Solution, kept map[int]bool) int {
+	n := 0
+	for _, r := range *O {
+		if !kept[r.Row] {
+			n++
+		}
+	}
+	return n
+}
+
+// Searches a solution holding at most changes rows not kept, leaving it in O.
+// The kept rows are tried first, then the others while changes remain, both
+// on the same column.
+func (m *SparseMatrix) near(O *Solution, kept map[int]bool, changes int) bool {
+	root := m.Root()
+	if root.Right == root {
+		return true
+	}
+	m.level = len(*O)
+	c := m.SmallestCol()
+	if c == nil {
+		return false
+	}
+	found := m.descend(O, len(*O), descent{col: c, keep: func(r *Node) bool {
+		return kept[r.Row]
+	}, visit: func(*Node) bool {
+		return m.near(O, kept, changes)
+	}})
+	if found || changes == 0 {
+		return found
+	}
+	return m.descend(O, len(*O), descent{col: c, keep: func(r *Node) bool {
+		return !kept[r.Row]
+	}, visit: func(*Node) bool {
+		return m.near(O, kept, changes-1)
+	}})
+}
@@ -0,0 +1,51 @@
+package cover
+
+import (
+	"testing"
+)
+
+func TestSolveNear(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		matrix, headers, _ := GeneratePlantedCover(16, 40, 0.2, seed)
+		s := NewSolver(matrix, headers)
+		if seed%2 == 1 {
+			// the size buckets reorder the ties on uncovering
+			s.matrix.TrackSizes()
+		}
+		previous := append(Solution{}, *s.Solve()...)
+		s.Reset()
+		O, changes := s.SolveNear(previous, 0)
+		if changes != 0 || O.Len() != previous.Len() {
+			t.Errorf("Seed %v: unchanged matrix gives %v with %v changes (wants the previous solution)", seed, O, changes)
+		}
+		// forbidding two rows of the solution forces changes
+		s.matrix.Forbid(previous[0], previous[1])
+		kept := map[int]bool{}
+		for _, r := range previous {
+			kept[r.Row] = true
+		}
+		want := -1
+		for _, O := range s.SolveAll() {
+			if n := s.changes(O, kept); want < 0 || n < want {
+				want = n
+			}
+		}
+		O, changes = s.SolveNear(previous, len(headers))
+		if changes != want {
+			t.Errorf("Seed %v: %v changes (wants %v)", seed, changes, want)
+		}
+		if want < 0 {
+			continue
+		}
+		rows := make([]int, O.Len())
+		for i, r := range *O {
+			rows[i] = r.Row
+		}
+		if err := s.CheckRows(rows); err != nil {
+			t.Errorf("Seed %v: near solution %v: %v", seed, O, err)
+		}
+		if _, changes := s.SolveNear(previous, want-1); changes != -1 {
+			t.Errorf("Seed %v: %v changes found within %v", seed, changes, want-1)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package cover
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Sets of rows known to be in no solution, learnt from the subtrees the search
+// exhausted without finding one. The rows chosen along a path leave the same
+// subproblem whatever their order, so a search meeting the same rows again,
+// possibly with others, can skip the subtree. This pays off when the search
+// explores the same region several times, like restarted searches or workers
+// sharing the problem, as clause learning does in SAT solvers.
+// Only sets of up to max rows are kept, the smaller ones pruning more.
+// Rows are identified by index, so the nogoods only apply to matrices of the
+// same problem. They are safe for concurrent use.
+type Nogoods struct {
+	max  int
+	mu   sync.RWMutex
+	sets map[string]bool
+	// nogoods holding each row
+	byRow map[int][][]int
+}
+
+// Creates an empty store of nogoods of up to max rows.
+func NewNogoods(max int) *Nogoods {
+	return &Nogoods{max: max, sets: map[string]bool{}, byRow: map[int][][]int{}}
+}
+
+// Returns the number of nogoods recorded.
+func (n *Nogoods) Len() int {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return len(n.sets)
+}
+
+// Returns the key of a set of rows.
+func nogoodKey(rows []int) string {
+	sort.Ints(rows)
+	key := make([]string, len(rows))
+	for i, r := range rows {
+		key[i] = strconv.Itoa(r)
+	}
+	return strings.Join(key, ",")
+}
+
+// Records the rows of the solution up to level k as a nogood, if small enough.
+func (n *Nogoods) record(O *Solution, k int) {
+	if n == nil || k >= n.max {
+		return
+	}
+	rows := make([]int, k+1)
+	for i, r := range (*O)[:k+1] {
+		rows[i] = r.Row
+	}
+	key := nogoodKey(rows)
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.sets[key] {
+		return
+	}
+	n.sets[key] = true
+	for _, r := range rows {
+		n.byRow[r] = append(n.byRow[r], rows)
+	}
+}
+
+// Tells whether the rows of the solution up to level k hold a nogood. Since
+// the ones without the row of level k were checked at the upper levels, only
+// the nogoods holding it are looked up.
+func (n *Nogoods) prunes(O *Solution, k int) bool {
+	if n == nil {
+		return false
+	}
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	for _, rows := range n.byRow[O.Get(k).Row] {
+		held := 0
+		for _, r := range rows {
+			for i := 0; i <= k; i++ {
+				if O.Get(i).Row == r {
+					held++
+					break
+				}
+			}
+		}
+		if held == len(rows) {
+			return true
+		}
+	}
+	return false
+}
+
+// Records and uses nogoods during the following searches. The store may be
+// shared by the matrices of other solvers of the same problem.
+func (m *SparseMatrix) UseNogoods(n *Nogoods) {
+	m.nogoods = n
+}
@@ -0,0 +1,26 @@
+package cover
+
+import (
+	"testing"
+)
+
+func TestNogoods(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		matrix, headers, _ := GeneratePlantedCover(20, 80, 0.15, seed)
+		want := NewSolver(matrix, headers).Count()
+		nogoods := NewNogoods(3)
+		first := NewSolver(matrix, headers, WithNogoods(nogoods))
+		if n := first.Count(); n.Cmp(want) != 0 {
+			t.Errorf("Seed %v: %v solutions with nogoods (wants %v)", seed, n, want)
+		}
+		// a second solver prunes the subtrees the first one exhausted
+		second := NewSolver(matrix, headers, WithNogoods(nogoods))
+		if n := second.Count(); n.Cmp(want) != 0 {
+			t.Errorf("Seed %v: %v solutions with shared nogoods (wants %v)", seed, n, want)
+		}
+		a, b := first.Stats(), second.Stats()
+		if nogoods.Len() > 0 && b.Candidates() >= a.Candidates() {
+			t.Errorf("Seed %v: shared nogoods do not prune, %v candidates (first %v)", seed, b.Candidates(), a.Candidates())
+		}
+	}
+}
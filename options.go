@@ -0,0 +1,182 @@
+package cover
+
+import (
+	"io"
+	"log/slog"
+	"math/rand"
+	"time"
+)
+
+// Configures a solver when created.
+type Option func(*Solver)
+
+// Groups the columns by size for a constant time smallest column lookup,
+// see TrackSizes().
+func WithSizeTracking() Option {
+	return func(s *Solver) {
+		s.matrix.TrackSizes()
+	}
+}
+
+// Includes forced rows before branching, see PropagateUnits().
+func WithUnitPropagation() Option {
+	return func(s *Solver) {
+		s.matrix.PropagateUnits()
+	}
+}
+
+// Branches on the column with the fewest viable rows among the width smallest
+// ones, see Lookahead().
+func WithLookahead(width int) Option {
+	return func(s *Solver) {
+		s.matrix.Lookahead(width)
+	}
+}
+
+// Reorders the columns before the search, see OrderColumns().
+func WithColumnOrder(o ColumnOrder) Option {
+	return func(s *Solver) {
+		s.matrix.OrderColumns(o)
+	}
+}
+
+// Reorders the rows of each column before the search, see OrderRows().
+func WithRowOrder(less func(a, b *Node) bool) Option {
+	return func(s *Solver) {
+		s.matrix.OrderRows(less)
+	}
+}
+
+// Shuffles the rows of each column before the search, see ShuffleRows().
+func WithRandomRows(seed int64) Option {
+	return func(s *Solver) {
+		s.matrix.ShuffleRows(rand.New(rand.NewSource(seed)))
+	}
+}
+
+// Registers a hook fired on every cover and uncover, see AddHook().
+func WithHook(h Hook) Option {
+	return func(s *Solver) {
+		s.matrix.AddHook(h)
+	}
+}
+
+// Tags the searches with pprof labels, see SetLabels().
+func WithLabels(problem string, labels ...string) Option {
+	return func(s *Solver) {
+		s.matrix.SetLabels(problem, labels...)
+	}
+}
+
+// Registers the decoder turning rows into domain objects, see SetDecoder().
+func WithDecoder(d Decoder) Option {
+	return func(s *Solver) {
+		s.SetDecoder(d)
+	}
+}
+
+// Reports the progress of the searches, see OnProgress().
+func WithProgress(every time.Duration, f func(Progress) bool) Option {
+	return func(s *Solver) {
+		s.matrix.OnProgress(every, f)
+	}
+}
+
+// Records and uses nogoods, see UseNogoods().
+func WithNogoods(n *Nogoods) Option {
+	return func(s *Solver) {
+		s.matrix.UseNogoods(n)
+	}
+}
+
+// Makes Solve() use the SAT solver, or race it against the dancing links,
+// see SolveSAT() and SolveRace(). A nil solver stands for DPLL.
+func WithSAT(sat SATSolver, b Backend) Option {
+	return func(s *Solver) {
+		if sat == nil {
+			sat = DPLL{}
+		}
+		s.sat, s.backend = sat, b
+	}
+}
+
+// Searches only the rows for which pred returns true, see FilterRows().
+func WithOnlyRows(pred func(row int) bool) Option {
+	return func(s *Solver) {
+		s.matrix.FilterRows(pred)
+	}
+}
+
+// Searches without the rows of the given indexes, see FilterRows().
+func WithForbiddenRows(ids ...int) Option {
+	forbidden := map[int]bool{}
+	for _, id := range ids {
+		forbidden[id] = true
+	}
+	return WithOnlyRows(func(row int) bool {
+		return !forbidden[row]
+	})
+}
+
+// Aborts the searches after n rows tried, see SetNodeLimit().
+func WithNodeLimit(n int64) Option {
+	return func(s *Solver) {
+		s.matrix.SetNodeLimit(n)
+	}
+}
+
+// Aborts the searches reaching the depth d, see SetDepthLimit().
+func WithDepthLimit(d int) Option {
+	return func(s *Solver) {
+		s.matrix.SetDepthLimit(d)
+	}
+}
+
+// Checks the matrix after every uncover, see SelfCheck().
+func WithSelfCheck() Option {
+	return func(s *Solver) {
+		s.matrix.SelfCheck(true)
+	}
+}
+
+// Traces the searches to w with lines starting with the prefix, see Trace().
+func WithTrace(w io.Writer, prefix string) Option {
+	return func(s *Solver) {
+		s.matrix.Trace(w, prefix)
+	}
+}
+
+// Journals the decisions of the searches to w, see Journal().
+func WithJournal(w io.Writer) Option {
+	return func(s *Solver) {
+		s.matrix.Journal(w)
+	}
+}
+
+// Makes the searches visit the same nodes on every run, see SetDeterministic().
+func WithDeterministic() Option {
+	return func(s *Solver) {
+		s.matrix.SetDeterministic(true)
+	}
+}
+
+// Names the problem, see SetName().
+func WithName(name string) Option {
+	return func(s *Solver) {
+		s.matrix.SetName(name)
+	}
+}
+
+// Tags the problem, see SetTag().
+func WithTag(key, value string) Option {
+	return func(s *Solver) {
+		s.matrix.SetTag(key, value)
+	}
+}
+
+// Logs the searches as structured records, see SetLogger().
+func WithLogger(l *slog.Logger) Option {
+	return func(s *Solver) {
+		s.matrix.SetLogger(l)
+	}
+}
@@ -0,0 +1,121 @@
+package cover
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// Ordering of the columns in the headers. Even with the smallest column
+// heuristic, it decides the ties and how the nodes are visited.
+type ColumnOrder int
+
+const (
+	// order given at build time
+	AsBuilt ColumnOrder = iota
+	// fewest rows first
+	BySize
+	// columns sharing rows with the most other columns first
+	ByConnectivity
+)
+
+// Returns the number of distinct columns sharing at least one row with each column.
+func (m *SparseMatrix) connectivity() map[*Node]int {
+	conn := map[*Node]int{}
+	root := m.Root()
+	for col := root.Right; col != root; col = col.Right {
+		neighbours := map[*Node]bool{}
+		col.ForEachInCol(func(r *Node) {
+			for j := r.Right; j != r; j = j.Right {
+				neighbours[j.Col] = true
+			}
+		})
+		conn[col] = len(neighbours)
+	}
+	return conn
+}
+
+// Relinks the remaining columns in the given order, ties keeping their
+// current order. Only meant to be called before the search.
+func (m *SparseMatrix) OrderColumns(o ColumnOrder) {
+	root := m.Root()
+	cols := make([]*Node, 0)
+	for col := root.Right; col != root; col = col.Right {
+		cols = append(cols, col)
+	}
+	switch o {
+	case AsBuilt:
+		return
+	case BySize:
+		sort.SliceStable(cols, func(i, j int) bool {
+			return cols[i].Size < cols[j].Size
+		})
+	case ByConnectivity:
+		conn := m.connectivity()
+		sort.SliceStable(cols, func(i, j int) bool {
+			return conn[cols[i]] > conn[cols[j]]
+		})
+	}
+	root.Left = root
+	root.Right = root
+	for _, col := range cols {
+		root.RowAppend(col)
+	}
+	// buckets break ties in header order
+	if m.sizes != nil {
+		m.TrackSizes()
+	}
+}
+
+// Relinks the nodes of the column in the given order.
+func relinkCol(col *Node, nodes []*Node) {
+	col.Up = col
+	col.Down = col
+	for _, n := range nodes {
+		n.Down = col
+		n.Up = col.Up
+		col.Up.Down = n
+		col.Up = n
+	}
+}
+
+// Relinks the rows of every remaining column so that the search tries them in
+// the given order, ties keeping their current order. Only meant to be called
+// before the search.
+func (m *SparseMatrix) OrderRows(less func(a, b *Node) bool) {
+	root := m.Root()
+	for col := root.Right; col != root; col = col.Right {
+		nodes := col.ColNodes()
+		sort.SliceStable(nodes, func(i, j int) bool {
+			return less(nodes[i], nodes[j])
+		})
+		relinkCol(col, nodes)
+	}
+}
+
+// Relinks the rows of every remaining column in a random order, unless the
+// searches are deterministic.
+func (m *SparseMatrix) ShuffleRows(rnd *rand.Rand) {
+	if m.deterministic {
+		return
+	}
+	root := m.Root()
+	for col := root.Right; col != root; col = col.Right {
+		nodes := col.ColNodes()
+		rnd.Shuffle(len(nodes), func(i, j int) {
+			nodes[i], nodes[j] = nodes[j], nodes[i]
+		})
+		relinkCol(col, nodes)
+	}
+}
+
+// Orders the rows as built.
+func ByRowIndex(a, b *Node) bool {
+	return a.Row < b.Row
+}
+
+// Returns an order trying the cheapest rows first, costs being indexed by row.
+func ByRowCost(costs []float64) func(a, b *Node) bool {
+	return func(a, b *Node) bool {
+		return costs[a.Row] < costs[b.Row]
+	}
+}
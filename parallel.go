@@ -0,0 +1,95 @@
+package cover
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Part of a column built by a single worker.
+type chain struct {
+	first, last *Node
+	size        uint
+}
+
+// Same as NewSparseMatrixFromRows(), but splits the rows in chunks built by
+// concurrent workers. Each worker links its own rows and column chains, which
+// are stitched together at the end. A non positive count uses all the CPUs.
+func NewSparseMatrixParallel(rows [][]int, headers []string, workers int) *SparseMatrix {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	root := &Node{Meta: Meta{Name: "root"}}
+	root.Left = root
+	root.Right = root
+	cols := make([]*Node, len(headers))
+	for i, h := range headers {
+		cols[i] = NewColNode(h)
+		root.RowAppend(cols[i])
+	}
+	chunk := (len(rows) + workers - 1) / workers
+	chains := make([][]chain, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		lo, hi := w*chunk, (w+1)*chunk
+		if hi > len(rows) {
+			hi = len(rows)
+		}
+		if lo >= hi {
+			break
+		}
+		chains[w] = make([]chain, len(headers))
+		wg.Add(1)
+		go func(offset int, part [][]int, ch []chain) {
+			defer wg.Done()
+			count := 0
+			for _, row := range part {
+				count += len(row)
+			}
+			// a single allocation for all the nodes of the chunk
+			nodes := make([]Node, count)
+			p := 0
+			for i, row := range part {
+				var first *Node
+				for _, j := range row {
+					n := &nodes[p]
+					p++
+					n.Col = cols[j]
+					n.Row = offset + i
+					if first != nil {
+						first.RowAppend(n)
+					} else {
+						n.Left = n
+						n.Right = n
+						first = n
+					}
+					c := &ch[j]
+					if c.last != nil {
+						c.last.Down = n
+						n.Up = c.last
+					} else {
+						c.first = n
+					}
+					c.last = n
+					c.size++
+				}
+			}
+		}(lo, rows[lo:hi], chains[w])
+	}
+	wg.Wait()
+	nodes := 0
+	for j, col := range cols {
+		for _, ch := range chains {
+			if ch == nil || ch[j].first == nil {
+				continue
+			}
+			c := ch[j]
+			col.Up.Down = c.first
+			c.first.Up = col.Up
+			c.last.Down = col
+			col.Up = c.last
+			col.Size += c.size
+		}
+		nodes += int(col.Size)
+	}
+	return &SparseMatrix{Node: root, nodes: nodes, cols: len(headers)}
+}
@@ -0,0 +1,65 @@
+package cover
+
+import (
+	"sort"
+)
+
+// Pareto-optimal solution of two costs: no other solution costs less on one
+// without costing more on the other.
+type ParetoPoint struct {
+	Solution Solution
+	Costs    [2]float64
+}
+
+// Tells whether costs a are at least as good as b on both.
+func dominates(a, b [2]float64) bool {
+	return a[0] <= b[0] && a[1] <= b[1]
+}
+
+// Enumerates the Pareto-optimal solutions of two costs indexed by row, e.g.
+// cost and overtime, sorted by increasing first cost: the trade-off frontier
+// rather than a single optimum. A solution is kept per point of the
+// frontier. A branch is cut when the frontier dominates its costs plus the
+// lower bounds on the remaining ones. The matrix is restored afterwards.
+func (s *Solver) SolvePareto(first, second []float64) []ParetoPoint {
+	m := s.matrix
+	shares := [2][]float64{m.shares(first), m.shares(second)}
+	frontier := make([]ParetoPoint, 0)
+	O := m.NewSolution()
+	m.begin(O)
+	m.pareto(O, [2][]float64{first, second}, shares, [2]float64{}, &frontier)
+	sort.Slice(frontier, func(a, b int) bool {
+		return frontier[a].Costs[0] < frontier[b].Costs[0]
+	})
+	return frontier
+}
+
+func (m *SparseMatrix) pareto(O *Solution, costs, shares [2][]float64, cost [2]float64, frontier *[]ParetoPoint) {
+	root := m.Root()
+	if root.Right == root {
+		for _, p := range *frontier {
+			if dominates(p.Costs, cost) {
+				return
+			}
+		}
+		// drop the points the solution dominates
+		kept := (*frontier)[:0]
+		for _, p := range *frontier {
+			if !dominates(cost, p.Costs) {
+				kept = append(kept, p)
+			}
+		}
+		*frontier = append(kept, ParetoPoint{append(Solution{}, *O...), cost})
+		return
+	}
+	low := [2]float64{cost[0] + m.lowerBound(shares[0]), cost[1] + m.lowerBound(shares[1])}
+	for _, p := range *frontier {
+		if dominates(p.Costs, low) {
+			return
+		}
+	}
+	m.descend(O, len(*O), descent{visit: func(r *Node) bool {
+		m.pareto(O, costs, shares, [2]float64{cost[0] + costs[0][r.Row], cost[1] + costs[1][r.Row]}, frontier)
+		return false
+	}})
+}
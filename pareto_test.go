@@ -0,0 +1,58 @@
+package cover
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSolvePareto(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		matrix, headers, _ := GeneratePlantedCover(16, 80, 0.2, seed)
+		rnd := rand.New(rand.NewSource(seed))
+		costs := [2][]float64{make([]float64, len(matrix)), make([]float64, len(matrix))}
+		for i := range matrix {
+			costs[0][i] = float64(rnd.Intn(10))
+			costs[1][i] = float64(rnd.Intn(10))
+		}
+		solver := NewSolver(matrix, headers)
+		all := make([][2]float64, 0)
+		for _, O := range solver.SolveAll() {
+			var c [2]float64
+			for _, r := range *O {
+				c[0] += costs[0][r.Row]
+				c[1] += costs[1][r.Row]
+			}
+			all = append(all, c)
+		}
+		// points no other solution strictly improves
+		want := map[[2]float64]bool{}
+		for _, a := range all {
+			optimal := true
+			for _, b := range all {
+				if dominates(b, a) && b != a {
+					optimal = false
+				}
+			}
+			if optimal {
+				want[a] = true
+			}
+		}
+		frontier := solver.SolvePareto(costs[0], costs[1])
+		if len(frontier) != len(want) {
+			t.Errorf("Seed %v: frontier has %v points (wants %v)", seed, len(frontier), len(want))
+		}
+		for i, p := range frontier {
+			var c [2]float64
+			for _, r := range p.Solution {
+				c[0] += costs[0][r.Row]
+				c[1] += costs[1][r.Row]
+			}
+			if !want[p.Costs] || c != p.Costs {
+				t.Errorf("Seed %v: point %v costs %v is not optimal", seed, p.Costs, c)
+			}
+			if i > 0 && frontier[i-1].Costs[0] >= p.Costs[0] {
+				t.Errorf("Seed %v: frontier not sorted at %v", seed, i)
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package cover
+
+import (
+	"fmt"
+)
+
+// Creates a solver for the partridge puzzle of order n: pack k squares of
+// size k, for k in 1..n, into a square of side n(n+1)/2, whose area is the sum
+// of theirs. The first order having a solution is 8. Each row places one copy
+// of a square at a given position, see squaresRows().
+func NewPartridgeSolver(n int) *Solver {
+	counts := make([]int, n)
+	for k := 1; k <= n; k++ {
+		counts[k-1] = k
+	}
+	rows, headers := squaresRows(n*(n+1)/2, counts)
+	return &Solver{matrix: NewSparseMatrixFromRows(rows, headers), Solutions: make([]*Solution, 0, 1)}
+}
+
+// Returns the rows packing counts[k-1] squares of size k into a square of the
+// given side, covering each cell once, each row listing the indexes of its
+// columns. The columns are the cells, row by row, then a column per copy of a
+// square, then the ordering columns.
+// Copies of a size are interchangeable, so the copy c+1 must lie after the
+// copy c in row-major order of their top left cells, which the ordering
+// columns enforce: the copy c at x,y takes "s<k>#<c><x'" for x' < x and
+// "s<k>#<c><x,y'" for y' < y, the copy c+1 at x,y takes "s<k>#<c><x" and
+// "s<k>#<c><x,y". A slack row of its own covers an ordering column no copy
+// takes, so that the copies cover it at most once. Each packing is thus one
+// solution.
+func squaresRows(side int, counts []int) (rows [][]int, headers []string) {
+	index := map[string]int{}
+	add := func(name string) {
+		index[name] = len(headers)
+		headers = append(headers, name)
+	}
+	for i := 0; i < side*side; i++ {
+		add(fmt.Sprintf("%v,%v", i/side, i%side))
+	}
+	for k := 1; k <= len(counts); k++ {
+		for c := 0; c < counts[k-1]; c++ {
+			add(fmt.Sprintf("s%v#%v", k, c))
+		}
+	}
+	ordering := len(headers)
+	for k := 1; k <= len(counts); k++ {
+		for c := 0; c+1 < counts[k-1]; c++ {
+			for x := 0; x+k <= side; x++ {
+				add(fmt.Sprintf("s%v#%v<%v", k, c, x))
+				for y := 0; y+k <= side; y++ {
+					add(fmt.Sprintf("s%v#%v<%v,%v", k, c, x, y))
+				}
+			}
+		}
+	}
+	for k := 1; k <= len(counts); k++ {
+		last := side - k
+		for c := 0; c < counts[k-1]; c++ {
+			for x := 0; x <= last; x++ {
+				for y := 0; y <= last; y++ {
+					row := make([]int, 0, k*k+1)
+					for i := x; i < x+k; i++ {
+						for j := y; j < y+k; j++ {
+							row = append(row, i*side+j)
+						}
+					}
+					row = append(row, index[fmt.Sprintf("s%v#%v", k, c)])
+					if c+1 < counts[k-1] {
+						for x2 := 0; x2 < x; x2++ {
+							row = append(row, index[fmt.Sprintf("s%v#%v<%v", k, c, x2)])
+						}
+						for y2 := 0; y2 < y; y2++ {
+							row = append(row, index[fmt.Sprintf("s%v#%v<%v,%v", k, c, x, y2)])
+						}
+					}
+					if c > 0 {
+						row = append(row, index[fmt.Sprintf("s%v#%v<%v", k, c-1, x)])
+						row = append(row, index[fmt.Sprintf("s%v#%v<%v,%v", k, c-1, x, y)])
+					}
+					rows = append(rows, row)
+				}
+			}
+		}
+	}
+	for j := ordering; j < len(headers); j++ {
+		rows = append(rows, []int{j})
+	}
+	return rows, headers
+}
@@ -0,0 +1,66 @@
+package cover
+
+import (
+	"testing"
+)
+
+// Guesser counting the packings of a squares matrix.
+type packings struct {
+	matrix *SparseMatrix
+	count  int64
+}
+
+func (p *packings) ChooseCol(k int) *Node {
+	return p.matrix.SmallestCol()
+}
+func (p *packings) Eureka(O *Solution) {
+	p.count++
+}
+func (p *packings) Terminate() bool {
+	return false
+}
+
+func countPackings(m *SparseMatrix) int64 {
+	p := &packings{matrix: m}
+	m.Search(&Solution{}, 0, p)
+	return p.count
+}
+
+func TestSquaresRows(t *testing.T) {
+	cases := []struct {
+		side   int
+		counts []int
+		want   int64
+	}{
+		// interchangeable copies count once
+		{2, []int{4}, 1},
+		{4, []int{0, 4}, 1},
+		// the 2x2 square anywhere in the 3x3 one
+		{3, []int{5, 1}, 4},
+		{6, []int{0, 0, 4}, 1},
+		// the ways to place four 2x2 squares in a 5x5 one
+		{5, []int{9, 4}, 79},
+	}
+	for _, c := range cases {
+		rows, headers := squaresRows(c.side, c.counts)
+		matrix := make([][]int, len(rows))
+		for i, row := range rows {
+			matrix[i] = make([]int, len(headers))
+			for _, j := range row {
+				matrix[i][j] = 1
+			}
+		}
+		if got := countPackings(NewSparseMatrix(matrix, headers)); got != c.want {
+			t.Errorf("side %v, counts %v: got %v packings, want %v", c.side, c.counts, got, c.want)
+		}
+	}
+}
+
+func TestPartridge(t *testing.T) {
+	// no packing below the order 8
+	for n, want := range []int64{1, 1, 0, 0, 0} {
+		if got := countPackings(NewPartridgeSolver(n).matrix); got != want {
+			t.Errorf("order %v: got %v packings, want %v", n, got, want)
+		}
+	}
+}
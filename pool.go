@@ -0,0 +1,34 @@
+package cover
+
+import (
+	"sync"
+)
+
+// Vends solvers over private copies of the same problem, so that many
+// goroutines can solve it at once. Solvers are reused once put back.
+type SolverPool struct {
+	rows    [][]int
+	headers []string
+	pool    sync.Pool
+}
+
+// Creates a pool of solvers for the given binary matrix.
+func NewSolverPool(m [][]int, h []string) *SolverPool {
+	p := &SolverPool{rows: SparseRows(m), headers: h}
+	p.pool.New = func() interface{} {
+		return &Solver{matrix: NewSparseMatrixFromRows(p.rows, p.headers), Solutions: make([]*Solution, 0, 1)}
+	}
+	return p
+}
+
+// Returns a solver owned by the caller until given back with Put().
+func (p *SolverPool) Get() *Solver {
+	return p.pool.Get().(*Solver)
+}
+
+// Resets the solver and makes it available to other goroutines.
+// Solutions it found must not be used afterwards.
+func (p *SolverPool) Put(s *Solver) {
+	s.Reset()
+	p.pool.Put(s)
+}
@@ -0,0 +1,49 @@
+package cover
+
+import (
+	"math"
+)
+
+// Best solution met so far by the lexicographic search.
+type preferred struct {
+	solution   Solution
+	cost, pref float64
+}
+
+// Searches a solution of minimal total cost, like SolveMinCost(), and among
+// those the one of maximal total preference, both indexed by row: schedulers
+// get the cheapest solution, then the most preferred. A branch is cut when its
+// cost, plus the lower bound on the remaining one when bound is set, exceeds
+// the best cost, or reaches it while an upper bound on its preference does
+// not exceed the best one. The matrix is restored afterwards. Returns an
+// empty solution, an infinite cost and no preference if there is no solution.
+func (s *Solver) SolvePreferred(costs, prefs []float64, bound bool) (*Solution, float64, float64) {
+	m := s.matrix
+	best := &preferred{solution: Solution{}, cost: math.Inf(1)}
+	O := m.NewSolution()
+	m.begin(O)
+	m.preferred(O, costs, prefs, m.shares(costs), m.shares(prefs), 0, 0, bound, best)
+	return &best.solution, best.cost, best.pref
+}
+
+func (m *SparseMatrix) preferred(O *Solution, costs, prefs, costShare, prefShare []float64, cost, pref float64, bound bool, best *preferred) {
+	root := m.Root()
+	if root.Right == root {
+		if cost < best.cost || cost == best.cost && pref > best.pref {
+			best.solution = append(Solution{}, *O...)
+			best.cost, best.pref = cost, pref
+		}
+		return
+	}
+	low := cost
+	if bound {
+		low += m.lowerBound(costShare)
+	}
+	if low > best.cost || low == best.cost && pref+m.upperBound(prefShare) <= best.pref {
+		return
+	}
+	m.descend(O, len(*O), descent{visit: func(r *Node) bool {
+		m.preferred(O, costs, prefs, costShare, prefShare, cost+costs[r.Row], pref+prefs[r.Row], bound, best)
+		return false
+	}})
+}
@@ -0,0 +1,49 @@
+package cover
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSolvePreferred(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		matrix, headers, _ := GeneratePlantedCover(16, 80, 0.2, seed)
+		rnd := rand.New(rand.NewSource(seed))
+		costs, prefs := make([]float64, len(matrix)), make([]float64, len(matrix))
+		for i := range costs {
+			// few distinct costs, so that several solutions tie
+			costs[i] = float64(rnd.Intn(3))
+			prefs[i] = float64(rnd.Intn(10))
+		}
+		solver := NewSolver(matrix, headers)
+		min, max := math.Inf(1), math.Inf(-1)
+		for _, O := range solver.SolveAll() {
+			cost, pref := 0., 0.
+			for _, r := range *O {
+				cost += costs[r.Row]
+				pref += prefs[r.Row]
+			}
+			if cost < min {
+				min, max = cost, pref
+			} else if cost == min {
+				max = math.Max(max, pref)
+			}
+		}
+		for _, bound := range []bool{false, true} {
+			O, cost, pref := solver.SolvePreferred(costs, prefs, bound)
+			if cost != min || pref != max || O.Len() == 0 {
+				t.Errorf("Seed %v, bound %v: best is %v, %v with %v (wants %v, %v)", seed, bound, cost, pref, O, min, max)
+			}
+		}
+		// without costs, the most preferred solution
+		O, _, pref := solver.SolvePreferred(make([]float64, len(matrix)), prefs, true)
+		sum := 0.
+		for _, r := range *O {
+			sum += prefs[r.Row]
+		}
+		if sum != pref || pref < max {
+			t.Errorf("Seed %v: preference %v, sums up to %v (wants at least %v)", seed, pref, sum, max)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package cover
+
+import (
+	"math"
+)
+
+// Best relaxed solution met so far by the prize-collecting search.
+type prizeIncumbent struct {
+	incumbent
+	dropped []string
+}
+
+// Searches the rows minimizing their total cost plus the penalties of the
+// primary columns they leave uncovered, for over-constrained problems where
+// some demands can be dropped at a price. Costs are indexed by row, penalties
+// by column name, and both are non negative. Columns without a penalty must
+// be covered. The search is the branch and bound of SolveMinCost(), with the
+// lower bound of a column being the smallest of its penalty and of the shares
+// of its rows. The matrix is restored afterwards. Returns the rows, the names
+// of the dropped columns and the total cost, which is infinite if the columns
+// without a penalty cannot be covered.
+func (s *Solver) SolveWithPenalties(costs []float64, penalties map[string]float64) (*Solution, []string, float64) {
+	m := s.matrix
+	share := m.shares(costs)
+	best := &prizeIncumbent{incumbent: incumbent{solution: Solution{}, cost: math.Inf(1)}, dropped: []string{}}
+	O := m.NewSolution()
+	m.begin(O)
+	m.prizeCost(O, []string{}, costs, share, penalties, 0, best)
+	return &best.solution, best.dropped, best.cost
+}
+
+// Returns the penalty of the column, infinite if it has none.
+func penalty(penalties map[string]float64, col *Node) float64 {
+	if p, ok := penalties[col.Name]; ok {
+		return p
+	}
+	return math.Inf(1)
+}
+
+func (m *SparseMatrix) prizeCost(O *Solution, dropped []string, costs, share []float64, penalties map[string]float64, cost float64, best *prizeIncumbent) {
+	root := m.Root()
+	if root.Right == root {
+		if cost < best.cost {
+			best.solution = append(Solution{}, *O...)
+			best.dropped = append([]string{}, dropped...)
+			best.cost = cost
+		}
+		return
+	}
+	bound := cost
+	for col := root.Right; col != root; col = col.Right {
+		min := penalty(penalties, col)
+		for r := col.Down; r != col; r = r.Down {
+			min = math.Min(min, share[r.Row])
+		}
+		bound += min
+	}
+	if bound >= best.cost {
+		return
+	}
+	m.descend(O, len(*O), descent{partial: true, visit: func(r *Node) bool {
+		m.prizeCost(O, dropped, costs, share, penalties, cost+costs[r.Row], best)
+		return false
+	}, leave: func(c *Node) bool {
+		if p := penalty(penalties, c); !math.IsInf(p, 1) {
+			m.prizeCost(O, append(dropped, c.Name), costs, share, penalties, cost+p, best)
+		}
+		return false
+	}})
+}
@@ -0,0 +1,44 @@
+package cover
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestSolveWithPenalties(t *testing.T) {
+	h := []string{"A", "B", "C", "D", "E", "F", "G"}
+	costs := []float64{1, 1, 1, 1, 1, 1}
+	solver := NewSolver(knuth(), h)
+	// without penalties, the only cover of 3 rows
+	O, dropped, cost := solver.SolveWithPenalties(costs, nil)
+	if cost != 3 || O.Len() != 3 || len(dropped) != 0 {
+		t.Errorf("No penalty gives cost %v with %v dropping %v (wants 3 rows)", cost, O, dropped)
+	}
+	// dropping every column is cheaper than any row
+	all := map[string]float64{}
+	for _, name := range h {
+		all[name] = 0.1
+	}
+	O, dropped, cost = solver.SolveWithPenalties(costs, all)
+	if math.Abs(cost-0.7) > 1e-9 || O.Len() != 0 || len(dropped) != 7 {
+		t.Errorf("Cheap penalties give cost %v with %v dropping %v (wants 0.7, no row)", cost, O, dropped)
+	}
+	// row 1 (A D G) alone covers all but B, C, E, F, which row 2 (B C F) and
+	// a dropped E complete more cheaply than rows 0 and 4
+	O, dropped, cost = solver.SolveWithPenalties([]float64{5, 1, 1, 1, 5, 5}, map[string]float64{"E": 2})
+	if cost != 4 || !reflect.DeepEqual(O.Rows(), []int{1, 2}) || !reflect.DeepEqual(dropped, []string{"E"}) {
+		t.Errorf("Dropping E gives cost %v with rows %v dropping %v (wants 4, rows 1 2, E)", cost, O.Rows(), dropped)
+	}
+	// no row covers column C
+	solver = NewSolver([][]int{{1, 1, 0}}, []string{"A", "B", "C"})
+	if _, _, cost := solver.SolveWithPenalties([]float64{1}, nil); !math.IsInf(cost, 1) {
+		t.Errorf("Infeasible problem costs %v", cost)
+	}
+	if _, dropped, cost := solver.SolveWithPenalties([]float64{1}, map[string]float64{"C": 3}); cost != 4 || len(dropped) != 1 {
+		t.Errorf("Dropping C costs %v dropping %v (wants 4, C)", cost, dropped)
+	}
+	if cols := solver.matrix.ColCount(); cols != 3 {
+		t.Errorf("Matrix has %v columns after search (wants %v)", cols, 3)
+	}
+}
@@ -0,0 +1,111 @@
+package cover
+
+import (
+	"fmt"
+)
+
+// Exact cover problem over items of any comparable type, e.g. ints or structs
+// naming the constraints of a puzzle, so that builders index their items
+// without formatting and parsing column names. The items are only formatted
+// with fmt once, to name the columns of the solver, so that distinct items
+// may share a column name.
+type Problem[I comparable] struct {
+	// column index of each item, and the items by column index
+	index map[I]int
+	items []I
+	// column indexes of the secondary items
+	secondary map[int]bool
+	// column indexes of each row
+	rows [][]int
+}
+
+// Creates an empty problem over items of type I.
+func NewProblem[I comparable]() *Problem[I] {
+	return &Problem[I]{index: map[I]int{}, secondary: map[int]bool{}}
+}
+
+// Returns the column index of the item, adding it as a primary item when new.
+func (p *Problem[I]) column(item I) int {
+	j, ok := p.index[item]
+	if !ok {
+		j = len(p.items)
+		p.index[item] = j
+		p.items = append(p.items, item)
+	}
+	return j
+}
+
+// Adds primary items in order, skipping the ones already added. Items are
+// otherwise added by the first row covering them, so adding them beforehand
+// only fixes the column order, or the items no row covers.
+func (p *Problem[I]) AddItems(items ...I) {
+	for _, item := range items {
+		p.column(item)
+	}
+}
+
+// Adds secondary items, covered at most once. Items already added, e.g. by a
+// row, become secondary.
+func (p *Problem[I]) AddSecondary(items ...I) {
+	for _, item := range items {
+		p.secondary[p.column(item)] = true
+	}
+}
+
+// Adds a row covering the items, adding the new ones as primary items, and
+// returns its index, which is the row index of the solver.
+func (p *Problem[I]) AddRow(items ...I) int {
+	row := make([]int, len(items))
+	for k, item := range items {
+		row[k] = p.column(item)
+	}
+	p.rows = append(p.rows, row)
+	return len(p.rows) - 1
+}
+
+// Returns the items of the row of the given index, in the order added.
+func (p *Problem[I]) Row(i int) []I {
+	items := make([]I, len(p.rows[i]))
+	for k, j := range p.rows[i] {
+		items[k] = p.items[j]
+	}
+	return items
+}
+
+// Returns the number of items and of rows.
+func (p *Problem[I]) Size() (items, rows int) {
+	return len(p.items), len(p.rows)
+}
+
+// Builds a solver for the problem, whose decoder turns a row into its items,
+// see Row(). Later changes to the problem do not affect the solver.
+func (p *Problem[I]) Solver(opts ...Option) *Solver {
+	headers := make([]string, len(p.items))
+	for j, item := range p.items {
+		headers[j] = fmt.Sprint(item)
+	}
+	rows := make([][]int, len(p.rows))
+	for i, row := range p.rows {
+		rows[i] = append([]int(nil), row...)
+	}
+	m := NewSparseMatrixFromRows(rows, headers)
+	if len(p.secondary) > 0 {
+		cols := make([]*Node, 0, len(headers))
+		m.forEachCol(func(col *Node) {
+			cols = append(cols, col)
+		})
+		for j, col := range cols {
+			if p.secondary[j] {
+				m.setSecondary(col)
+			}
+		}
+	}
+	s := &Solver{matrix: m, Solutions: make([]*Solution, 0, 1)}
+	s.decoder = DecoderFunc(func(row *Node) interface{} {
+		return p.Row(row.Row)
+	})
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
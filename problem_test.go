@@ -0,0 +1,54 @@
+package cover
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProblem(t *testing.T) {
+	p := NewProblem[int]()
+	for _, row := range knuth() {
+		items := make([]int, 0)
+		for j, v := range row {
+			if v != 0 {
+				items = append(items, j)
+			}
+		}
+		p.AddRow(items...)
+	}
+	s := p.Solver()
+	decoded := s.SolveDecoded()
+	if len(decoded) != 3 {
+		t.Fatalf("Solution is %v", decoded)
+	}
+	if rows := s.Solutions[0].Rows(); !reflect.DeepEqual(p.Row(rows[0]), []int{2, 4, 5}) {
+		t.Errorf("Row %v has the items %v", rows[0], p.Row(rows[0]))
+	}
+}
+
+func TestProblemStructItems(t *testing.T) {
+	// a 4x4 latin square, each constraint being a struct
+	type item struct {
+		kind string
+		a, b int
+	}
+	p := NewProblem[item]()
+	for r := 0; r < 4; r++ {
+		for c := 0; c < 4; c++ {
+			for v := 0; v < 4; v++ {
+				p.AddRow(item{"cell", r, c}, item{"row", r, v}, item{"col", c, v})
+			}
+		}
+	}
+	if items, rows := p.Size(); items != 48 || rows != 64 {
+		t.Errorf("Problem has %v items and %v rows", items, rows)
+	}
+	if n := p.Solver(WithSizeTracking()).Count().Int64(); n != 576 {
+		t.Errorf("Latin squares of order 4 are %v (wants 576)", n)
+	}
+	// an optional item left uncovered does not prevent the solutions
+	p.AddSecondary(item{"extra", 0, 0}, item{"extra", 0, 0})
+	if n := p.Solver().Count().Int64(); n != 576 {
+		t.Errorf("Latin squares with a secondary item are %v", n)
+	}
+}
@@ -0,0 +1,58 @@
+package problems
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// Problem having a known number of solutions, to validate engines and forks
+// against.
+type Golden struct {
+	*Problem
+	Count *big.Int
+	// sorted row indexes of the solution when it is unique, may be nil
+	Solution []int
+	// takes more than a few seconds to count with the default engine
+	Slow bool
+}
+
+// Returns the reference instances: Knuth's example, the hard sudokus, the
+// pentomino rectangles and Langford pairings. Counts include the solutions
+// related by symmetries, since the problems do not break them.
+func GoldenSuite() []*Golden {
+	golden := []*Golden{{Problem: Knuth(), Count: big.NewInt(1), Solution: []int{0, 3, 4}}}
+	for _, name := range HardSudokuNames() {
+		golden = append(golden, &Golden{Problem: HardSudoku(name), Count: big.NewInt(1)})
+	}
+	// 2, 368, 1010 and 2339 tilings, times the 4 symmetries of a rectangle
+	for _, c := range []struct {
+		h, w  int
+		count int64
+		slow  bool
+	}{{3, 20, 8, false}, {4, 15, 1472, true}, {5, 12, 4040, true}, {6, 10, 9356, true}} {
+		golden = append(golden, &Golden{Problem: Pentominoes(c.h, c.w), Count: big.NewInt(c.count), Slow: c.slow})
+	}
+	// 1, 1, 26 and 150 pairings, times 2 for their reversals
+	for _, c := range [][2]int64{{3, 2}, {4, 2}, {7, 52}, {8, 300}} {
+		golden = append(golden, &Golden{Problem: Langford(int(c[0])), Count: big.NewInt(c[1])})
+	}
+	return golden
+}
+
+// Checks a count, and the rows of a solution if not nil, against the known
+// answers. Returns an error describing the mismatch.
+func (g *Golden) Check(count *big.Int, rows []int) error {
+	if count.Cmp(g.Count) != 0 {
+		return fmt.Errorf("%v: %v solutions (wants %v)", g.Name, count, g.Count)
+	}
+	if rows == nil || g.Solution == nil {
+		return nil
+	}
+	sorted := append([]int{}, rows...)
+	sort.Ints(sorted)
+	if fmt.Sprint(sorted) != fmt.Sprint(g.Solution) {
+		return fmt.Errorf("%v: solution %v (wants %v)", g.Name, sorted, g.Solution)
+	}
+	return nil
+}
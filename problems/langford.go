@@ -0,0 +1,30 @@
+package problems
+
+import (
+	"fmt"
+)
+
+// Builds the problem of the Langford pairings of order n: placing two copies
+// of each number k from 1 to n in a sequence of 2n positions, with k numbers
+// between the copies of k. The columns are the numbers followed by the
+// positions. Each pairing and its reversal are distinct solutions.
+func Langford(n int) *Problem {
+	headers := make([]string, 0, 3*n)
+	for k := 1; k <= n; k++ {
+		headers = append(headers, fmt.Sprint(k))
+	}
+	for i := 0; i < 2*n; i++ {
+		headers = append(headers, fmt.Sprintf("p%v", i))
+	}
+	matrix := make([][]int, 0)
+	for k := 1; k <= n; k++ {
+		for i := 0; i+k+1 < 2*n; i++ {
+			row := make([]int, 3*n)
+			row[k-1] = 1
+			row[n+i] = 1
+			row[n+i+k+1] = 1
+			matrix = append(matrix, row)
+		}
+	}
+	return &Problem{Name: fmt.Sprintf("langford-%v", n), Matrix: matrix, Headers: headers}
+}
@@ -0,0 +1,158 @@
+package problems
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/qur2/go-cover"
+)
+
+// The twelve pentominoes, drawn with '#' on rows separated by '/'.
+var Pentomino = map[string]string{
+	"F": ".##/##./.#.",
+	"I": "#####",
+	"L": "####/#...",
+	"N": "##../.###",
+	"P": "##/##/#.",
+	"T": "###/.#./.#.",
+	"U": "#.#/###",
+	"V": "#../#../###",
+	"W": "#../##./.##",
+	"X": ".#./###/.#.",
+	"Y": "####/.#..",
+	"Z": "##./.#./.##",
+}
+
+type cell struct{ x, y int }
+
+// Parses a drawn shape into its list of cells.
+func parseShape(s string) []cell {
+	cells := make([]cell, 0)
+	for x, line := range strings.Split(s, "/") {
+		for y, c := range line {
+			if c == '#' {
+				cells = append(cells, cell{x, y})
+			}
+		}
+	}
+	return cells
+}
+
+// Translates the shape to the origin and sorts its cells so that
+// equal shapes have equal representations.
+func normalize(cells []cell) []cell {
+	minX, minY := cells[0].x, cells[0].y
+	for _, c := range cells {
+		if c.x < minX {
+			minX = c.x
+		}
+		if c.y < minY {
+			minY = c.y
+		}
+	}
+	n := make([]cell, len(cells))
+	for i, c := range cells {
+		n[i] = cell{c.x - minX, c.y - minY}
+	}
+	sort.Slice(n, func(i, j int) bool {
+		return n[i].x < n[j].x || n[i].x == n[j].x && n[i].y < n[j].y
+	})
+	return n
+}
+
+// Returns the distinct rotations and reflections of a shape.
+func orientations(cells []cell) [][]cell {
+	seen := map[string]bool{}
+	all := make([][]cell, 0, 8)
+	for i := 0; i < 8; i++ {
+		t := make([]cell, len(cells))
+		for j, c := range cells {
+			x, y := c.x, c.y
+			if i&4 != 0 {
+				x, y = y, x
+			}
+			if i&2 != 0 {
+				x = -x
+			}
+			if i&1 != 0 {
+				y = -y
+			}
+			t[j] = cell{x, y}
+		}
+		t = normalize(t)
+		key := fmt.Sprint(t)
+		if !seen[key] {
+			seen[key] = true
+			all = append(all, t)
+		}
+	}
+	return all
+}
+
+// Builds the problem of tiling a board of the given size with the twelve
+// pentominoes. The board area must be 60, e.g. 3x20, 4x15, 5x12 or 6x10.
+// The columns are the pieces followed by the cells of the board.
+func Pentominoes(height, width int) *Problem {
+	names := make([]string, 0, len(Pentomino))
+	for name := range Pentomino {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pieces := len(names)
+	headers := make([]string, 0, pieces+height*width)
+	headers = append(headers, names...)
+	for i := 0; i < height*width; i++ {
+		headers = append(headers, fmt.Sprintf("%v,%v", i/width, i%width))
+	}
+	matrix := make([][]int, 0)
+	for p, name := range names {
+		for _, shape := range orientations(parseShape(Pentomino[name])) {
+			for x := 0; x < height; x++ {
+				for y := 0; y < width; y++ {
+					row := make([]int, len(headers))
+					row[p] = 1
+					fits := true
+					for _, c := range shape {
+						if x+c.x >= height || y+c.y >= width {
+							fits = false
+							break
+						}
+						row[pieces+(x+c.x)*width+y+c.y] = 1
+					}
+					if fits {
+						matrix = append(matrix, row)
+					}
+				}
+			}
+		}
+	}
+	return &Problem{
+		Name:    fmt.Sprintf("pentominoes-%vx%v", height, width),
+		Matrix:  matrix,
+		Headers: headers,
+		Decoder: PieceDecoder,
+	}
+}
+
+// Piece laid on the board, decoded from a row of a tiling problem.
+type Placement struct {
+	Piece string
+	// board cells covered, as x and y pairs
+	Cells [][2]int
+}
+
+// Decodes the rows of tiling problems, whose columns are pieces and cells
+// named "x,y", into placements.
+var PieceDecoder = cover.DecoderFunc(func(row *cover.Node) interface{} {
+	var p Placement
+	for _, n := range row.RowNodes() {
+		var x, y int
+		if _, err := fmt.Sscanf(n.Col.Name, "%d,%d", &x, &y); err == nil {
+			p.Cells = append(p.Cells, [2]int{x, y})
+		} else {
+			p.Piece = n.Col.Name
+		}
+	}
+	return p
+})
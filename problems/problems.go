@@ -0,0 +1,62 @@
+/*
+Package problems provides canonical exact cover instances, so that heuristics
+and engines can be benchmarked reproducibly.
+*/
+package problems
+
+import (
+	"github.com/qur2/go-cover"
+)
+
+// A named exact cover instance, given as a binary matrix and its column headers.
+type Problem struct {
+	Name    string
+	Matrix  [][]int
+	Headers []string
+	// turns rows into domain objects, may be nil
+	Decoder cover.Decoder
+}
+
+// Returns a new solver for the problem.
+func (p *Problem) Solver() *cover.Solver {
+	if p.Decoder != nil {
+		return cover.NewSolver(p.Matrix, p.Headers, cover.WithDecoder(p.Decoder))
+	}
+	return cover.NewSolver(p.Matrix, p.Headers)
+}
+
+// Knuth's example from the dancing links paper. It has exactly one solution.
+func Knuth() *Problem {
+	return &Problem{
+		Name: "knuth",
+		Matrix: [][]int{
+			{0, 0, 1, 0, 1, 1, 0},
+			{1, 0, 0, 1, 0, 0, 1},
+			{0, 1, 1, 0, 0, 1, 0},
+			{1, 0, 0, 1, 0, 0, 0},
+			{0, 1, 0, 0, 0, 0, 1},
+			{0, 0, 0, 1, 1, 0, 1},
+		},
+		Headers: []string{"A", "B", "C", "D", "E", "F", "G"},
+	}
+}
+
+// Returns all the problems of the corpus, using a fixed seed for random instances.
+func All() []*Problem {
+	all := []*Problem{Knuth()}
+	for _, name := range HardSudokuNames() {
+		all = append(all, HardSudoku(name))
+	}
+	for _, dim := range [][2]int{{3, 20}, {4, 15}, {5, 12}, {6, 10}} {
+		all = append(all, Pentominoes(dim[0], dim[1]))
+	}
+	all = append(all, RandomRegular(50, 200, 3, 1))
+	return all
+}
+
+// Describes the problem as a spec, e.g. to lint it or to send it over the wire.
+func (p *Problem) Spec() *cover.Spec {
+	s := cover.NewSpec(p.Matrix, p.Headers)
+	s.Name = p.Name
+	return s
+}
@@ -0,0 +1,120 @@
+package problems
+
+import (
+	"testing"
+)
+
+func TestKnuth(t *testing.T) {
+	s := Knuth().Solver()
+	s.Solve()
+	if len(s.Solutions) != 1 {
+		t.Errorf("Knuth example has exactly 1 solution, %v found", len(s.Solutions))
+	}
+}
+
+func TestHardSudokus(t *testing.T) {
+	for _, name := range HardSudokuNames() {
+		s := HardSudoku(name).Solver()
+		s.Solve()
+		if len(s.Solutions) != 1 || s.Solutions[0].Len() != 81 {
+			t.Errorf("Sudoku %v not solved", name)
+		}
+	}
+}
+
+func TestHardSudokuAllocs(t *testing.T) {
+	s := HardSudoku("ai-escargot").Solver()
+	// the first solve grows the statistics and the solution
+	s.Solve()
+	s.Reset()
+	allocs := testing.AllocsPerRun(10, func() {
+		s.Solve()
+		s.Reset()
+	})
+	if allocs > 0 {
+		t.Errorf("Solving again allocates %v times (wants none)", allocs)
+	}
+}
+
+func TestPentominoes(t *testing.T) {
+	p := Pentominoes(3, 20)
+	// 12 pieces followed by 60 cells
+	if len(p.Headers) != 72 {
+		t.Errorf("Pentominoes 3x20 has %v columns (wants %v)", len(p.Headers), 72)
+	}
+	s := p.Solver()
+	s.Solve()
+	if len(s.Solutions) != 1 || s.Solutions[0].Len() != 12 {
+		t.Errorf("Pentominoes 3x20 not solved")
+	}
+	cells := 0
+	for _, obj := range s.Decode(s.Solutions[0]) {
+		p := obj.(Placement)
+		if _, ok := Pentomino[p.Piece]; !ok || len(p.Cells) != 5 {
+			t.Errorf("Pentomino decoded as %v", p)
+		}
+		cells += len(p.Cells)
+	}
+	if cells != 60 {
+		t.Errorf("Pentominoes 3x20 cover %v cells (wants %v)", cells, 60)
+	}
+}
+
+// Solves the problem again and again with the same solver, whose statistics
+// and solution are reused.
+func benchmarkProblem(b *testing.B, p *Problem) {
+	s := p.Solver()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Solve()
+		s.Reset()
+	}
+}
+
+func BenchmarkKnuth(b *testing.B) {
+	benchmarkProblem(b, Knuth())
+}
+
+func BenchmarkHardSudoku(b *testing.B) {
+	benchmarkProblem(b, HardSudoku("ai-escargot"))
+}
+
+func BenchmarkPentominoes(b *testing.B) {
+	benchmarkProblem(b, Pentominoes(6, 10))
+}
+
+func TestRandomRegular(t *testing.T) {
+	// k beyond the columns covers them all
+	p := RandomRegular(4, 3, 6, 1)
+	for i, row := range p.Matrix {
+		if n := rowSize(row); n != 4 {
+			t.Errorf("Row %v covers %v columns (wants %v)", i, n, 4)
+		}
+	}
+	if p.Name != "random-3x4-k4-s1" {
+		t.Errorf("Clamped instance is named %v", p.Name)
+	}
+}
+
+func rowSize(row []int) int {
+	n := 0
+	for _, v := range row {
+		n += v
+	}
+	return n
+}
+
+func TestGoldenSuite(t *testing.T) {
+	for _, g := range GoldenSuite() {
+		// the slow ones take about 20s together
+		if g.Slow {
+			continue
+		}
+		s := g.Solver()
+		O := g.Solver().Solve()
+		if err := g.Check(s.Count(), O.Rows()); err != nil {
+			t.Error(err)
+		}
+	}
+}
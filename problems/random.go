@@ -0,0 +1,35 @@
+package problems
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Builds a random instance where each of the rows covers exactly k distinct
+// columns picked uniformly. The same seed always gives the same instance.
+// k is clamped to 0..cols, so that a too large k covers every column.
+func RandomRegular(cols, rows, k int, seed int64) *Problem {
+	if k > cols {
+		k = cols
+	}
+	if k < 0 {
+		k = 0
+	}
+	rnd := rand.New(rand.NewSource(seed))
+	headers := make([]string, cols)
+	for i := range headers {
+		headers[i] = fmt.Sprintf("c%v", i)
+	}
+	matrix := make([][]int, rows)
+	for i := range matrix {
+		matrix[i] = make([]int, cols)
+		for _, j := range rnd.Perm(cols)[:k] {
+			matrix[i][j] = 1
+		}
+	}
+	return &Problem{
+		Name:    fmt.Sprintf("random-%vx%v-k%v-s%v", rows, cols, k, seed),
+		Matrix:  matrix,
+		Headers: headers,
+	}
+}
@@ -0,0 +1,65 @@
+package problems
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/qur2/go-cover"
+)
+
+// Well known hard 9x9 sudokus, in the 81 chars format where '.' is an empty cell.
+var HardSudokus = map[string]string{
+	"ai-escargot":    "1....7.9..3..2...8..96..5....53..9...1..8...26....4...3......1..4......7..7...3..",
+	"easter-monster": "1.......2.9.4...5...6...7...5.9.3.......7.......85..4.7.....6...3...9.8...2.....1",
+	"inkala-2010":    "..53.....8......2..7..1.5..4....53...1..7...6..32...8..6.5....9..4....3......97..",
+	"everest":        "8..........36......7..9.2...5...7.......457.....1...3...1....68..85...1..9....4..",
+}
+
+// Returns the names of the hard sudokus, sorted.
+func HardSudokuNames() []string {
+	names := make([]string, 0, len(HardSudokus))
+	for name := range HardSudokus {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Builds the problem for one of the hard sudokus. Panics if the name is unknown.
+func HardSudoku(name string) *Problem {
+	s, ok := HardSudokus[name]
+	if !ok {
+		panic(fmt.Sprintf("Sudoku \"%v\" not found", name))
+	}
+	return Sudoku("sudoku-"+name, ParseSudoku(s))
+}
+
+// Parses a 81 chars sudoku, any char other than 1-9 being an empty cell.
+func ParseSudoku(s string) [][]int {
+	grid := make([][]int, 9)
+	for i := range grid {
+		grid[i] = make([]int, 9)
+		for j := range grid[i] {
+			if c := s[i*9+j]; c >= '1' && c <= '9' {
+				grid[i][j] = int(c - '0')
+			}
+		}
+	}
+	return grid
+}
+
+// Builds a plain exact cover problem from a sudoku grid. The givens are encoded
+// by keeping only the row of the given digit for each filled cell.
+func Sudoku(name string, grid [][]int) *Problem {
+	dim := len(grid)
+	m, h := cover.SudokuConstraintMatrix(dim)
+	matrix := make([][]int, 0, len(m))
+	for i, row := range m {
+		cell := i / dim
+		given := grid[cell/dim][cell%dim]
+		if given == 0 || given == i%dim+1 {
+			matrix = append(matrix, row)
+		}
+	}
+	return &Problem{Name: name, Matrix: matrix, Headers: h, Decoder: cover.SudokuDecoder}
+}
@@ -0,0 +1,25 @@
+package cover
+
+// Tags the following searches with pprof labels, so that the CPU profiles of
+// services running many concurrent solves can be attributed per problem.
+// Besides the problem name, extra labels can be given as key and value pairs.
+// The engine and heuristic labels are added by the search itself.
+func (m *SparseMatrix) SetLabels(problem string, labels ...string) {
+	if len(labels)%2 != 0 {
+		panic("cover: odd number of label keys and values")
+	}
+	m.labels = append([]string{"problem", problem}, labels...)
+}
+
+// Names the column choice strategy of the search, for the profile labels.
+func (m *SparseMatrix) heuristic() string {
+	switch {
+	case m.lookahead > 0:
+		return "lookahead"
+	case m.units:
+		return "units"
+	case m.sizes != nil:
+		return "buckets"
+	}
+	return "smallest"
+}
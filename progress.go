@@ -0,0 +1,55 @@
+package cover
+
+import (
+	"time"
+)
+
+// Snapshot of a search in progress.
+type Progress struct {
+	// rows tried by branching, see Stats.Candidates()
+	Nodes     int64
+	Solutions int64
+	Depth     int
+	Elapsed   time.Duration
+	// copy of the rows chosen above the current depth
+	Stack Solution
+}
+
+// Nodes tried per second since the start of the search.
+func (p Progress) Rate() float64 {
+	if p.Elapsed <= 0 {
+		return 0
+	}
+	return float64(p.Nodes) / p.Elapsed.Seconds()
+}
+
+// Calls f about every period during the following searches, using a hook,
+// or every fixed number of events when the searches are deterministic.
+// The elapsed time counts from this call. The search is aborted when f
+// returns false, e.g. on a user interrupt.
+func (m *SparseMatrix) OnProgress(every time.Duration, f func(Progress) bool) {
+	start := time.Now()
+	last := start
+	events := 0
+	m.AddHook(func(e Event, col *Node, depth int) {
+		// reading the clock at every event would slow the search down
+		if events++; events%256 != 0 {
+			return
+		}
+		if m.deterministic && events%deterministicPeriod != 0 {
+			return
+		}
+		now := time.Now()
+		if !m.deterministic && now.Sub(last) < every {
+			return
+		}
+		last = now
+		p := Progress{Nodes: m.stats.Candidates(), Solutions: m.stats.Solutions, Depth: depth, Elapsed: now.Sub(start)}
+		if m.current != nil && depth <= len(*m.current) {
+			p.Stack = append(Solution{}, (*m.current)[:depth]...)
+		}
+		if !f(p) {
+			m.Abort()
+		}
+	})
+}
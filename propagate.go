@@ -0,0 +1,57 @@
+package cover
+
+// Makes the search apply forced rows before branching, see Propagate().
+func (m *SparseMatrix) PropagateUnits() {
+	m.units = true
+}
+
+// Returns a column having a single row left, or nil.
+func (m *SparseMatrix) unitCol() *Node {
+	if m.sizes != nil {
+		if len(m.sizes.heads) > 1 {
+			return m.sizes.heads[1]
+		}
+		return nil
+	}
+	root := m.Root()
+	for col := root.Right; col != root; col = col.Right {
+		if col.Size == 1 {
+			return col
+		}
+	}
+	return nil
+}
+
+// Includes in the solution, from level k, the rows of the columns having a single
+// row left, until there is no such column or the matrix reaches a dead end.
+// Returns the number of rows included, to be given to Unpropagate().
+func (m *SparseMatrix) Propagate(O *Solution, k int) int {
+	n := 0
+	for !m.DeadEnd() {
+		c := m.unitCol()
+		if c == nil {
+			break
+		}
+		r := c.Down
+		m.stats.level(k + n)
+		m.stats.Levels[k+n].Forced++
+		O.Set(k+n, r)
+		m.cover(c, k+n)
+		for j := r.Right; j != r; j = j.Right {
+			m.commit(j, k+n)
+		}
+		n++
+	}
+	return n
+}
+
+// Undoes the n rows included by Propagate() from level k, in reverse order.
+func (m *SparseMatrix) Unpropagate(O *Solution, k, n int) {
+	for i := k + n - 1; i >= k; i-- {
+		r := O.Get(i)
+		for j := r.Left; j != r; j = j.Left {
+			m.uncommit(j, i)
+		}
+		m.uncommit(r, i)
+	}
+}
@@ -0,0 +1,190 @@
+/*
+Package puzzlebank stores generated sudokus for app backends. Puzzles are
+deduplicated by the hash of their canonical form, and served at most once.
+A bank lives in memory and can be backed by a flat file of JSON lines, which
+only grows: each line records a new puzzle or puzzles served.
+*/
+package puzzlebank
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/qur2/go-cover"
+)
+
+// Puzzle stored in a bank, grids being in the Line format.
+type Entry struct {
+	ID         int              `json:"id"`
+	Puzzle     string           `json:"puzzle"`
+	Solution   string           `json:"solution"`
+	Hash       string           `json:"hash"`
+	Difficulty cover.Difficulty `json:"difficulty"`
+	Clues      int              `json:"clues"`
+	Served     bool             `json:"served,omitempty"`
+}
+
+// Line of the file of a bank: a new entry, or the IDs of entries served.
+type record struct {
+	Entry  *Entry `json:"entry,omitempty"`
+	Served []int  `json:"served,omitempty"`
+}
+
+// Collection of puzzles, safe for concurrent use.
+type Bank struct {
+	mu      sync.Mutex
+	entries []*Entry
+	byHash  map[string]*Entry
+	// where records are appended, nil for a bank in memory only
+	w io.Writer
+	f *os.File
+}
+
+// Creates an empty bank in memory.
+func New() *Bank {
+	return &Bank{entries: make([]*Entry, 0), byHash: map[string]*Entry{}}
+}
+
+// Opens the bank backed by the file at path, creating it if needed.
+func Open(path string) (*Bank, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	b := New()
+	if err := b.replay(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	b.w, b.f = f, f
+	return b, nil
+}
+
+// Applies the records read from r.
+func (b *Bank) replay(r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		var rec record
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return err
+		}
+		b.apply(&rec)
+	}
+	return sc.Err()
+}
+
+func (b *Bank) apply(rec *record) {
+	if e := rec.Entry; e != nil {
+		b.entries = append(b.entries, e)
+		b.byHash[e.Hash] = e
+	}
+	for _, id := range rec.Served {
+		if id >= 0 && id < len(b.entries) {
+			b.entries[id].Served = true
+		}
+	}
+}
+
+// Applies a record and appends it to the file, if any.
+func (b *Bank) write(rec *record) error {
+	if b.w != nil {
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		if _, err := b.w.Write(append(data, '\n')); err != nil {
+			return err
+		}
+	}
+	b.apply(rec)
+	return nil
+}
+
+// Returns the hash identifying the puzzle up to its symmetries, see
+// cover.CanonicalGrid().
+func Hash(puzzle [][]int) string {
+	sum := sha256.Sum256([]byte(cover.CanonicalGrid(puzzle)))
+	return hex.EncodeToString(sum[:])
+}
+
+// Stores a puzzle and its solution, unless the bank already holds the same
+// puzzle up to its symmetries. Returns the entry of the puzzle, and whether
+// it was added.
+func (b *Bank) Add(puzzle, solution [][]int, d cover.Difficulty) (*Entry, bool, error) {
+	h := Hash(puzzle)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if e, ok := b.byHash[h]; ok {
+		return e, false, nil
+	}
+	clues := 0
+	for _, line := range puzzle {
+		for _, cell := range line {
+			if cell > 0 {
+				clues++
+			}
+		}
+	}
+	e := &Entry{
+		ID:         len(b.entries),
+		Puzzle:     cover.FormatGrid(puzzle, nil, cover.Line),
+		Solution:   cover.FormatGrid(solution, nil, cover.Line),
+		Hash:       h,
+		Difficulty: d,
+		Clues:      clues,
+	}
+	if err := b.write(&record{Entry: e}); err != nil {
+		return nil, false, err
+	}
+	return e, true, nil
+}
+
+// Returns the number of puzzles stored, and of those not served yet.
+func (b *Bank) Len() (total, unserved int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, e := range b.entries {
+		if !e.Served {
+			unserved++
+		}
+	}
+	return len(b.entries), unserved
+}
+
+// Returns up to n puzzles of the difficulty not served yet, oldest first,
+// and marks them served so that no other call returns them.
+func (b *Bank) Serve(d cover.Difficulty, n int) ([]*Entry, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	served := make([]*Entry, 0, n)
+	ids := make([]int, 0, n)
+	for _, e := range b.entries {
+		if len(served) == n {
+			break
+		}
+		if !e.Served && e.Difficulty == d {
+			served = append(served, e)
+			ids = append(ids, e.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return served, nil
+	}
+	if err := b.write(&record{Served: ids}); err != nil {
+		return nil, err
+	}
+	return served, nil
+}
+
+// Closes the file of the bank, if any.
+func (b *Bank) Close() error {
+	if b.f != nil {
+		return b.f.Close()
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package puzzlebank
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/qur2/go-cover"
+)
+
+func TestBank(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bank.jsonl")
+	b, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := cover.NewSudokuSolver(9)
+	for i, p := range []string{
+		"1....7.9..3..2...8..96..5....53..9...1..8...26....4...3......1..4......7..7...3..",
+		"8..........36......7..9.2...5...7.......457.....1...3...1....68..85...1..9....4..",
+		"..3.2.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3..",
+	} {
+		puzzle, err := cover.ParseGrid(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		solution := s.Grid(s.Solve(puzzle))
+		d := cover.Hard
+		if i == 2 {
+			d = cover.Easy
+		}
+		if _, added, err := b.Add(puzzle, solution, d); !added || err != nil {
+			t.Errorf("Puzzle %v not added: %v", i, err)
+		}
+		// the same puzzle rotated and relabeled
+		twin := cover.Relabel(cover.RotateGrid(puzzle), []int{2, 3, 4, 5, 6, 7, 8, 9, 1})
+		if e, added, _ := b.Add(twin, cover.RotateGrid(solution), d); added || e.Puzzle != p {
+			t.Errorf("Puzzle %v added twice", i)
+		}
+	}
+	hard, err := b.Serve(cover.Hard, 10)
+	if err != nil || len(hard) != 2 || hard[0].Clues != 23 {
+		t.Errorf("Serving 10 hard puzzles gives %v, error %v", hard, err)
+	}
+	b.Close()
+	b, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+	if total, unserved := b.Len(); total != 3 || unserved != 1 {
+		t.Errorf("Reopened bank has %v puzzles, %v unserved (wants 3, 1)", total, unserved)
+	}
+	if hard, _ := b.Serve(cover.Hard, 10); len(hard) != 0 {
+		t.Errorf("Hard puzzles served twice")
+	}
+	if easy, _ := b.Serve(cover.Easy, 10); len(easy) != 1 || easy[0].Difficulty != cover.Easy {
+		t.Errorf("Serving easy puzzles gives %v", easy)
+	}
+}
@@ -0,0 +1,82 @@
+package cover
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Generates a random binary matrix where each cell is set with the given
+// density. Every row has at least one cell set, so that the matrix has no
+// rows without columns. The same seed always gives the same matrix.
+func GenerateRandomCover(cols, rows int, density float64, seed int64) (matrix [][]int, headers []string) {
+	rnd := rand.New(rand.NewSource(seed))
+	return randomRows(rnd, cols, rows, density), randomHeaders(cols)
+}
+
+// Same as GenerateRandomCover, but also plants a known solution in the matrix.
+// The planted rows partition the columns and are shuffled among the random ones,
+// their indexes are returned in increasing order.
+func GeneratePlantedCover(cols, rows int, density float64, seed int64) (matrix [][]int, headers []string, planted []int) {
+	rnd := rand.New(rand.NewSource(seed))
+	// split a random permutation of the columns in chunks of random length
+	solution := make([][]int, 0)
+	perm := rnd.Perm(cols)
+	for len(perm) > 0 {
+		size := 1 + rnd.Intn(len(perm))
+		if density > 0 {
+			if max := int(float64(cols)*density) + 1; size > max {
+				size = max
+			}
+		}
+		row := make([]int, cols)
+		for _, j := range perm[:size] {
+			row[j] = 1
+		}
+		solution = append(solution, row)
+		perm = perm[size:]
+	}
+	matrix = randomRows(rnd, cols, rows, density)
+	// insert at random positions, keeping track of where planted rows end up
+	isPlanted := make([]bool, len(matrix))
+	for _, row := range solution {
+		i := rnd.Intn(len(matrix) + 1)
+		matrix = append(matrix[:i], append([][]int{row}, matrix[i:]...)...)
+		isPlanted = append(isPlanted[:i], append([]bool{true}, isPlanted[i:]...)...)
+	}
+	planted = make([]int, 0, len(solution))
+	for i, p := range isPlanted {
+		if p {
+			planted = append(planted, i)
+		}
+	}
+	return matrix, randomHeaders(cols), planted
+}
+
+func randomRows(rnd *rand.Rand, cols, rows int, density float64) [][]int {
+	if cols <= 0 {
+		return make([][]int, 0)
+	}
+	matrix := make([][]int, rows)
+	for i := range matrix {
+		matrix[i] = make([]int, cols)
+		empty := true
+		for j := range matrix[i] {
+			if rnd.Float64() < density {
+				matrix[i][j] = 1
+				empty = false
+			}
+		}
+		if empty {
+			matrix[i][rnd.Intn(cols)] = 1
+		}
+	}
+	return matrix
+}
+
+func randomHeaders(cols int) []string {
+	headers := make([]string, cols)
+	for i := range headers {
+		headers[i] = fmt.Sprintf("c%v", i)
+	}
+	return headers
+}
@@ -0,0 +1,60 @@
+package cover
+
+import (
+	"testing"
+)
+
+func TestGeneratePlantedCover(t *testing.T) {
+	m, h, planted := GeneratePlantedCover(20, 30, 0.2, 42)
+	if len(m) != 30+len(planted) {
+		t.Errorf("Matrix has %v rows (wants %v)", len(m), 30+len(planted))
+	}
+	covered := make([]int, len(h))
+	for _, i := range planted {
+		for j, v := range m[i] {
+			covered[j] += v
+		}
+	}
+	for j, c := range covered {
+		if c != 1 {
+			t.Errorf("Column %v covered %v times by the planted rows", h[j], c)
+		}
+	}
+	solver := NewSolver(m, h)
+	solver.Solve()
+	if len(solver.Solutions) != 1 {
+		t.Errorf("Planted cover has no solution")
+	}
+}
+
+func TestGenerateWithoutColumns(t *testing.T) {
+	if m, h := GenerateRandomCover(0, 10, 0.2, 42); len(m) != 0 || len(h) != 0 {
+		t.Errorf("Matrix without columns has %v rows and headers %v", len(m), h)
+	}
+	m, h, planted := GeneratePlantedCover(0, 10, 0.2, 42)
+	if len(m) != 0 || len(h) != 0 || len(planted) != 0 {
+		t.Errorf("Planted matrix without columns has %v rows, headers %v and planted rows %v", len(m), h, planted)
+	}
+}
+
+func TestCheckPlanted(t *testing.T) {
+	for seed := int64(0); seed < 10; seed++ {
+		if err := CheckPlanted(16, 40, 0.2, seed); err != nil {
+			t.Errorf("Seed %v: %v", seed, err)
+		}
+		if err := CheckPlanted(16, 40, 0.2, seed, WithNogoods(NewNogoods(2))); err != nil {
+			t.Errorf("Seed %v with nogoods: %v", seed, err)
+		}
+	}
+}
+
+func FuzzCheckPlanted(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{12, 30, 60, 1, 2, 3, 4, 5})
+	f.Add([]byte("go-cover"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if err := CheckPlantedBytes(data); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
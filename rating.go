@@ -0,0 +1,124 @@
+package cover
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// Rating band of a sudoku, as a human solver perceives it.
+type Difficulty int
+
+const (
+	Easy Difficulty = iota
+	Medium
+	Hard
+	Expert
+)
+
+// Grade of a sudoku having no solution, below every difficulty.
+const Unsolvable Difficulty = -1
+
+func (d Difficulty) String() string {
+	switch d {
+	case Easy:
+		return "easy"
+	case Medium:
+		return "medium"
+	case Hard:
+		return "hard"
+	case Expert:
+		return "expert"
+	case Unsolvable:
+		return "unsolvable"
+	}
+	return "Difficulty(" + strconv.Itoa(int(d)) + ")"
+}
+
+// Sudoku having a known human rating.
+type RatedPuzzle struct {
+	Grid   [][]int
+	Rating Difficulty
+}
+
+// Thresholds mapping the search effort of a sudoku to its difficulty. Easy
+// ones are solved by unit propagation alone, hence an effort of 0. A
+// calibration is saved as JSON to grade later puzzles the same way.
+type Calibration struct {
+	// highest effort of each difficulty but the last
+	Thresholds []float64
+}
+
+// Rough thresholds, to use until calibrated against a rated corpus.
+var DefaultCalibration = Calibration{Thresholds: []float64{0, 20, 200}}
+
+// Reads a calibration saved by Save().
+func LoadCalibration(r io.Reader) (*Calibration, error) {
+	c := &Calibration{}
+	if err := json.NewDecoder(r).Decode(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Writes the calibration as JSON.
+func (c *Calibration) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(c)
+}
+
+// Returns the difficulty of a search effort, Unsolvable if it is negative,
+// see Effort().
+func (c *Calibration) Grade(effort float64) Difficulty {
+	if effort < 0 {
+		return Unsolvable
+	}
+	d := 0
+	for d < len(c.Thresholds) && effort > c.Thresholds[d] {
+		d++
+	}
+	return Difficulty(d)
+}
+
+// Returns the effort spent solving the grid, i.e. the number of rows tried by
+// branching once unit propagation is exhausted. The effort is -1 if the grid
+// has no solution.
+func (s *SudokuSolver) Effort(sudoku [][]int) float64 {
+	if s.Solve(sudoku).Len() == 0 {
+		return -1
+	}
+	stats := s.Stats()
+	return float64(stats.Candidates())
+}
+
+// Grades the grid with the calibration, Unsolvable if it has no solution.
+func (s *SudokuSolver) Rate(sudoku [][]int, c *Calibration) Difficulty {
+	return c.Grade(s.Effort(sudoku))
+}
+
+// Computes the thresholds matching the ratings of the corpus: the threshold
+// between two consecutive difficulties lies halfway between the highest effort
+// of the easier one and the lowest effort of the harder one. A difficulty
+// missing from the corpus gets the threshold below it. Puzzles having no
+// solution are ignored.
+func (s *SudokuSolver) Calibrate(corpus []RatedPuzzle) *Calibration {
+	efforts := make([][]float64, Expert+1)
+	for _, p := range corpus {
+		if e := s.Effort(p.Grid); e >= 0 && p.Rating >= Easy && p.Rating <= Expert {
+			efforts[p.Rating] = append(efforts[p.Rating], e)
+		}
+	}
+	for _, e := range efforts {
+		sort.Float64s(e)
+	}
+	c := &Calibration{Thresholds: make([]float64, Expert)}
+	prev := 0.
+	for d := range c.Thresholds {
+		lo, hi := efforts[d], efforts[d+1]
+		if len(lo) > 0 && len(hi) > 0 {
+			prev = (lo[len(lo)-1] + hi[0]) / 2
+		}
+		c.Thresholds[d] = prev
+	}
+	return c
+}
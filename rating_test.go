@@ -0,0 +1,45 @@
+package cover
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCalibrate(t *testing.T) {
+	corpus := []RatedPuzzle{
+		{parseGrid("53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"), Easy},
+		{parseGrid("..3.2.6..9..3.5..1..18.64....81.29..7.......8..67.82....26.95..8..2.3..9..5.1.3.."), Easy},
+		{parseGrid("1....7.9..3..2...8..96..5....53..9...1..8...26....4...3......1..4......7..7...3.."), Medium},
+		{parseGrid("4.....8.5.3..........7......2.....6.....8.4......1.......6.3.7.5..2.....1.4......"), Hard},
+		{parseGrid("8..........36......7..9.2...5...7.......457.....1...3...1....68..85...1..9....4.."), Hard},
+		{parseGrid("52...6.........7.13...........4..8..6......5...........418.........3..2...87....."), Expert},
+		{parseGrid("6....894.9....61...7..4....2..61..........2...89..2.......6...5.......3.8....16.."), Expert},
+	}
+	s := NewSudokuSolver(9)
+	c := s.Calibrate(corpus)
+	var b bytes.Buffer
+	if err := c.Save(&b); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadCalibration(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range corpus {
+		if d := s.Rate(p.Grid, c); d != p.Rating {
+			t.Errorf("Puzzle %v rated %v (wants %v)", i, d, p.Rating)
+		}
+	}
+}
+
+func TestRateUnsolvable(t *testing.T) {
+	s := NewSudokuSolver(9)
+	// two 1 in the first row
+	if d := s.Rate(parseGrid("11"+strings.Repeat(".", 79)), &DefaultCalibration); d != Unsolvable {
+		t.Errorf("Puzzle without solution rated %v (wants %v)", d, Unsolvable)
+	}
+	if d := DefaultCalibration.Grade(0); d != Easy {
+		t.Errorf("Effort 0 graded %v (wants %v)", d, Easy)
+	}
+}
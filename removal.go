@@ -0,0 +1,127 @@
+package cover
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Effect of removing a clue from a puzzle.
+type ClueImpact struct {
+	// the clue removed
+	Cell SudokuCell
+	// completions of the puzzle without the clue, up to the bound
+	Solutions int
+	// the completions were all counted
+	Exhaustive bool
+}
+
+// Tells whether the puzzle stays uniquely solvable without the clue.
+func (c ClueImpact) Unique() bool {
+	return c.Solutions == 1 && c.Exhaustive
+}
+
+// Effects of removing each clue of a puzzle, see ClueRemoval().
+type RemovalReport struct {
+	// impacts of the clues, in row-major order
+	Impacts []ClueImpact
+	// number of clues per count of solutions after their removal, the bound
+	// standing for the counts reaching it
+	Histogram map[int]int
+}
+
+// Returns the clues which can be removed keeping a unique solution.
+func (r *RemovalReport) Removable() []SudokuCell {
+	cells := make([]SudokuCell, 0)
+	for _, c := range r.Impacts {
+		if c.Unique() {
+			cells = append(cells, c.Cell)
+		}
+	}
+	return cells
+}
+
+// Guesser counting up to max solutions, aborting the search at the last.
+type boundedCounter struct {
+	matrix *SparseMatrix
+	max, n int
+}
+
+func (c *boundedCounter) ChooseCol(k int) *Node {
+	return c.matrix.SmallestCol()
+}
+func (c *boundedCounter) Eureka(O *Solution) {
+	if c.n++; c.n >= c.max {
+		c.matrix.Abort()
+	}
+}
+func (c *boundedCounter) Terminate() bool {
+	return false
+}
+
+// Counts up to max completions of the puzzle, telling whether they were all
+// counted. The matrix is restored afterwards.
+func (s *SudokuSolver) countUpTo(puzzle [][]int, max int) (int, bool) {
+	s.Reset()
+	m := s.matrix
+	O := m.NewSolution()
+	rows := s.givens(puzzle)
+	if rows == nil {
+		return 0, true
+	}
+	k, err := m.Require(O, rows...)
+	if err != nil {
+		return 0, true
+	}
+	c := &boundedCounter{matrix: m, max: max}
+	m.Search(O, k, c)
+	m.Release(O, k)
+	return c.n, !m.Aborted()
+}
+
+// Evaluates the removal of each clue of a puzzle, 0 being an empty cell, e.g.
+// a complete grid to start building a puzzle from: counts up to max
+// completions without the clue, which tells the clues a construction tool
+// may remove next. The clues are shared among workers, each one having its
+// own solver, a non positive count using all the CPUs.
+func ClueRemoval(puzzle [][]int, max, workers int) *RemovalReport {
+	if workers < 1 {
+		workers = runtime.NumCPU()
+	}
+	dim := len(puzzle)
+	report := &RemovalReport{Histogram: map[int]int{}}
+	for i, line := range puzzle {
+		for j, digit := range line {
+			if digit > 0 {
+				report.Impacts = append(report.Impacts, ClueImpact{Cell: SudokuCell{i, j, digit}})
+			}
+		}
+	}
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s := NewSudokuSolver(dim)
+			without := make([][]int, dim)
+			for i := range without {
+				without[i] = append([]int{}, puzzle[i]...)
+			}
+			for i := range next {
+				c := &report.Impacts[i]
+				without[c.Cell.X][c.Cell.Y] = 0
+				c.Solutions, c.Exhaustive = s.countUpTo(without, max)
+				without[c.Cell.X][c.Cell.Y] = c.Cell.Digit
+			}
+		}()
+	}
+	for i := range report.Impacts {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	for _, c := range report.Impacts {
+		report.Histogram[c.Solutions]++
+	}
+	return report
+}
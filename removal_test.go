@@ -0,0 +1,34 @@
+package cover
+
+import (
+	"testing"
+)
+
+func TestClueRemoval(t *testing.T) {
+	grid := parseGrid("534678912672195348198342567859761423426853791713924856961537284287419635345286179")
+	r := ClueRemoval(grid, 10, 3)
+	if len(r.Impacts) != 81 || len(r.Removable()) != 81 || r.Histogram[1] != 81 {
+		t.Errorf("Complete grid: %v impacts, %v removable, histogram %v", len(r.Impacts), len(r.Removable()), r.Histogram)
+	}
+	// the cells of a unique rectangle but one
+	for _, c := range [][2]int{{0, 3}, {0, 4}, {3, 3}} {
+		grid[c[0]][c[1]] = 0
+	}
+	r = ClueRemoval(grid, 10, 0)
+	if len(r.Impacts) != 78 {
+		t.Fatalf("%v impacts (wants 78)", len(r.Impacts))
+	}
+	for _, c := range r.Impacts {
+		if c.Cell.X == 3 && c.Cell.Y == 4 {
+			if c.Solutions != 2 || !c.Exhaustive || c.Unique() {
+				t.Errorf("Removing the last cell of the rectangle: %+v (wants 2 solutions)", c)
+			}
+		}
+	}
+	if r.Histogram[2] == 0 || r.Histogram[1]+r.Histogram[2]+r.Histogram[10] > 78 {
+		t.Errorf("Histogram %v", r.Histogram)
+	}
+	if r := ClueRemoval(make([][]int, 4), 5, 1); len(r.Impacts) != 0 {
+		t.Errorf("Empty grid has %v impacts", len(r.Impacts))
+	}
+}
@@ -0,0 +1,73 @@
+package cover
+
+import (
+	"errors"
+)
+
+// Error returned when requiring a row which is no longer in the matrix.
+var ErrConflict = errors.New("row conflicts with the rows already chosen")
+
+// Returns the first row containing all the named columns, or nil if there is
+// none. Only the rows still in the matrix are looked up.
+func (m *SparseMatrix) Row(names ...string) *Node {
+	if len(names) == 0 {
+		return nil
+	}
+	col := m.findCol(names[0])
+	if col == nil {
+		return nil
+	}
+	for r := col.Down; r != col; r = r.Down {
+		found := 0
+		r.ForEachInRow(func(n *Node) {
+			for _, name := range names {
+				if n.Col.Name == name {
+					found++
+				}
+			}
+		})
+		if found == len(names) {
+			return r
+		}
+	}
+	return nil
+}
+
+// Tells whether the row is still in the matrix, i.e. none of its columns
+// has been covered and none of its nodes has been unlinked, e.g. by a
+// column purified with another color.
+func isActive(r *Node) bool {
+	for _, n := range r.RowNodes() {
+		if n.Col.Left.Right != n.Col || n.Up.Down != n {
+			return false
+		}
+	}
+	return true
+}
+
+// Includes the rows, given by any of their nodes, at the first levels of the
+// solution and covers their columns, as if the search had chosen them.
+// Returns the level to start the search from, or ErrConflict if a row is no
+// longer available, in which case the matrix is left untouched and the
+// solution emptied.
+// The rows are released by Release(), or by Unpropagate() from level 0.
+func (m *SparseMatrix) Require(O *Solution, rows ...*Node) (int, error) {
+	for k, r := range rows {
+		if !isActive(r) {
+			m.Release(O, k)
+			*O = (*O)[:0]
+			return 0, ErrConflict
+		}
+		O.Set(k, r)
+		m.commit(r, k)
+		for j := r.Right; j != r; j = j.Right {
+			m.commit(j, k)
+		}
+	}
+	return len(rows), nil
+}
+
+// Uncovers the columns of the k rows included by Require().
+func (m *SparseMatrix) Release(O *Solution, k int) {
+	m.Unpropagate(O, 0, k)
+}
@@ -0,0 +1,86 @@
+package cover
+
+import (
+	"math/rand"
+)
+
+// Returns the i-th term of the Luby sequence: 1 1 2 1 1 2 4 1 1 2 1 1 2 4 8...
+// The sequence gives restart budgets within a log factor of the best fixed one.
+func luby(i int64) int64 {
+	for k := uint(1); ; k++ {
+		if i == 1<<k-1 {
+			return 1 << (k - 1)
+		}
+		if i < 1<<k-1 {
+			return luby(i - 1<<(k-1) + 1)
+		}
+	}
+}
+
+// Guesser breaking ties between the smallest columns at random, unless rnd is
+// nil, and aborting the search once its budget of branching steps is spent.
+type restarter struct {
+	matrix   *SparseMatrix
+	rnd      *rand.Rand
+	budget   int64
+	solution *Solution
+	// whether the budget aborted the search, rather than e.g. a node limit
+	spent bool
+}
+
+func (r *restarter) ChooseCol(k int) *Node {
+	r.budget--
+	if r.budget < 0 {
+		r.spent = true
+		r.matrix.Abort()
+	}
+	var chosen *Node
+	ties := 0
+	root := r.matrix.Root()
+	for col := root.Right; col != root; col = col.Right {
+		if chosen == nil || col.Size < chosen.Size {
+			chosen, ties = col, 1
+		} else if col.Size == chosen.Size {
+			// reservoir sampling among the ties
+			ties++
+			if r.rnd != nil && r.rnd.Intn(ties) == 0 {
+				chosen = col
+			}
+		}
+	}
+	return chosen
+}
+func (r *restarter) Eureka(O *Solution) {
+	r.solution = O
+}
+func (r *restarter) Terminate() bool {
+	return r.solution != nil
+}
+
+// Searches a single solution with randomized column choices, restarting the
+// search each time a budget of branching steps is spent. The i-th run gets
+// unit times the i-th term of the Luby sequence, so that an unlucky early
+// choice cannot trap the search for long. The budgets grow without bound, so
+// the search still ends, with an empty solution if there is none. A search
+// aborted otherwise, e.g. by a node or depth limit or by a progress callback,
+// is not restarted and also returns an empty solution. The seed is ignored by
+// deterministic searches, see SetDeterministic().
+func (s *Solver) SolveWithRestarts(unit int64, seed int64) *Solution {
+	s.matrix.ResetStats()
+	r := &restarter{matrix: s.matrix}
+	if !s.matrix.deterministic {
+		r.rnd = rand.New(rand.NewSource(seed))
+	}
+	for i := int64(1); ; i++ {
+		O := s.matrix.NewSolution()
+		r.budget, r.spent = luby(i)*unit, false
+		s.matrix.Search(O, 0, r)
+		if r.solution != nil {
+			s.Solutions = append(s.Solutions, O)
+			return O
+		}
+		if !s.matrix.Aborted() || !r.spent || s.matrix.LimitReached() {
+			return s.matrix.NewSolution()
+		}
+	}
+}
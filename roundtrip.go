@@ -0,0 +1,71 @@
+package cover
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+)
+
+// Maps arbitrary bytes, e.g. the input of a fuzz target, to parameters of
+// GeneratePlantedCover() small enough to enumerate every solution: up to 24
+// columns and 48 random rows of density 0.05 to 0.5.
+func PlantedParams(data []byte) (cols, rows int, density float64, seed int64) {
+	var b [8]byte
+	copy(b[:], data)
+	for i, c := range data {
+		b[i%8] ^= c
+	}
+	cols = 1 + int(b[0])%24
+	rows = int(b[1]) % 49
+	density = 0.05 + 0.45*float64(b[2])/math.MaxUint8
+	for _, c := range b[3:] {
+		seed = seed<<8 | int64(c)
+	}
+	return cols, rows, density, seed
+}
+
+// Builds a planted instance with GeneratePlantedCover(), enumerates it with a
+// solver having the options, and checks that every solution reported is an
+// exact cover, that none is reported twice, that the planted one is among
+// them, and that Count() agrees. Returns an error describing the first failure.
+func CheckPlanted(cols, rows int, density float64, seed int64, opts ...Option) error {
+	matrix, headers, planted := GeneratePlantedCover(cols, rows, density, seed)
+	want := append([]int(nil), planted...)
+	sort.Ints(want)
+	s := NewSolver(matrix, headers, opts...)
+	seen := map[string]bool{}
+	found := false
+	for _, O := range s.SolveAll() {
+		if err := s.CheckRows(O.Rows()); err != nil {
+			return fmt.Errorf("solution %v: %w", O.Rows(), err)
+		}
+		key := O.Key()
+		if seen[key] {
+			return fmt.Errorf("solution %v reported twice", O.Rows())
+		}
+		seen[key] = true
+		found = found || fmt.Sprint(O.Rows()) == fmt.Sprint(want)
+	}
+	if !found {
+		return fmt.Errorf("planted solution %v not found among %v", planted, len(seen))
+	}
+	n := NewSolver(matrix, headers, opts...).Count()
+	if n.Cmp(big.NewInt(int64(len(seen)))) != 0 {
+		return fmt.Errorf("count is %v, enumeration finds %v", n, len(seen))
+	}
+	return nil
+}
+
+// Checks the planted instance the bytes map to, see PlantedParams() and
+// CheckPlanted(). Meant for fuzz targets of user code:
+//
+//	f.Fuzz(func(t *testing.T, data []byte) {
+//		if err := cover.CheckPlantedBytes(data, myOptions...); err != nil {
+//			t.Fatal(err)
+//		}
+//	})
+func CheckPlantedBytes(data []byte, opts ...Option) error {
+	cols, rows, density, seed := PlantedParams(data)
+	return CheckPlanted(cols, rows, density, seed, opts...)
+}
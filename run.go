@@ -0,0 +1,43 @@
+package cover
+
+import (
+	"time"
+)
+
+// Advances the search for about the time budget and tells whether it is
+// over, e.g. for a browser or a game loop to keep its UI responsive while
+// solving: the search steps one decision at a time, see Session.Step(), and
+// the next call resumes where the last one stopped. Once a solution is found,
+// it is appended to Solutions and the matrix left covered as by Solve(). A
+// search over without a solution leaves the matrix restored. Reset() stops a
+// search in progress and restores the matrix.
+func (s *Solver) Run(budget time.Duration) bool {
+	if s.run == nil {
+		s.matrix.ResetStats()
+		s.run = s.Session()
+		if s.run.Solved() {
+			return s.found()
+		}
+	}
+	deadline := time.Now().Add(budget)
+	for steps := 1; ; steps++ {
+		if !s.run.Step() {
+			s.run = nil
+			return true
+		}
+		if s.run.Solved() {
+			return s.found()
+		}
+		if steps%256 == 0 && time.Now().After(deadline) {
+			return false
+		}
+	}
+}
+
+// Records the solution of the session, ending the search.
+func (s *Solver) found() bool {
+	O := append(Solution{}, s.run.O...)
+	s.Solutions = append(s.Solutions, &O)
+	s.run = nil
+	return true
+}
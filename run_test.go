@@ -0,0 +1,43 @@
+package cover
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		matrix, headers, _ := GeneratePlantedCover(16, 40, 0.2, seed)
+		s := NewSolver(matrix, headers)
+		slices := 1
+		for !s.Run(0) {
+			slices++
+		}
+		if len(s.Solutions) != 1 {
+			t.Fatalf("Seed %v: %v solutions after %v slices", seed, len(s.Solutions), slices)
+		}
+		rows := make([]int, s.Solutions[0].Len())
+		for i, r := range *s.Solutions[0] {
+			rows[i] = r.Row
+		}
+		s.Reset()
+		if err := s.CheckRows(rows); err != nil {
+			t.Errorf("Seed %v: %v", seed, err)
+		}
+		if cols := s.matrix.ColCount(); cols != len(headers) {
+			t.Errorf("Seed %v: matrix has %v columns after reset (wants %v)", seed, cols, len(headers))
+		}
+	}
+	// an unsolvable problem ends restored, and a search in progress resets
+	s := NewSolver(oddPairs(13))
+	for !s.Run(time.Millisecond) {
+	}
+	if len(s.Solutions) != 0 || s.matrix.ColCount() != 13 {
+		t.Errorf("Unsolvable problem: %v solutions, %v columns", len(s.Solutions), s.matrix.ColCount())
+	}
+	s.Run(0)
+	s.Reset()
+	if err := s.matrix.Validate(); err != nil || s.matrix.ColCount() != 13 {
+		t.Errorf("Reset in progress: %v columns, %v", s.matrix.ColCount(), err)
+	}
+}
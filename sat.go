@@ -0,0 +1,203 @@
+package cover
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"sync/atomic"
+)
+
+// Search algorithm used by Solver.Solve().
+type Backend int
+
+const (
+	// Dancing links, the default.
+	DLX Backend = iota
+	// SAT solver given to WithSAT(), on the CNF() encoding.
+	SAT
+	// Both at once, the first one to finish giving the solution.
+	Race
+)
+
+func (b Backend) String() string {
+	switch b {
+	case DLX:
+		return "dlx"
+	case SAT:
+		return "sat"
+	case Race:
+		return "race"
+	}
+	return "Backend(" + strconv.Itoa(int(b)) + ")"
+}
+
+// Boolean formula in conjunctive normal form. Variable v, from 1 to Vars,
+// appears as the literal v or as its negation -v.
+type CNF struct {
+	Vars    int
+	Clauses [][]int
+}
+
+// Encodes the remaining problem as a formula whose variable i+1 tells whether
+// the row of index i is chosen. Each primary column gets a clause requiring
+// one of its rows, and each pair of rows sharing a column, unless they give
+// it the same color, a clause forbidding both. Rows no longer in the matrix
+// are forced false.
+func (m *SparseMatrix) CNF() *CNF {
+	c := &CNF{Vars: m.rowCount()}
+	active := make([]bool, c.Vars)
+	secondary := m.secondaryCols()
+	m.forEachCol(func(col *Node) {
+		rows := make([]int, 0, col.Size)
+		for r := col.Down; r != col; r = r.Down {
+			active[r.Row] = true
+			rows = append(rows, r.Row+1)
+		}
+		if !secondary[col] {
+			c.Clauses = append(c.Clauses, rows)
+		}
+		for a := col.Down; a != col; a = a.Down {
+			for b := a.Down; b != col; b = b.Down {
+				if !agree(a, b) {
+					c.Clauses = append(c.Clauses, []int{-(a.Row + 1), -(b.Row + 1)})
+				}
+			}
+		}
+	})
+	for i, ok := range active {
+		if !ok {
+			c.Clauses = append(c.Clauses, []int{-(i + 1)})
+		}
+	}
+	return c
+}
+
+// Writes the formula in the DIMACS format read by SAT solvers.
+func (c *CNF) WriteDIMACS(w io.Writer) error {
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(b, "p cnf %v %v\n", c.Vars, len(c.Clauses))
+	for _, clause := range c.Clauses {
+		for _, l := range clause {
+			fmt.Fprint(b, l, " ")
+		}
+		fmt.Fprintln(b, 0)
+	}
+	return b.Flush()
+}
+
+// Solver of boolean formulas, to plug an external SAT solver in.
+type SATSolver interface {
+	// Returns a model indexed by variable, nil if the formula is unsatisfiable,
+	// or the error of the context once it is done.
+	Solve(ctx context.Context, c *CNF) ([]bool, error)
+}
+
+// Returns the first node of each remaining row, by index.
+func (m *SparseMatrix) rowNodes() map[int]*Node {
+	nodes := map[int]*Node{}
+	m.forEachCol(func(col *Node) {
+		for r := col.Down; r != col; r = r.Down {
+			if _, ok := nodes[r.Row]; !ok {
+				nodes[r.Row] = r
+			}
+		}
+	})
+	return nodes
+}
+
+// Translates a model of the formula of CNF() back to rows.
+func modelRows(model []bool, nodes map[int]*Node) *Solution {
+	O := Solution{}
+	for v := 1; v < len(model); v++ {
+		if model[v] {
+			O = append(O, nodes[v-1])
+		}
+	}
+	return &O
+}
+
+// Searches a solution with a SAT solver instead of the dancing links, which
+// some pathological instances suit better. The matrix is left untouched.
+// Returns an empty solution if there is none.
+func (s *Solver) SolveSAT(ctx context.Context, sat SATSolver) (*Solution, error) {
+	model, err := sat.Solve(ctx, s.matrix.CNF())
+	if err != nil {
+		return nil, err
+	}
+	return modelRows(model, s.matrix.rowNodes()), nil
+}
+
+// Guesser keeping a copy of the first solution, and aborting the search once
+// found or once stopped from another goroutine.
+type racer struct {
+	matrix   *SparseMatrix
+	stop     *atomic.Bool
+	solution *Solution
+}
+
+func (r *racer) ChooseCol(k int) *Node {
+	if r.stop.Load() {
+		r.matrix.Abort()
+	}
+	return r.matrix.SmallestCol()
+}
+func (r *racer) Eureka(O *Solution) {
+	s := append(Solution{}, *O...)
+	r.solution = &s
+	r.matrix.Abort()
+}
+func (r *racer) Terminate() bool {
+	return false
+}
+
+// Races the dancing links against the SAT solver, and returns the solution of
+// the first one to finish, the other one being stopped. Unlike Solve(), the
+// matrix is restored afterwards. Returns an empty solution if there is none.
+func (s *Solver) SolveRace(sat SATSolver) *Solution {
+	m := s.matrix
+	cnf := m.CNF()
+	nodes := m.rowNodes()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var stop atomic.Bool
+	done := make(chan *Solution, 1)
+	go func() {
+		model, err := sat.Solve(ctx, cnf)
+		if err != nil {
+			done <- nil
+			return
+		}
+		stop.Store(true)
+		done <- modelRows(model, nodes)
+	}()
+	m.ResetStats()
+	r := &racer{matrix: m, stop: &stop}
+	m.Search(m.NewSolution(), 0, r)
+	if r.solution != nil || !stop.Load() {
+		cancel()
+		<-done
+		if r.solution == nil {
+			return &Solution{}
+		}
+		return r.solution
+	}
+	return <-done
+}
+
+// Solves with the SAT solver or the race chosen by WithSAT(), then includes
+// the rows of the solution as Solve() does, so that Reset() restores them.
+func (s *Solver) solveWith(b Backend) *Solution {
+	var found *Solution
+	if b == Race {
+		found = s.SolveRace(s.sat)
+	} else {
+		found, _ = s.SolveSAT(context.Background(), s.sat)
+	}
+	O := s.matrix.NewSolution()
+	if _, err := s.matrix.Require(O, *found...); err == nil && O.Len() > 0 {
+		s.Solutions = append(s.Solutions, O)
+	}
+	return O
+}
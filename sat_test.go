@@ -0,0 +1,90 @@
+package cover
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+)
+
+// Checks that the rows of O cover each of the n columns exactly once.
+func checkCover(t *testing.T, O *Solution, n int) {
+	covered := map[*Node]int{}
+	for _, r := range *O {
+		covered[r.Col]++
+		for j := r.Right; j != r; j = j.Right {
+			covered[j.Col]++
+		}
+	}
+	if len(covered) != n {
+		t.Errorf("Solution covers %v columns (wants %v)", len(covered), n)
+	}
+	for c, k := range covered {
+		if k != 1 {
+			t.Errorf("Column %v covered %v times", c.Name, k)
+		}
+	}
+}
+
+func TestWriteDIMACS(t *testing.T) {
+	m := NewSparseMatrix([][]int{{1, 1}, {0, 1}}, []string{"A", "B"})
+	var b bytes.Buffer
+	if err := m.CNF().WriteDIMACS(&b); err != nil {
+		t.Fatal(err)
+	}
+	if want := "p cnf 2 3\n1 0\n1 2 0\n-1 -2 0\n"; b.String() != want {
+		t.Errorf("DIMACS is\n%v(wants\n%v)", b.String(), want)
+	}
+}
+
+func TestSolveSAT(t *testing.T) {
+	for _, b := range []Backend{SAT, Race} {
+		s := NewSolver(knuth(), []string{"A", "B", "C", "D", "E", "F", "G"}, WithSAT(nil, b))
+		O := s.Solve()
+		checkCover(t, O, 7)
+		if len(s.Solutions) != 1 {
+			t.Errorf("Backend %v keeps %v solutions (wants %v)", b, len(s.Solutions), 1)
+		}
+		if s.matrix.ColCount() != 0 {
+			t.Errorf("Backend %v leaves %v columns after Solve()", b, s.matrix.ColCount())
+		}
+		s.Reset()
+		if s.matrix.ColCount() != 7 {
+			t.Errorf("Backend %v leaves %v columns after Reset()", b, s.matrix.ColCount())
+		}
+	}
+	for seed := int64(0); seed < 5; seed++ {
+		matrix, headers, _ := GeneratePlantedCover(20, 80, 0.15, seed)
+		O, err := NewSolver(matrix, headers).SolveSAT(context.Background(), DPLL{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkCover(t, O, 20)
+	}
+	// column B cannot be covered
+	s := NewSolver([][]int{{1, 0}, {1, 0}}, []string{"A", "B"})
+	if O, _ := s.SolveSAT(context.Background(), DPLL{}); O.Len() != 0 {
+		t.Errorf("Uncoverable column has a SAT solution")
+	}
+	if O := s.SolveRace(DPLL{}); O.Len() != 0 {
+		t.Errorf("Uncoverable column has a raced solution")
+	}
+}
+
+func TestSolveSATColors(t *testing.T) {
+	s, err := coloredSpec(t).Solver()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the rows giving x the color r do not exclude each other
+	if O, err := s.SolveSAT(context.Background(), DPLL{}); err != nil || fmt.Sprint(O.Rows()) != "[0 1]" {
+		t.Errorf("SAT solution is %v, %v (wants [0 1])", O.Rows(), err)
+	}
+	s, err = coloredSpec(t).Solver(WithSAT(DPLL{}, SAT))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if O := s.Solve(); fmt.Sprint(O.Rows()) != "[0 1]" {
+		t.Errorf("SAT backend solution is %v (wants [0 1])", O.Rows())
+	}
+}
@@ -0,0 +1,104 @@
+package cover
+
+import (
+	"errors"
+)
+
+// Error returned when toggling a column which is not in the matrix.
+var ErrNoColumn = errors.New("no such column")
+
+// Turns the column into a secondary one, which a solution covers at most
+// once instead of exactly once. The search never branches on it, so it is
+// linked in a list of its own rather than in the headers.
+func (m *SparseMatrix) setSecondary(c *Node) {
+	if c.bucket != nil {
+		c.bucket.remove(c)
+		c.bucket = nil
+	}
+	if m.secondary == nil {
+		m.secondary = &Node{Meta: Meta{Name: "secondary"}}
+		m.secondary.Left = m.secondary
+		m.secondary.Right = m.secondary
+	}
+	c.Right.Left = c.Left
+	c.Left.Right = c.Right
+	c.Left = c
+	c.Right = c
+	m.secondary.RowAppend(c)
+}
+
+// Turns the secondary column back into a primary one, linked after the other
+// primary columns.
+func (m *SparseMatrix) setPrimary(c *Node) {
+	c.Right.Left = c.Left
+	c.Left.Right = c.Right
+	c.Left = c
+	c.Right = c
+	m.Root().RowAppend(c)
+	if m.sizes != nil {
+		for int(c.Size) >= len(m.sizes.heads) {
+			m.sizes.heads = append(m.sizes.heads, nil)
+		}
+		c.bucket = &bucket{index: m.sizes}
+		c.bucket.insert(c)
+	}
+}
+
+// Makes the named columns secondary, so that solutions cover them at most
+// once, e.g. to relax the constraints of a sudoku without rebuilding its
+// matrix. It must be called between searches. Columns already secondary are
+// left as is. Returns ErrNoColumn at the first unknown name, the columns
+// named before it being toggled.
+func (m *SparseMatrix) SetSecondary(names ...string) error {
+	secondary := m.secondaryCols()
+	for _, name := range names {
+		c := m.findCol(name)
+		if c == nil {
+			return ErrNoColumn
+		}
+		if !secondary[c] {
+			m.setSecondary(c)
+		}
+	}
+	return nil
+}
+
+// Makes the named columns primary again, see SetSecondary(). They are moved
+// after the other primary columns, which may change the search order.
+func (m *SparseMatrix) SetPrimary(names ...string) error {
+	secondary := m.secondaryCols()
+	for _, name := range names {
+		c := m.findCol(name)
+		if c == nil {
+			return ErrNoColumn
+		}
+		if secondary[c] {
+			m.setPrimary(c)
+		}
+	}
+	return nil
+}
+
+// Calls f with each remaining column, the primary ones first.
+func (m *SparseMatrix) forEachCol(f func(*Node)) {
+	root := m.Root()
+	for col := root.Right; col != root; col = col.Right {
+		f(col)
+	}
+	if m.secondary != nil {
+		for col := m.secondary.Right; col != m.secondary; col = col.Right {
+			f(col)
+		}
+	}
+}
+
+// Returns the set of the remaining secondary columns.
+func (m *SparseMatrix) secondaryCols() map[*Node]bool {
+	set := map[*Node]bool{}
+	if m.secondary != nil {
+		for col := m.secondary.Right; col != m.secondary; col = col.Right {
+			set[col] = true
+		}
+	}
+	return set
+}
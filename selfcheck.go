@@ -0,0 +1,120 @@
+package cover
+
+import (
+	"fmt"
+)
+
+// Walks the columns from the header, checking the links and the sizes. The
+// walk stops at the first violation, which is described by the error.
+func (m *SparseMatrix) check() error {
+	seen := map[*Node]bool{}
+	for _, head := range []*Node{m.Root(), m.secondary} {
+		if head == nil {
+			continue
+		}
+		for col := head.Right; col != head; col = col.Right {
+			if seen[col] {
+				return fmt.Errorf("column %v: met twice walking the headers", col.Name)
+			}
+			seen[col] = true
+			if col.Right.Left != col || col.Left.Right != col {
+				return fmt.Errorf("column %v: left and right links disagree with its neighbors", col.Name)
+			}
+			size := 0
+			for n := col.Down; n != col; n = n.Down {
+				if seen[n] {
+					return fmt.Errorf("column %v: row %v met twice", col.Name, n.Row)
+				}
+				seen[n] = true
+				if n.Col != col {
+					return fmt.Errorf("column %v: row %v points to column %v", col.Name, n.Row, n.Col.Name)
+				}
+				if n.Down.Up != n || n.Up.Down != n {
+					return fmt.Errorf("column %v: row %v has up and down links disagreeing with its neighbors", col.Name, n.Row)
+				}
+				if n.Right.Left != n || n.Left.Right != n {
+					return fmt.Errorf("column %v: row %v has left and right links disagreeing with its neighbors", col.Name, n.Row)
+				}
+				size++
+			}
+			if col.Down.Up != col || col.Up.Down != col {
+				return fmt.Errorf("column %v: up and down links disagree with its rows", col.Name)
+			}
+			if col.Size != uint(size) {
+				return fmt.Errorf("column %v: size %v but %v rows", col.Name, col.Size, size)
+			}
+		}
+	}
+	return nil
+}
+
+// Turns on or off the self-check mode, in which the links and sizes of the
+// whole matrix are checked after every uncover of the searches, which then
+// panic with a description of the first violation. This catches the
+// mistakes of code extending the matrix by hand or of new engines right
+// where they happen, at the cost of a search many times slower.
+func (m *SparseMatrix) SelfCheck(on bool) {
+	m.selfCheck = on
+}
+
+// Checks the invariants of the matrix: the four-way links of the headers and
+// of the rows agree with their neighbors, the nodes of each row form a cycle
+// over active columns and share their row index, the size of each column
+// counts its rows, and with size tracking, each primary column is in the
+// bucket of its size. Meant for tests of code building matrices, or after
+// loading one, on a matrix that no search has left covered. Returns an error
+// describing the first violation found.
+func (m *SparseMatrix) Validate() error {
+	if err := m.check(); err != nil {
+		return err
+	}
+	active := map[*Node]bool{}
+	m.forEachCol(func(col *Node) {
+		active[col] = true
+	})
+	var err error
+	m.forEachCol(func(col *Node) {
+		for r := col.Down; r != col && err == nil; r = r.Down {
+			n := 0
+			for j := r.Right; j != r && err == nil; j = j.Right {
+				switch {
+				case !active[j.Col]:
+					err = fmt.Errorf("row %v: node of covered column %v", r.Row, j.Col.Name)
+				case j.Row != r.Row:
+					err = fmt.Errorf("row %v: node of row %v in column %v", r.Row, j.Row, j.Col.Name)
+				case j.Down.Up != j || j.Up.Down != j:
+					err = fmt.Errorf("row %v: node of column %v not linked in the column", r.Row, j.Col.Name)
+				}
+				if n++; n > len(active) {
+					err = fmt.Errorf("row %v: nodes do not cycle back", r.Row)
+				}
+			}
+		}
+	})
+	if err != nil || m.sizes == nil {
+		return err
+	}
+	secondary := m.secondaryCols()
+	bucketed := 0
+	for size, head := range m.sizes.heads {
+		var prev *Node
+		for c := head; c != nil; c = c.bucket.next {
+			switch {
+			case c.Size != uint(size):
+				return fmt.Errorf("column %v: size %v in the bucket of size %v", c.Name, c.Size, size)
+			case c.bucket.prev != prev:
+				return fmt.Errorf("column %v: bucket links disagree", c.Name)
+			case !active[c] || secondary[c]:
+				return fmt.Errorf("column %v: in a bucket but not an active primary column", c.Name)
+			}
+			prev = c
+			if bucketed++; bucketed > len(active) {
+				return fmt.Errorf("bucket of size %v does not end", size)
+			}
+		}
+	}
+	if primary := m.ColCount(); bucketed != primary {
+		return fmt.Errorf("%v columns in buckets for %v primary columns", bucketed, primary)
+	}
+	return nil
+}
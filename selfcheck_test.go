@@ -0,0 +1,58 @@
+package cover
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelfCheck(t *testing.T) {
+	h := []string{"A", "B", "C", "D", "E", "F", "G"}
+	s := NewSolver(knuth(), h, WithSelfCheck())
+	if n := s.Count(); n.Int64() != 1 {
+		t.Errorf("Self-checked count is %v (wants %v)", n, 1)
+	}
+	// a hook corrupting the size of column A during the search
+	s.matrix.AddHook(func(e Event, col *Node, depth int) {
+		if e == UncoverEvent && col.Name == "A" {
+			col.Size += 2
+		}
+	})
+	defer func() {
+		r := recover()
+		if msg, ok := r.(string); !ok || !strings.Contains(msg, "column A: size") {
+			t.Errorf("Corrupted size panics with %v", r)
+		}
+	}()
+	s.Count()
+	t.Errorf("Corrupted size not detected")
+}
+
+func TestValidate(t *testing.T) {
+	rows, h := SudokuConstraintRows(4)
+	m := NewSparseMatrixFromRows(rows, h)
+	m.TrackSizes()
+	if err := m.SetSecondary("1,1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Validate(); err != nil {
+		t.Fatalf("Sudoku matrix is invalid: %v", err)
+	}
+	col := m.Root().Right
+	for _, c := range []struct {
+		corrupt, repair func()
+		want            string
+	}{
+		{func() { col.Size++ }, func() { col.Size-- }, "size"},
+		{func() { col.Down.Row++ }, func() { col.Down.Row-- }, "node of row"},
+		{func() { col.Down.Down.Up = col }, func() { col.Down.Down.Up = col.Down }, "up and down links"},
+	} {
+		c.corrupt()
+		if err := m.Validate(); err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("Corruption gives %v (wants %v)", err, c.want)
+		}
+		c.repair()
+	}
+	if err := m.Validate(); err != nil {
+		t.Errorf("Repaired matrix is invalid: %v", err)
+	}
+}
@@ -0,0 +1,101 @@
+package cover
+
+// Search driven one decision at a time, e.g. by a GUI animating the dancing
+// links or by a student stepping through the algorithm. The matrix can be
+// inspected between steps, and hooks fire as during a search.
+// The session owns the matrix until Reset() restores it.
+type Session struct {
+	matrix *SparseMatrix
+	// rows included so far, each one with its column covered
+	O Solution
+}
+
+// Starts a session over the matrix of the solver.
+func (s *Solver) Session() *Session {
+	return &Session{matrix: s.matrix, O: *s.matrix.NewSolution()}
+}
+
+// Returns the matrix, in its state between steps.
+func (s *Session) Matrix() *SparseMatrix {
+	return s.matrix
+}
+
+// Tells whether the included rows form a solution.
+func (s *Session) Solved() bool {
+	root := s.matrix.Root()
+	return root.Right == root
+}
+
+// Returns the rows the search would branch on next, i.e. the rows of the
+// smallest column. It is nil once solved, and empty at a dead end.
+func (s *Session) PeekChoices() []*Node {
+	if s.Solved() {
+		return nil
+	}
+	s.matrix.level = len(s.O)
+	return s.matrix.SmallestCol().ColNodes()
+}
+
+// Includes a row, given by any of its nodes, and covers its columns.
+// Returns ErrConflict if the row is no longer in the matrix.
+func (s *Session) Choose(r *Node) error {
+	if r.Col == nil || !isActive(r) {
+		return ErrConflict
+	}
+	k := len(s.O)
+	s.matrix.commit(r, k)
+	for j := r.Right; j != r; j = j.Right {
+		s.matrix.commit(j, k)
+	}
+	s.O = append(s.O, r)
+	return nil
+}
+
+// Reverts the last row included, uncovering its columns. Returns false if
+// there is no row to revert.
+func (s *Session) Undo() bool {
+	k := len(s.O) - 1
+	if k < 0 {
+		return false
+	}
+	r := s.O[k]
+	for j := r.Left; j != r; j = j.Left {
+		s.matrix.uncommit(j, k)
+	}
+	s.matrix.uncommit(r, k)
+	s.O = s.O[:k]
+	return true
+}
+
+// Takes the next decision of the DLX algorithm: includes the first row of the
+// smallest column or, once solved or at a dead end, backtracks to the next
+// row of the last decision having one left. Stepping over and over visits the
+// rows in the order of Search(). Returns false once the tree is exhausted,
+// leaving the matrix restored.
+func (s *Session) Step() bool {
+	if choices := s.PeekChoices(); len(choices) > 0 {
+		s.Choose(choices[0])
+		return true
+	}
+	for len(s.O) > 0 {
+		r := s.O[len(s.O)-1]
+		s.Undo()
+		if next := r.Down; next != r.Col {
+			s.Choose(next)
+			return true
+		}
+	}
+	return false
+}
+
+// Returns a copy of the rows included so far.
+func (s *Session) Solution() *Solution {
+	O := append(Solution{}, s.O...)
+	return &O
+}
+
+// Reverts all the rows included, restoring the matrix.
+func (s *Session) Reset() {
+	for s.Undo() {
+	}
+}
@@ -0,0 +1,109 @@
+package cover
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSessionStep(t *testing.T) {
+	for seed := int64(0); seed < 5; seed++ {
+		matrix, headers, _ := GeneratePlantedCover(12, 40, 0.25, seed)
+		solver := NewSolver(matrix, headers)
+		want := len(solver.SolveAll())
+		s := solver.Session()
+		found := 0
+		for s.Step() {
+			if s.Solved() {
+				found++
+			}
+		}
+		if found != want {
+			t.Errorf("Stepping seed %v finds %v solutions (wants %v)", seed, found, want)
+		}
+		if cols := solver.matrix.ColCount(); cols != len(headers) {
+			t.Errorf("Matrix has %v columns after stepping (wants %v)", cols, len(headers))
+		}
+	}
+}
+
+func TestSessionChoose(t *testing.T) {
+	s := NewSolver(knuth(), []string{"A", "B", "C", "D", "E", "F", "G"}).Session()
+	choices := s.PeekChoices()
+	if len(choices) != 2 || choices[0].Col.Name != "A" {
+		t.Fatalf("Choices are %v", choices)
+	}
+	// the row A D G leads to a dead end
+	if err := s.Choose(choices[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Choose(choices[1]); err != ErrConflict {
+		t.Errorf("Choosing a conflicting row returns %v (wants %v)", err, ErrConflict)
+	}
+	s.Undo()
+	if err := s.Choose(choices[1]); err != nil {
+		t.Fatal(err)
+	}
+	for !s.Solved() && len(s.PeekChoices()) > 0 {
+		s.Choose(s.PeekChoices()[0])
+	}
+	if !s.Solved() || s.Solution().Len() != 3 {
+		t.Errorf("Session ends with %v", s.Solution())
+	}
+	s.Reset()
+	if cols := s.Matrix().ColCount(); cols != 7 {
+		t.Errorf("Matrix has %v columns after reset (wants %v)", cols, 7)
+	}
+}
+
+func TestSessionWhyNot(t *testing.T) {
+	s := NewSolver(knuth(), []string{"A", "B", "C", "D", "E", "F", "G"}).Session()
+	// the row A D
+	if err := s.Choose(s.PeekChoices()[1]); err != nil {
+		t.Fatal(err)
+	}
+	if e := s.WhyNot(3); !e.Chosen {
+		t.Errorf("Chosen row: %v", e)
+	}
+	// the rows A D G and D E G conflict on D, the first one on A too
+	e := s.WhyNot(1)
+	if len(e.Conflicts) != 2 || e.Conflicts[0].Column != "A" || e.Conflicts[1].Column != "D" || e.Available {
+		t.Errorf("Row A D G: %v", e)
+	}
+	if e := s.WhyNot(5); len(e.Conflicts) != 1 || e.Conflicts[0].By.Row != 3 {
+		t.Errorf("Row D E G: %v", e)
+	}
+	if e := s.WhyNot(4); !e.Available || len(e.Conflicts) != 0 {
+		t.Errorf("Row B G: %v", e)
+	}
+	if e := s.WhyNot(9); e.Available || e.String() != "row 9 is not in the matrix" {
+		t.Errorf("Unknown row: %v", e)
+	}
+}
+
+func TestSessionWhyNotColors(t *testing.T) {
+	spec, err := LoadSpec(strings.NewReader(`{
+		"items": [{"name": "p"}, {"name": "q"}, {"name": "x", "secondary": true, "colors": ["A", "B"]}],
+		"options": [{"items": ["p", "x:B"]}, {"items": ["q", "x:A"]}, {"items": ["q", "x:B"]}, {"items": ["q", "x"]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	solver, err := spec.Solver()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := solver.Session()
+	if err := s.Choose(s.Matrix().Col("p").Down); err != nil {
+		t.Fatal(err)
+	}
+	for row, conflict := range []string{"", "x", "", "x"} {
+		e := s.WhyNot(row)
+		switch {
+		case row == 0 && !e.Chosen:
+			t.Errorf("Chosen row: %v", e)
+		case conflict == "" && row > 0 && !e.Available:
+			t.Errorf("Row %v agreeing on the color: %v", row, e)
+		case conflict != "" && (len(e.Conflicts) != 1 || e.Conflicts[0].Column != conflict):
+			t.Errorf("Row %v: %v (wants a conflict on %v)", row, e, conflict)
+		}
+	}
+}
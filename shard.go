@@ -0,0 +1,113 @@
+package cover
+
+import (
+	"encoding/json"
+	"io"
+	"math/big"
+)
+
+// Progress of an enumeration split into the subtrees rooted at a given depth
+// of the search tree. Subtrees are numbered in the order the search reaches
+// them, which only depends on the matrix and on its options, so that they can
+// be counted by different processes or machines and merged afterwards.
+// A checkpoint is saved as JSON to resume the enumeration after a restart.
+type Checkpoint struct {
+	Depth int
+	// solution count of each finished subtree, by index
+	Counts map[int]*big.Int
+}
+
+// Creates an empty checkpoint for subtrees rooted at the given depth.
+func NewCheckpoint(depth int) *Checkpoint {
+	return &Checkpoint{Depth: depth, Counts: map[int]*big.Int{}}
+}
+
+// Reads a checkpoint saved by Save().
+func LoadCheckpoint(r io.Reader) (*Checkpoint, error) {
+	c := NewCheckpoint(0)
+	if err := json.NewDecoder(r).Decode(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Writes the checkpoint as JSON.
+func (c *Checkpoint) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(c)
+}
+
+// Adds the subtrees finished in another checkpoint of the same depth.
+func (c *Checkpoint) Merge(o *Checkpoint) {
+	for i, n := range o.Counts {
+		c.Counts[i] = n
+	}
+}
+
+// Returns the indexes below n of the subtrees not finished yet.
+func (c *Checkpoint) Missing(n int) []int {
+	missing := make([]int, 0)
+	for i := 0; i < n; i++ {
+		if _, ok := c.Counts[i]; !ok {
+			missing = append(missing, i)
+		}
+	}
+	return missing
+}
+
+// Returns the number of solutions of the finished subtrees.
+func (c *Checkpoint) Total() *big.Int {
+	total := new(big.Int)
+	for _, n := range c.Counts {
+		total.Add(total, n)
+	}
+	return total
+}
+
+// Walks the search tree down to the given depth, calling visit with the index
+// of each subtree rooted there, or at a solution found above it.
+func (m *SparseMatrix) walkSubtrees(O *Solution, k, depth int, next *int, visit func(int, int)) {
+	root := m.Root()
+	if k == depth || root.Right == root {
+		visit(*next, k)
+		*next++
+		return
+	}
+	c := m.SmallestCol()
+	m.cover(c, k)
+	for r := c.Down; r != c; r = r.Down {
+		O.Set(k, r)
+		for j := r.Right; j != r; j = j.Right {
+			m.commit(j, k)
+		}
+		if !m.DeadEnd() {
+			m.walkSubtrees(O, k+1, depth, next, visit)
+		}
+		for j := r.Left; j != r; j = j.Left {
+			m.uncommit(j, k)
+		}
+	}
+	m.uncover(c, k)
+}
+
+// Returns the number of subtrees rooted at the given depth.
+func (s *Solver) Subtrees(depth int) int {
+	n := 0
+	s.matrix.walkSubtrees(s.matrix.NewSolution(), 0, depth, &n, func(int, int) {})
+	return n
+}
+
+// Counts the solutions of the subtrees of index lo to hi excluded, skipping
+// the ones already finished in the checkpoint, which records the others.
+// The caller can save the checkpoint after each call to resume later on.
+func (s *Solver) CountSubtrees(c *Checkpoint, lo, hi int) {
+	n := 0
+	O := s.matrix.NewSolution()
+	s.matrix.walkSubtrees(O, 0, c.Depth, &n, func(i, k int) {
+		if _, done := c.Counts[i]; done || i < lo || i >= hi {
+			return
+		}
+		count := &counter{matrix: s.matrix, count: new(big.Int)}
+		s.matrix.Search(O, k, count)
+		c.Counts[i] = count.count
+	})
+}
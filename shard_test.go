@@ -0,0 +1,35 @@
+package cover
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCountSubtrees(t *testing.T) {
+	m, h := GraecoLatinConstraintMatrix(3)
+	solver := NewSolver(m, h)
+	n := solver.Subtrees(2)
+	if n < 2 {
+		t.Fatalf("Search tree has %v subtrees at depth 2", n)
+	}
+	// two machines share the subtrees, the first one being restarted midway
+	first, second := NewCheckpoint(2), NewCheckpoint(2)
+	NewSolver(m, h).CountSubtrees(first, 0, n/4)
+	var saved bytes.Buffer
+	if err := first.Save(&saved); err != nil {
+		t.Fatal(err)
+	}
+	resumed, err := LoadCheckpoint(&saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	NewSolver(m, h).CountSubtrees(resumed, 0, n/2)
+	NewSolver(m, h).CountSubtrees(second, n/2, n)
+	resumed.Merge(second)
+	if missing := resumed.Missing(n); len(missing) != 0 {
+		t.Errorf("Subtrees %v are not counted", missing)
+	}
+	if total := resumed.Total(); total.Int64() != 72 {
+		t.Errorf("Subtrees hold %v solutions (wants %v)", total, 72)
+	}
+}
@@ -0,0 +1,151 @@
+package cover
+
+import (
+	"fmt"
+)
+
+// Outcome of Simplify(), as row indexes.
+type Simplification struct {
+	// rows in every solution
+	Forced []int
+	// rows in no solution, hidden from the matrix
+	Dead []int
+	// true when propagation alone proves there is no solution
+	Infeasible bool
+	// rows and primary columns left for the search to decide
+	Rows, Cols int
+}
+
+func (s *Simplification) String() string {
+	if s.Infeasible {
+		return "no solution"
+	}
+	return fmt.Sprintf("%v forced rows, %v dead rows, %v rows and %v columns left", len(s.Forced), len(s.Dead), s.Rows, s.Cols)
+}
+
+// Removes a row from its columns. Rows must be restored in reverse order, on
+// the matrix as it was when hidden.
+func (m *SparseMatrix) hideRow(r *Node) {
+	for _, n := range r.RowNodes() {
+		n.Down.Up = n.Up
+		n.Up.Down = n.Down
+		if b := n.Col.bucket; b != nil {
+			b.remove(n.Col)
+			n.Col.Size--
+			b.insert(n.Col)
+		} else {
+			n.Col.Size--
+		}
+	}
+	m.hidden = append(m.hidden, r)
+}
+
+// Puts back the last row hidden.
+func (m *SparseMatrix) unhideRow() {
+	nodes := m.hidden[len(m.hidden)-1].RowNodes()
+	for j := len(nodes) - 1; j >= 0; j-- {
+		n := nodes[j]
+		if b := n.Col.bucket; b != nil {
+			b.remove(n.Col)
+			n.Col.Size++
+			b.insert(n.Col)
+		} else {
+			n.Col.Size++
+		}
+		n.Down.Up = n
+		n.Up.Down = n
+	}
+	m.hidden = m.hidden[:len(m.hidden)-1]
+}
+
+// Puts back the rows hidden by Simplify(), in reverse order.
+func (m *SparseMatrix) RestoreRows() {
+	for len(m.hidden) > 0 {
+		m.unhideRow()
+	}
+}
+
+// Tells whether including the row leads unit propagation to a dead end.
+func (m *SparseMatrix) probe(O *Solution, k int, r *Node) bool {
+	O.Set(k, r)
+	m.cover(r.Col, k)
+	for j := r.Right; j != r; j = j.Right {
+		m.commit(j, k)
+	}
+	n := m.Propagate(O, k+1)
+	dead := m.DeadEnd()
+	m.Unpropagate(O, k+1, n)
+	for j := r.Left; j != r; j = j.Left {
+		m.uncommit(j, k)
+	}
+	m.uncover(r.Col, k)
+	return dead
+}
+
+// Determines up front the rows in every solution, which unit propagation
+// forces, and the rows in no solution, which conflict with the forced rows or
+// lead propagation to a dead end. The dead rows are hidden until RestoreRows(),
+// and unit propagation is turned on so that every search starts with the
+// forced rows. Hiding rows may force others, so this goes on until nothing
+// changes. The report tells how much of the problem is decided before search.
+func (m *SparseMatrix) Simplify() *Simplification {
+	s := &Simplification{Forced: []int{}, Dead: []int{}}
+	rows := make([]*Node, 0)
+	seen := map[int]bool{}
+	m.forEachCol(func(col *Node) {
+		for r := col.Down; r != col; r = r.Down {
+			if !seen[r.Row] {
+				seen[r.Row] = true
+				rows = append(rows, r)
+			}
+		}
+	})
+	O := m.NewSolution()
+	for {
+		n := m.Propagate(O, 0)
+		if m.DeadEnd() {
+			m.Unpropagate(O, 0, n)
+			s.Infeasible = true
+			break
+		}
+		forced := map[int]bool{}
+		for _, r := range (*O)[:n] {
+			forced[r.Row] = true
+		}
+		dead := make([]*Node, 0)
+		live := rows[:0]
+		for _, r := range rows {
+			switch {
+			case forced[r.Row]:
+				live = append(live, r)
+			case !isActive(r) || m.probe(O, n, r):
+				dead = append(dead, r)
+			default:
+				live = append(live, r)
+			}
+		}
+		rows = live
+		s.Forced = s.Forced[:0]
+		for _, r := range (*O)[:n] {
+			s.Forced = append(s.Forced, r.Row)
+		}
+		s.Cols = m.ColCount()
+		m.Unpropagate(O, 0, n)
+		for _, r := range dead {
+			m.hideRow(r)
+			s.Dead = append(s.Dead, r.Row)
+		}
+		if len(dead) == 0 {
+			s.Rows = len(rows) - len(s.Forced)
+			break
+		}
+	}
+	m.ResetStats()
+	m.units = true
+	return s
+}
+
+// Simplifies the problem before search, see SparseMatrix.Simplify().
+func (s *Solver) Simplify() *Simplification {
+	return s.matrix.Simplify()
+}
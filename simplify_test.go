@@ -0,0 +1,40 @@
+package cover
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSimplify(t *testing.T) {
+	solver := NewSolver(knuth(), []string{"A", "B", "C", "D", "E", "F", "G"})
+	s := solver.Simplify()
+	// A D G, B C F and D E G each lead propagation to a dead end, which
+	// leaves a single row for each of A, B and E
+	if fmt.Sprint(s.Forced, s.Dead) != "[3 4 0] [1 2 5]" || s.Rows != 0 || s.Cols != 0 {
+		t.Errorf("Knuth example simplifies to %+v", s)
+	}
+	if O := solver.Solve(); O.Len() != 3 {
+		t.Errorf("Simplified search finds %v", O)
+	}
+	if stats := solver.Stats(); stats.Candidates() != 0 {
+		t.Errorf("Simplified search branches on %v rows", stats.Candidates())
+	}
+	solver.Reset()
+	solver.matrix.RestoreRows()
+	if n := solver.Count(); n.Int64() != 1 {
+		t.Errorf("Restored matrix has %v solutions (wants %v)", n, 1)
+	}
+	for seed := int64(0); seed < 10; seed++ {
+		matrix, headers, _ := GeneratePlantedCover(20, 60, 0.15, seed)
+		solver := NewSolver(matrix, headers)
+		want := solver.Count()
+		solver.Simplify()
+		if n := solver.Count(); n.Cmp(want) != 0 {
+			t.Errorf("Seed %v: simplified problem has %v solutions (wants %v)", seed, n, want)
+		}
+		solver.matrix.RestoreRows()
+		if n := solver.Count(); n.Cmp(want) != 0 {
+			t.Errorf("Seed %v: restored problem has %v solutions (wants %v)", seed, n, want)
+		}
+	}
+}
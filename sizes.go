@@ -0,0 +1,65 @@
+package cover
+
+// Links a column to its neighbours having the same size.
+type bucket struct {
+	prev, next *Node
+	index      *sizeIndex
+}
+
+// Removes the column from the bucket of its current size.
+func (b *bucket) remove(c *Node) {
+	if b.prev != nil {
+		b.prev.bucket.next = b.next
+	} else {
+		b.index.heads[c.Size] = b.next
+	}
+	if b.next != nil {
+		b.next.bucket.prev = b.prev
+	}
+}
+
+// Inserts the column at the head of the bucket of its current size.
+func (b *bucket) insert(c *Node) {
+	head := b.index.heads[c.Size]
+	b.prev = nil
+	b.next = head
+	if head != nil {
+		head.bucket.prev = c
+	}
+	b.index.heads[c.Size] = c
+}
+
+// Active columns grouped by size.
+type sizeIndex struct {
+	heads []*Node
+}
+
+// Returns the first column of the smallest non empty bucket. Sizes only
+// shrink during the search, so the lookup stops early in practice.
+func (s *sizeIndex) smallest() *Node {
+	for _, c := range s.heads {
+		if c != nil {
+			return c
+		}
+	}
+	return nil
+}
+
+// Groups the columns by size so that SmallestCol() no longer scans all the
+// headers. Each size update then costs a few more pointer writes, which pays
+// off on matrices having many columns like sudoku ones.
+func (m *SparseMatrix) TrackSizes() {
+	root := m.Root()
+	max := uint(0)
+	for col := root.Right; col != root; col = col.Right {
+		if col.Size > max {
+			max = col.Size
+		}
+	}
+	m.sizes = &sizeIndex{heads: make([]*Node, max+1)}
+	// insert backwards so that ties are broken in header order
+	for col := root.Left; col != root; col = col.Left {
+		col.bucket = &bucket{index: m.sizes}
+		col.bucket.insert(col)
+	}
+}
@@ -0,0 +1,49 @@
+package cover
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Logs the following searches as structured records: the start and end of
+// each search at info level, and its covers, uncovers and solutions at debug
+// level, which are only built when the handler enables that level. A nil
+// logger stops the logging.
+func (m *SparseMatrix) SetLogger(l *slog.Logger) {
+	if m.logger == nil && l != nil {
+		m.AddHook(func(e Event, col *Node, depth int) {
+			if m.logDebug {
+				m.logSearch.LogAttrs(context.Background(), slog.LevelDebug, "dlx",
+					slog.String("event", e.String()), slog.String("column", col.Name),
+					slog.Int("depth", depth), slog.Int64("nodes", m.tried))
+			}
+		})
+	}
+	m.logger = l
+	m.logDebug = false
+}
+
+// Logs the start of a search, checking once whether debug records are
+// wanted since the handler level may change between searches. The records
+// of the search carry the name and tags of the problem.
+func (m *SparseMatrix) logStart() {
+	ctx := context.Background()
+	m.logSearch = m.logger.With(m.metaAttrs()...)
+	m.logDebug = m.logger.Enabled(ctx, slog.LevelDebug)
+	m.logSearch.LogAttrs(ctx, slog.LevelInfo, "search started",
+		slog.Int("columns", m.ColCount()), slog.String("heuristic", m.heuristic()))
+}
+
+// Logs a solution found by the search.
+func (m *SparseMatrix) logSolution(O *Solution) {
+	m.logSearch.LogAttrs(context.Background(), slog.LevelDebug, "dlx",
+		slog.String("event", "solution"), slog.Any("rows", O.Rows()),
+		slog.Int64("nodes", m.tried))
+}
+
+// Logs the end of a search with its statistics.
+func (m *SparseMatrix) logEnd() {
+	m.logSearch.LogAttrs(context.Background(), slog.LevelInfo, "search ended",
+		slog.Int64("solutions", m.stats.Solutions), slog.Int64("nodes", m.stats.Candidates()),
+		slog.Bool("aborted", m.aborted), slog.Bool("limited", m.limited))
+}
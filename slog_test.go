@@ -0,0 +1,48 @@
+package cover
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+	var out bytes.Buffer
+	level := new(slog.LevelVar)
+	l := slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: level}))
+	s := NewSolver(knuth(), []string{"A", "B", "C", "D", "E", "F", "G"}, WithLogger(l))
+	s.SolveAll()
+	records := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(records) != 2 {
+		t.Fatalf("Info logs %v records (wants 2):\n%v", len(records), out.String())
+	}
+	var end map[string]interface{}
+	if err := json.Unmarshal([]byte(records[1]), &end); err != nil {
+		t.Fatal(err)
+	}
+	if end["msg"] != "search ended" || end["solutions"] != 1.0 {
+		t.Errorf("Last record is %v", records[1])
+	}
+
+	out.Reset()
+	level.Set(slog.LevelDebug)
+	s.SolveAll()
+	events := map[string]int{}
+	for _, r := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var rec map[string]interface{}
+		if err := json.Unmarshal([]byte(r), &rec); err != nil {
+			t.Fatal(err)
+		}
+		if e, ok := rec["event"].(string); ok {
+			events[e]++
+			if e != "solution" && (rec["column"] == nil || rec["depth"] == nil || rec["nodes"] == nil) {
+				t.Errorf("Record misses attributes: %v", r)
+			}
+		}
+	}
+	if events["cover"] == 0 || events["cover"] != events["uncover"] || events["solution"] != 1 {
+		t.Errorf("Debug logs %v events", events)
+	}
+}
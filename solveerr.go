@@ -0,0 +1,87 @@
+package cover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Returned when a search is exhausted without finding a solution.
+var ErrNoSolution = errors.New("no solution")
+
+// Returned when a search is aborted by its node or depth limit.
+var ErrLimitReached = errors.New("search limit reached")
+
+// Error of a failed solve, giving the context needed to act on it from the
+// logs of a batch: which problem, how large, and how far the search went.
+// The dimensions are zero when the solve failed before building a matrix.
+// The cause, e.g. ErrNoSolution or the error of a context, is unwrapped.
+type SolveError struct {
+	// name of the problem, see SetName(), empty if unknown
+	Problem    string
+	Rows, Cols int
+	// deepest level the search branched at, and rows tried by branching
+	Depth int
+	Nodes int64
+	Err   error
+}
+
+func (e *SolveError) Error() string {
+	name := e.Problem
+	if name == "" {
+		name = "problem"
+	}
+	if e.Rows == 0 && e.Cols == 0 {
+		// failed before building the matrix, e.g. on invalid input
+		return fmt.Sprintf("%v: %v", name, e.Err)
+	}
+	return fmt.Sprintf("%v (%vx%v): %v after %v nodes at depth %v", name, e.Rows, e.Cols, e.Err, e.Nodes, e.Depth)
+}
+
+func (e *SolveError) Unwrap() error {
+	return e.Err
+}
+
+// Wraps the cause of a failed search with the dimensions of the matrix and
+// the statistics of the search.
+func (m *SparseMatrix) solveError(err error) *SolveError {
+	depth := len(m.stats.Levels) - 1
+	if depth < 0 {
+		depth = 0
+	}
+	return &SolveError{
+		Problem: m.name,
+		Rows:    m.rowCount(),
+		Cols:    m.cols,
+		Depth:   depth,
+		Nodes:   m.stats.Candidates(),
+		Err:     err,
+	}
+}
+
+// Same as Solve(), but aborts the search once the context is done, and fails
+// with a *SolveError wrapping the error of the context, ErrLimitReached or
+// ErrNoSolution. The matrix is restored when there is no solution.
+func (s *Solver) SolveContext(ctx context.Context) (*Solution, error) {
+	m := s.matrix
+	m.ctx = ctx
+	defer func() {
+		m.ctx = nil
+	}()
+	if err := ctx.Err(); err != nil {
+		m.ResetStats()
+		return m.NewSolution(), m.solveError(err)
+	}
+	O := s.Solve()
+	if m.stats.Solutions > 0 {
+		return O, nil
+	}
+	err := ErrNoSolution
+	switch {
+	case m.limited:
+		err = ErrLimitReached
+	case m.aborted && ctx.Err() != nil:
+		err = ctx.Err()
+	}
+	return O, m.solveError(err)
+}
@@ -0,0 +1,64 @@
+package cover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// Pairs of n columns, which have no exact cover when n is odd but take many
+// nodes to refute.
+func oddPairs(n int) ([][]int, []string) {
+	headers := make([]string, n)
+	for i := range headers {
+		headers[i] = fmt.Sprint(i)
+	}
+	rows := make([][]int, 0)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			row := make([]int, n)
+			row[i], row[j] = 1, 1
+			rows = append(rows, row)
+		}
+	}
+	return rows, headers
+}
+
+func TestSolveContext(t *testing.T) {
+	s := NewSolver(knuth(), []string{"A", "B", "C", "D", "E", "F", "G"})
+	if O, err := s.SolveContext(context.Background()); err != nil || O.Len() != 3 {
+		t.Errorf("Solves knuth with %v rows and error %v", O.Len(), err)
+	}
+
+	matrix, headers := oddPairs(13)
+	s = NewSolver(matrix, headers)
+	ctx, cancel := context.WithCancel(context.Background())
+	s.matrix.AddHook(func(e Event, col *Node, depth int) {
+		if s.matrix.tried > 1000 {
+			cancel()
+		}
+	})
+	_, err := s.SolveContext(ctx)
+	var serr *SolveError
+	if !errors.Is(err, context.Canceled) || !errors.As(err, &serr) {
+		t.Fatalf("Canceled solve fails with %v", err)
+	}
+	if serr.Rows != len(matrix) || serr.Cols != len(headers) || serr.Depth == 0 || serr.Nodes < 1000 {
+		t.Errorf("Canceled solve fails with %+v", serr)
+	}
+	if err := s.matrix.Validate(); err != nil {
+		t.Errorf("Canceled solve leaves the matrix broken: %v", err)
+	}
+
+	s = NewSolver(matrix, headers, WithNodeLimit(100))
+	if _, err := s.SolveContext(context.Background()); !errors.Is(err, ErrLimitReached) {
+		t.Errorf("Limited solve fails with %v", err)
+	}
+
+	s = NewSolver([][]int{{1, 0}}, []string{"A", "B"})
+	_, err = s.SolveContext(context.Background())
+	if !errors.Is(err, ErrNoSolution) || err.Error() != "problem (1x2): no solution after 0 nodes at depth 0" {
+		t.Errorf("Unsolvable solve fails with %v", err)
+	}
+}
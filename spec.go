@@ -0,0 +1,229 @@
+package cover
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Error returned when building a solver for a problem using features the
+// engine cannot search yet.
+var ErrUnsupported = errors.New("feature not supported by the engine")
+
+/*
+Spec describes an exact cover problem as JSON, the format read by the dlx
+command and sent by the distributed coordinator. For instance:
+
+	{
+	  "name": "example",
+	  "items": [
+	    {"name": "a"},
+	    {"name": "b"},
+	    {"name": "x", "secondary": true, "colors": ["red", "blue"]},
+	    {"name": "t", "min": 1, "max": 2}
+	  ],
+	  "options": [
+	    {"items": ["a", "x:red"], "cost": 2},
+	    {"items": ["b", "t"]},
+	    {"items": ["a", "b", "x:blue", "t"]}
+	  ]
+	}
+
+Items are the columns and options are the rows, as in Knuth's later writings.
+Primary items are covered exactly once, or between min and max times when a
+multiplicity is given. Secondary items are covered at most once, unless they
+are colored: options then give them a color, and any number of options
+agreeing on the color may cover them.
+*/
+type Spec struct {
+	Name    string       `json:"name,omitempty"`
+	Items   []ItemSpec   `json:"items"`
+	Options []OptionSpec `json:"options"`
+}
+
+// Column of a problem spec.
+type ItemSpec struct {
+	Name      string `json:"name"`
+	Secondary bool   `json:"secondary,omitempty"`
+	// colors a secondary item may take, none when uncolored
+	Colors []string `json:"colors,omitempty"`
+	// multiplicity of a primary item, both 0 meaning exactly once
+	Min int `json:"min,omitempty"`
+	Max int `json:"max,omitempty"`
+}
+
+// Row of a problem spec.
+type OptionSpec struct {
+	// item names, "item:color" giving the color of a colored secondary item
+	Items []string `json:"items"`
+	Cost  float64  `json:"cost,omitempty"`
+}
+
+// Describes a binary matrix and its headers, whose columns are primary.
+func NewSpec(matrix [][]int, headers []string) *Spec {
+	s := &Spec{Items: make([]ItemSpec, len(headers)), Options: make([]OptionSpec, len(matrix))}
+	for j, h := range headers {
+		s.Items[j].Name = h
+	}
+	for i, row := range matrix {
+		s.Options[i].Items = []string{}
+		for j, v := range row {
+			if v != 0 {
+				s.Options[i].Items = append(s.Options[i].Items, headers[j])
+			}
+		}
+	}
+	return s
+}
+
+// Reads a spec saved by Save(), and checks it.
+func LoadSpec(r io.Reader) (*Spec, error) {
+	s := &Spec{}
+	if err := json.NewDecoder(r).Decode(s); err != nil {
+		return nil, err
+	}
+	if err := s.Check(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Writes the spec as JSON.
+func (s *Spec) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(s)
+}
+
+// Returns the index of the item named in an option and its color, empty when
+// none. Item names may hold colons, so the whole name is looked up first.
+func resolve(index map[string]int, name string) (int, string, bool) {
+	if j, ok := index[name]; ok {
+		return j, "", true
+	}
+	if i := strings.LastIndexByte(name, ':'); i >= 0 {
+		j, ok := index[name[:i]]
+		return j, name[i+1:], ok
+	}
+	return 0, "", false
+}
+
+// Returns the index of each item by name.
+func (s *Spec) index() map[string]int {
+	index := make(map[string]int, len(s.Items))
+	for j, item := range s.Items {
+		index[item.Name] = j
+	}
+	return index
+}
+
+// Checks that the items are unique, that the options only use declared items
+// and colors, and that the multiplicities make sense.
+func (s *Spec) Check() error {
+	index := s.index()
+	if len(index) != len(s.Items) {
+		return errors.New("duplicate item names")
+	}
+	for _, item := range s.Items {
+		switch {
+		case len(item.Colors) > 0 && !item.Secondary:
+			return fmt.Errorf("item %v: colors on a primary item", item.Name)
+		case item.Min < 0 || item.Max < item.Min:
+			return fmt.Errorf("item %v: multiplicity %v to %v", item.Name, item.Min, item.Max)
+		case item.Max > 0 && item.Secondary:
+			return fmt.Errorf("item %v: multiplicity on a secondary item", item.Name)
+		}
+	}
+	for i, o := range s.Options {
+		seen := map[int]bool{}
+		for _, name := range o.Items {
+			j, color, ok := resolve(index, name)
+			switch {
+			case !ok:
+				return fmt.Errorf("option %v: unknown item %v", i, name)
+			case seen[j]:
+				return fmt.Errorf("option %v: item %v repeated", i, s.Items[j].Name)
+			case color != "" && !contains(s.Items[j].Colors, color):
+				return fmt.Errorf("option %v: unknown color %v of item %v", i, color, s.Items[j].Name)
+			}
+			seen[j] = true
+		}
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Returns the cost of each option, by row index.
+func (s *Spec) Costs() []float64 {
+	costs := make([]float64, len(s.Options))
+	for i, o := range s.Options {
+		costs[i] = o.Cost
+	}
+	return costs
+}
+
+// Builds a solver for the problem, whose row indexes are the option indexes.
+// Returns ErrUnsupported if the problem has multiplicities.
+func (s *Spec) Solver(opts ...Option) (*Solver, error) {
+	if err := s.Check(); err != nil {
+		return nil, err
+	}
+	headers := make([]string, len(s.Items))
+	for j, item := range s.Items {
+		once := item.Min == item.Max && item.Max <= 1
+		if !once {
+			return nil, fmt.Errorf("item %v: %w", item.Name, ErrUnsupported)
+		}
+		headers[j] = item.Name
+	}
+	index := s.index()
+	rows := make([][]int, len(s.Options))
+	// color of each colored item of each option, numbered from 1
+	colors := make([]map[int]int, len(s.Options))
+	for i, o := range s.Options {
+		rows[i] = make([]int, len(o.Items))
+		for k, name := range o.Items {
+			j, color, _ := resolve(index, name)
+			rows[i][k] = j
+			if color == "" {
+				continue
+			}
+			if colors[i] == nil {
+				colors[i] = map[int]int{}
+			}
+			for c, name := range s.Items[j].Colors {
+				if name == color {
+					colors[i][j] = c + 1
+				}
+			}
+		}
+	}
+	m := NewSparseMatrixFromRows(rows, headers)
+	cols := make([]*Node, 0, len(headers))
+	m.forEachCol(func(col *Node) {
+		cols = append(cols, col)
+	})
+	for j, item := range s.Items {
+		if item.Secondary {
+			m.setSecondary(cols[j])
+		}
+		if len(item.Colors) > 0 {
+			for n := cols[j].Down; n != cols[j]; n = n.Down {
+				n.Color = colors[n.Row][j]
+			}
+		}
+	}
+	solver := &Solver{matrix: m, Solutions: make([]*Solution, 0, 1)}
+	for _, opt := range opts {
+		opt(solver)
+	}
+	return solver, nil
+}
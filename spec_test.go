@@ -0,0 +1,109 @@
+package cover
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSpec(t *testing.T) {
+	headers := []string{"A", "B", "C", "D", "E", "F", "G"}
+	var b bytes.Buffer
+	if err := NewSpec(knuth(), headers).Save(&b); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := LoadSpec(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	solver, err := spec.Solver()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := solver.Count(); n.Int64() != 1 {
+		t.Errorf("Knuth spec has %v solutions (wants %v)", n, 1)
+	}
+	// B C F and D E G cover the others without A
+	spec.Items[0].Secondary = true
+	if solver, err = spec.Solver(); err != nil {
+		t.Fatal(err)
+	}
+	if n := solver.CountMemo(); n.Int64() != 2 {
+		t.Errorf("Knuth spec with secondary A has %v solutions (wants %v)", n, 2)
+	}
+	if r := solver.matrix.Row("A", "G"); r == nil || r.Row != 1 {
+		t.Errorf("Row A D G not found through its secondary column")
+	}
+}
+
+func TestSpecCheck(t *testing.T) {
+	for _, c := range []struct {
+		json string
+		err  string
+	}{
+		{`{"items": [{"name": "a"}, {"name": "a"}]}`, "duplicate"},
+		{`{"items": [{"name": "a"}], "options": [{"items": ["b"]}]}`, "unknown item"},
+		{`{"items": [{"name": "a"}], "options": [{"items": ["a", "a"]}]}`, "repeated"},
+		{`{"items": [{"name": "a", "colors": ["red"]}]}`, "primary"},
+		{`{"items": [{"name": "x", "secondary": true, "colors": ["red"]}], "options": [{"items": ["x:blue"]}]}`, "unknown color"},
+		{`{"items": [{"name": "a", "min": 2, "max": 1}]}`, "multiplicity"},
+	} {
+		if _, err := LoadSpec(strings.NewReader(c.json)); err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("Spec %v fails with %v (wants %v)", c.json, err, c.err)
+		}
+	}
+	spec, err := LoadSpec(strings.NewReader(`{"items": [{"name": "a", "min": 2, "max": 2}], "options": [{"items": ["a"]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := spec.Solver(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Spec with multiplicities builds a solver: %v", err)
+	}
+}
+
+func TestSpecColors(t *testing.T) {
+	// Knuth's example of colored secondary items
+	spec, err := LoadSpec(strings.NewReader(`{
+		"items": [{"name": "p"}, {"name": "q"}, {"name": "r"},
+			{"name": "x", "secondary": true, "colors": ["A", "B"]},
+			{"name": "y", "secondary": true, "colors": ["A", "B"]}],
+		"options": [{"items": ["p", "q", "x", "y:A"]}, {"items": ["p", "r", "x:A", "y"]},
+			{"items": ["p", "x:B"]}, {"items": ["q", "x:A"]}, {"items": ["r", "y:B"]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, opts := range [][]Option{nil, {WithSizeTracking()}, {WithUnitPropagation()}} {
+		solver, err := spec.Solver(opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		solutions := solver.SolveAll()
+		if len(solutions) != 1 || fmt.Sprint(solutions[0].Rows()) != "[1 3]" {
+			t.Errorf("Colored spec has solutions %v (wants [1 3])", solutions)
+		}
+		if err := solver.matrix.Validate(); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestSetSecondary(t *testing.T) {
+	solver := NewSolver(knuth(), []string{"A", "B", "C", "D", "E", "F", "G"}, WithSizeTracking())
+	if err := solver.matrix.SetSecondary("A", "Z"); err != ErrNoColumn {
+		t.Errorf("Toggling an unknown column returns %v (wants %v)", err, ErrNoColumn)
+	}
+	if n := solver.Count(); n.Int64() != 2 {
+		t.Errorf("Knuth example with secondary A has %v solutions (wants %v)", n, 2)
+	}
+	if err := solver.matrix.SetPrimary("A"); err != nil {
+		t.Fatal(err)
+	}
+	if n := solver.Count(); n.Int64() != 1 {
+		t.Errorf("Knuth example with primary A has %v solutions (wants %v)", n, 1)
+	}
+	if c := solver.matrix.Root().Left; c.Name != "A" || solver.matrix.ColCount() != 7 {
+		t.Errorf("Column %v is the last of %v", c.Name, solver.matrix.ColCount())
+	}
+}
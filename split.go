@@ -0,0 +1,169 @@
+package cover
+
+import (
+	"math/big"
+)
+
+// Groups the remaining columns linked by rows, each group in header order.
+func (m *SparseMatrix) components() [][]*Node {
+	index := map[*Node]int{}
+	cols := make([]*Node, 0)
+	m.forEachCol(func(col *Node) {
+		index[col] = len(cols)
+		cols = append(cols, col)
+	})
+	parent := make([]int, len(cols))
+	for j := range parent {
+		parent[j] = j
+	}
+	var find func(int) int
+	find = func(j int) int {
+		if parent[j] != j {
+			parent[j] = find(parent[j])
+		}
+		return parent[j]
+	}
+	for j, col := range cols {
+		for r := col.Down; r != col; r = r.Down {
+			for n := r.Right; n != r; n = n.Right {
+				parent[find(index[n.Col])] = find(j)
+			}
+		}
+	}
+	groups := make([][]*Node, 0)
+	group := map[int]int{}
+	for j, col := range cols {
+		root := find(j)
+		g, ok := group[root]
+		if !ok {
+			g = len(groups)
+			group[root] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], col)
+	}
+	return groups
+}
+
+// Splits the remaining problem into independent ones, whose columns share no
+// row, like the separate blocks of a scheduling problem. Their solutions
+// combine into the solutions of the whole problem, so that counts multiply.
+// The parts keep the row indexes, size tracking and unit propagation of the
+// solver. Groups of secondary columns only are left out since the search
+// never chooses their rows.
+func (s *Solver) Split() []*Solver {
+	m := s.matrix
+	secondary := m.secondaryCols()
+	parts := make([]*Solver, 0)
+	for _, group := range m.components() {
+		primary := false
+		index := map[*Node]int{}
+		headers := make([]string, len(group))
+		for j, col := range group {
+			index[col] = j
+			headers[j] = col.Name
+			primary = primary || !secondary[col]
+		}
+		if !primary {
+			continue
+		}
+		rows := make([][]int, 0)
+		ids := make([]int, 0)
+		seen := map[int]bool{}
+		for _, col := range group {
+			for r := col.Down; r != col; r = r.Down {
+				if seen[r.Row] {
+					continue
+				}
+				seen[r.Row] = true
+				row := make([]int, 0)
+				for _, n := range r.RowNodes() {
+					row = append(row, index[n.Col])
+				}
+				rows = append(rows, row)
+				ids = append(ids, r.Row)
+			}
+		}
+		sub := NewSparseMatrixFromRows(rows, headers)
+		cols := make([]*Node, 0, len(group))
+		sub.forEachCol(func(col *Node) {
+			cols = append(cols, col)
+			for r := col.Down; r != col; r = r.Down {
+				r.Row = ids[r.Row]
+			}
+		})
+		for j, col := range group {
+			if secondary[col] {
+				sub.setSecondary(cols[j])
+			}
+		}
+		if m.sizes != nil {
+			sub.TrackSizes()
+		}
+		if m.units {
+			sub.PropagateUnits()
+		}
+		parts = append(parts, &Solver{matrix: sub, Solutions: make([]*Solution, 0, 1), decoder: s.decoder})
+	}
+	return parts
+}
+
+// Counts the solutions as the product of the counts of the parts of Split().
+func (s *Solver) CountSplit() *big.Int {
+	count := big.NewInt(1)
+	for _, part := range s.Split() {
+		count.Mul(count, part.Count())
+		if count.Sign() == 0 {
+			break
+		}
+	}
+	return count
+}
+
+// Calls f with each solution until it returns false. The solutions of the
+// parts of Split() are enumerated first, then combined one at a time, so that
+// their product is never held in memory. The solutions are made of the rows
+// of the solver, and are only valid during the call.
+func (s *Solver) SolveSplit(f func(*Solution) bool) {
+	// the first node of each remaining row, by index
+	nodes := map[int]*Node{}
+	s.matrix.forEachCol(func(col *Node) {
+		for r := col.Down; r != col; r = r.Down {
+			if _, ok := nodes[r.Row]; !ok {
+				nodes[r.Row] = r
+			}
+		}
+	})
+	all := make([][]*Solution, 0)
+	for _, part := range s.Split() {
+		sols := part.SolveAll()
+		if len(sols) == 0 {
+			return
+		}
+		all = append(all, sols)
+	}
+	// odometer over the solutions of the parts
+	pick := make([]int, len(all))
+	O := s.matrix.NewSolution()
+	for {
+		*O = (*O)[:0]
+		for p, i := range pick {
+			for _, r := range *all[p][i] {
+				*O = append(*O, nodes[r.Row])
+			}
+		}
+		if !f(O) {
+			return
+		}
+		p := len(pick) - 1
+		for ; p >= 0; p-- {
+			if pick[p]++; pick[p] < len(all[p]) {
+				break
+			}
+			pick[p] = 0
+		}
+		if p < 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package cover
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Two dominoes boards side by side, sharing no cell.
+func twoBoards() *Solver {
+	rows, headers := [][]int{}, []string{}
+	for b, dims := range [][2]int{{2, 3}, {3, 4}} {
+		h, w := dims[0], dims[1]
+		base := len(headers)
+		for i := 0; i < h*w; i++ {
+			headers = append(headers, fmt.Sprintf("%v:%v,%v", b, i/w, i%w))
+		}
+		for x := 0; x < h; x++ {
+			for y := 0; y < w; y++ {
+				if y+1 < w {
+					rows = append(rows, []int{base + x*w + y, base + x*w + y + 1})
+				}
+				if x+1 < h {
+					rows = append(rows, []int{base + x*w + y, base + (x+1)*w + y})
+				}
+			}
+		}
+	}
+	return &Solver{matrix: NewSparseMatrixFromRows(rows, headers)}
+}
+
+func TestSplit(t *testing.T) {
+	s := twoBoards()
+	parts := s.Split()
+	if len(parts) != 2 {
+		t.Fatalf("Two boards split in %v parts", len(parts))
+	}
+	// 3 tilings of 2x3 times 11 tilings of 3x4
+	if n := s.CountSplit(); n.Int64() != 33 || s.Count().Int64() != 33 {
+		t.Errorf("Two boards have %v tilings (wants %v)", n, 33)
+	}
+	seen := map[string]bool{}
+	s.SolveSplit(func(O *Solution) bool {
+		if O.Len() != 9 {
+			t.Errorf("Tiling has %v dominoes (wants %v)", O.Len(), 9)
+		}
+		seen[O.Key()] = true
+		return true
+	})
+	if len(seen) != 33 {
+		t.Errorf("Split enumeration finds %v distinct tilings (wants %v)", len(seen), 33)
+	}
+	calls := 0
+	s.SolveSplit(func(O *Solution) bool {
+		calls++
+		return false
+	})
+	if calls != 1 {
+		t.Errorf("Enumeration goes on after stop, %v calls", calls)
+	}
+}
@@ -0,0 +1,78 @@
+package cover
+
+import (
+	"fmt"
+)
+
+// Search counters for a depth level.
+type LevelStats struct {
+	// rows tried by branching at this level
+	Candidates int64
+	// rows tried whose subtree holds no solution
+	Failures int64
+	// rows included without branching, see PropagateUnits()
+	Forced int64
+}
+
+// Search counters, per depth level.
+type Stats struct {
+	Levels    []LevelStats
+	Solutions int64
+	// name and tags of the problem searched, see SetName()
+	Problem string
+	Tags    map[string]string
+}
+
+// Makes sure the counters of level k exist.
+func (s *Stats) level(k int) {
+	for len(s.Levels) <= k {
+		s.Levels = append(s.Levels, LevelStats{})
+	}
+}
+
+// Returns the total number of rows tried by branching.
+func (s *Stats) Candidates() int64 {
+	n := int64(0)
+	for _, l := range s.Levels {
+		n += l.Candidates
+	}
+	return n
+}
+
+// Formats the counters as a table, one line per level.
+func (s *Stats) String() string {
+	o := ""
+	if s.Problem != "" {
+		o = s.Problem + "\n"
+	}
+	o += fmt.Sprintf("%6v %12v %12v %12v\n", "level", "candidates", "failures", "forced")
+	for k, l := range s.Levels {
+		o += fmt.Sprintf("%6v %12v %12v %12v\n", k, l.Candidates, l.Failures, l.Forced)
+	}
+	return o + fmt.Sprintf("%v solutions\n", s.Solutions)
+}
+
+// Returns the counters of the searches since the last ResetStats().
+// They show where the search explodes, which helps to choose a better column
+// ordering or to add constraints.
+func (m *SparseMatrix) Stats() Stats {
+	s := m.stats
+	s.Levels = append([]LevelStats{}, m.stats.Levels...)
+	s.Problem, s.Tags = m.name, m.Tags()
+	return s
+}
+
+// Clears the search counters. The solvers do it before each solve.
+func (m *SparseMatrix) ResetStats() {
+	m.stats = Stats{Levels: m.stats.Levels[:0]}
+	if m.groups != nil {
+		for i := range m.groups.branches {
+			m.groups.branches[i] = 0
+		}
+	}
+}
+
+// Returns the counters of the last solve.
+func (s *Solver) Stats() Stats {
+	return s.matrix.Stats()
+}
@@ -0,0 +1,190 @@
+package cover
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Error stopping the enumeration of Each().
+var errStop = errors.New("stop")
+
+// Record of a solution written by WriteSolutions() in the JSON format.
+type SolutionRecord struct {
+	// sorted row indexes
+	Rows []int `json:"rows"`
+	// rows decoded by the decoder of the solver if any, in search order
+	Decoded []interface{} `json:"decoded,omitempty"`
+	// name and tags of the problem, see SetName()
+	Problem string            `json:"problem,omitempty"`
+	Tags    map[string]string `json:"tags,omitempty"`
+}
+
+// Guesser writing every solution as soon as found, and aborting the search
+// on the first write error.
+type streamer struct {
+	solver *Solver
+	write  func(O *Solution) error
+	count  int
+	err    error
+}
+
+func (s *streamer) ChooseCol(k int) *Node {
+	return s.solver.matrix.SmallestCol()
+}
+func (s *streamer) Eureka(O *Solution) {
+	if s.err = s.write(O); s.err != nil {
+		s.solver.matrix.Abort()
+		return
+	}
+	s.count++
+}
+func (s *streamer) Terminate() bool {
+	return false
+}
+
+// Enumerates the solutions, writing each one as soon as found so that huge
+// enumerations can be piped to disk or to another process: one JSON record
+// per line with the JSON format, the sorted row indexes separated by commas
+// with CSV, or by spaces with Line. Consecutive solutions often share most of
+// their rows, which the Delta format saves: each line holds the rows removed
+// since the previous solution as -(index+1) and the rows added as index+1,
+// see ReadDeltas(). Returns the number of solutions written
+// and the first write error, which stops the search.
+func (s *Solver) WriteSolutions(w io.Writer, format Format) (int, error) {
+	b := bufio.NewWriter(w)
+	var write func(O *Solution) error
+	switch format {
+	case JSON:
+		enc := json.NewEncoder(b)
+		write = func(O *Solution) error {
+			rec := SolutionRecord{Rows: O.Rows(), Problem: s.matrix.name, Tags: s.matrix.tags}
+			if s.decoder != nil {
+				rec.Decoded = s.Decode(O)
+			}
+			return enc.Encode(rec)
+		}
+	case CSV:
+		c := csv.NewWriter(b)
+		write = func(O *Solution) error {
+			fields := make([]string, O.Len())
+			for i, r := range O.Rows() {
+				fields[i] = strconv.Itoa(r)
+			}
+			c.Write(fields)
+			c.Flush()
+			return c.Error()
+		}
+	case Line:
+		write = func(O *Solution) error {
+			for i, r := range O.Rows() {
+				if i > 0 {
+					b.WriteByte(' ')
+				}
+				b.WriteString(strconv.Itoa(r))
+			}
+			return b.WriteByte('\n')
+		}
+	case Delta:
+		prev := []int{}
+		write = func(O *Solution) error {
+			rows := O.Rows()
+			removed, added := diffRows(prev, rows)
+			for i, r := range removed {
+				if i > 0 {
+					b.WriteByte(' ')
+				}
+				b.WriteString(strconv.Itoa(-r - 1))
+			}
+			for i, r := range added {
+				if i > 0 || len(removed) > 0 {
+					b.WriteByte(' ')
+				}
+				b.WriteString(strconv.Itoa(r + 1))
+			}
+			prev = rows
+			return b.WriteByte('\n')
+		}
+	default:
+		return 0, fmt.Errorf("solutions in format %v: %w", format, ErrUnsupported)
+	}
+	s.matrix.ResetStats()
+	st := &streamer{solver: s, write: write}
+	s.matrix.Search(s.matrix.NewSolution(), 0, st)
+	if err := b.Flush(); st.err == nil {
+		st.err = err
+	}
+	return st.count, st.err
+}
+
+// Calls f with each solution as soon as found, until it returns false, e.g.
+// to filter the solutions with a rule the matrix cannot express. The solution
+// is only valid during the call. The matrix is restored afterwards.
+func (s *Solver) Each(f func(O *Solution) bool) {
+	s.matrix.ResetStats()
+	st := &streamer{solver: s, write: func(O *Solution) error {
+		if !f(O) {
+			return errStop
+		}
+		return nil
+	}}
+	s.matrix.Search(s.matrix.NewSolution(), 0, st)
+}
+
+// Returns the rows of a missing from b and the rows of b missing from a, both
+// being sorted.
+func diffRows(a, b []int) (removed, added []int) {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || i < len(a) && a[i] < b[j]:
+			removed = append(removed, a[i])
+			i++
+		case i == len(a) || b[j] < a[i]:
+			added = append(added, b[j])
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	return removed, added
+}
+
+// Reads the solutions written by WriteSolutions() in the Delta format, calling
+// f with the sorted row indexes of each one until it returns false.
+func ReadDeltas(r io.Reader, f func(rows []int) bool) error {
+	rows := map[int]bool{}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<24)
+	for sc.Scan() {
+		for _, field := range strings.Fields(sc.Text()) {
+			d, err := strconv.Atoi(field)
+			switch {
+			case err != nil:
+				return err
+			case d < 0:
+				delete(rows, -d-1)
+			case d > 0:
+				rows[d-1] = true
+			default:
+				return errors.New("delta 0 is no row")
+			}
+		}
+		O := make([]int, 0, len(rows))
+		for r := range rows {
+			O = append(O, r)
+		}
+		sort.Ints(O)
+		if !f(O) {
+			return nil
+		}
+	}
+	return sc.Err()
+}
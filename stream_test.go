@@ -0,0 +1,66 @@
+package cover
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestWriteSolutions(t *testing.T) {
+	m := [][]int{{1, 0}, {0, 1}, {1, 1}}
+	for f, want := range map[Format]string{
+		Line: "0 1\n2\n",
+		CSV:  "0,1\n2\n",
+		JSON: "{\"rows\":[0,1]}\n{\"rows\":[2]}\n",
+	} {
+		var b bytes.Buffer
+		n, err := NewSolver(m, []string{"A", "B"}).WriteSolutions(&b, f)
+		if err != nil || n != 2 || b.String() != want {
+			t.Errorf("Format %v writes %v solutions, error %v:\n%v(wants\n%v)", f, n, err, b.String(), want)
+		}
+	}
+	s := NewSolver(m, []string{"A", "B"}, WithDecoder(ColumnNames))
+	var b bytes.Buffer
+	s.WriteSolutions(&b, JSON)
+	if want := "{\"rows\":[0,1],\"decoded\":[[\"A\"],[\"B\"]]}\n{\"rows\":[2],\"decoded\":[[\"A\",\"B\"]]}\n"; b.String() != want {
+		t.Errorf("Decoded records are\n%v(wants\n%v)", b.String(), want)
+	}
+	if _, err := s.WriteSolutions(&b, Box); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Box format gives %v (wants ErrUnsupported)", err)
+	}
+	rows, headers := SudokuConstraintRows(4)
+	big := &Solver{matrix: NewSparseMatrixFromRows(rows, headers)}
+	if n, err := big.WriteSolutions(failingWriter{}, Line); err == nil || n >= 288 {
+		t.Errorf("Failing writer gives %v after %v solutions", err, n)
+	}
+}
+
+func TestReadDeltas(t *testing.T) {
+	rows, headers := SudokuConstraintRows(4)
+	s := &Solver{matrix: NewSparseMatrixFromRows(rows, headers)}
+	var lines, deltas bytes.Buffer
+	s.WriteSolutions(&lines, Line)
+	n, err := s.WriteSolutions(&deltas, Delta)
+	if err != nil || n != 288 {
+		t.Fatalf("Delta format writes %v solutions, error %v", n, err)
+	}
+	if deltas.Len() >= lines.Len() {
+		t.Errorf("Deltas take %v bytes, more than %v for lines", deltas.Len(), lines.Len())
+	}
+	var b bytes.Buffer
+	ReadDeltas(&deltas, func(rows []int) bool {
+		b.WriteString(strings.Trim(fmt.Sprint(rows), "[]") + "\n")
+		return true
+	})
+	if b.String() != lines.String() {
+		t.Errorf("Deltas read back differ from the lines written")
+	}
+}
@@ -2,10 +2,8 @@ package cover
 
 import (
 	"fmt"
-	"log"
 	"math"
-	"sort"
-	"strconv"
+	"math/big"
 	"strings"
 )
 
@@ -18,10 +16,30 @@ func SudokuConstraintMatrix(dim int) (matrix [][]int, headers []string) {
 	bdim := dim * dim
 	rowCount := bdim * dim
 	colCount := bdim * 4
-	log.Printf("Building sparse matrix of %dx%d\n", rowCount, colCount)
-	// constraint matrix headers
-	// constraint order is existence, row, col, block
-	headers = make([]string, colCount)
+	headers = sudokuHeaders(dim)
+	// constraint matrix
+	matrix = make([][]int, rowCount)
+	for i := 0; i < rowCount; i++ {
+		matrix[i] = make([]int, colCount)
+		digit := i%dim + 1
+		dcell := i / dim
+		drow := i / bdim
+		dcol := (i / dim) % dim
+		dblock := drow/sdim*sdim + dcol/sdim
+		matrix[i][dcell] = digit
+		matrix[i][bdim+drow*dim+i%dim] = digit
+		matrix[i][bdim+bdim+i%bdim] = digit
+		matrix[i][bdim+bdim+bdim+dblock*dim+i%dim] = digit
+	}
+	return
+}
+
+// Builds the constraint matrix headers for a sudoku of the given dimension.
+// Constraint order is existence, row, col, block.
+func sudokuHeaders(dim int) []string {
+	bdim := dim * dim
+	colCount := bdim * 4
+	headers := make([]string, colCount)
 	for i, j := 0, 0; i < colCount; i++ {
 		j = i % bdim
 		if i < bdim {
@@ -38,135 +56,198 @@ func SudokuConstraintMatrix(dim int) (matrix [][]int, headers []string) {
 			headers[i] = fmt.Sprintf("%vb%v", j%dim+1, j/dim)
 		}
 	}
-	// constraint matrix
-	matrix = make([][]int, rowCount)
+	return headers
+}
+
+// Same as SudokuConstraintMatrix(), but each row only lists the indexes of its
+// 4 columns. It skips the dense matrix, which has 6+ million cells for 25x25.
+func SudokuConstraintRows(dim int) (rows [][]int, headers []string) {
+	sdim := int(math.Sqrt(float64(dim)))
+	bdim := dim * dim
+	rowCount := bdim * dim
+	rows = make([][]int, rowCount)
+	// a single backing array keeps the rows contiguous
+	cols := make([]int, rowCount*4)
 	for i := 0; i < rowCount; i++ {
-		matrix[i] = make([]int, colCount)
-		digit := i%dim + 1
 		dcell := i / dim
 		drow := i / bdim
 		dcol := (i / dim) % dim
 		dblock := drow/sdim*sdim + dcol/sdim
-		matrix[i][dcell] = digit
-		matrix[i][bdim+drow*dim+i%dim] = digit
-		matrix[i][bdim+bdim+i%bdim] = digit
-		matrix[i][bdim+bdim+bdim+dblock*dim+i%dim] = digit
+		rows[i] = cols[i*4 : i*4+4 : i*4+4]
+		rows[i][0] = dcell
+		rows[i][1] = bdim + drow*dim + i%dim
+		rows[i][2] = bdim + bdim + i%bdim
+		rows[i][3] = bdim + bdim + bdim + dblock*dim + i%dim
 	}
-	return
+	return rows, sudokuHeaders(dim)
 }
 
 type SudokuSolver struct {
 	*Solver
 	Dim int
+	// cells placed by naked and hidden singles before the last search
+	Singles int
 }
 
 // Since the constraint matrix for a sudoku only depends on its size, this constructor
 // encapsulate the matrix creation so that only the sudoku size is needed.
-func NewSudokuSolver(dim int) *SudokuSolver {
-	m, h := SudokuConstraintMatrix(dim)
-	s := SudokuSolver{&Solver{matrix: NewSparseMatrix(m, h)}, dim}
+// The options apply after the default size tracking and unit propagation.
+func NewSudokuSolver(dim int, opts ...Option) *SudokuSolver {
+	rows, h := SudokuConstraintRows(dim)
+	s := SudokuSolver{Solver: &Solver{matrix: NewSparseMatrixFromRows(rows, h), decoder: SudokuDecoder}, Dim: dim}
+	s.matrix.TrackSizes()
+	s.matrix.PropagateUnits()
+	for _, opt := range opts {
+		opt(s.Solver)
+	}
 	return &s
 }
 
-// Translates the initial grid to a map of digit => cells.
-// This enables the solver to safely initialize the matrix before
-// actually running the DLX algorithm.
-// The trick is to search only in the columns of the 1st constraint (existence)
-// and start from the biggest digit. This way, the number of steps to find the correct
-// row does not change and the digit can be found in a single column.
-func (s *SudokuSolver) gridToCover(sudoku [][]int) map[int][]string {
-	dim := len(sudoku)
-	init := map[int][]string{}
-	for i := 0; i < dim; i++ {
-		for j := 0; j < dim; j++ {
-			digit := sudoku[i][j]
+// Finds the rows of the matrix matching the digits of the grid, 0 being an empty cell.
+// Returns nil if a digit is out of range.
+func (s *SudokuSolver) givens(sudoku [][]int) []*Node {
+	rows := make([]*Node, 0)
+	for i, line := range sudoku {
+		for j, digit := range line {
 			if digit > 0 {
-				_, ok := init[digit]
-				if !ok {
-					init[digit] = make([]string, 0)
+				r := s.matrix.Row(fmt.Sprintf("%v,%v", i, j), fmt.Sprintf("%vr%v", digit, i))
+				if r == nil {
+					return nil
 				}
-				init[digit] = append(init[digit], fmt.Sprintf("%v,%v", i, j))
+				rows = append(rows, r)
 			}
 		}
 	}
-	return init
+	return rows
 }
-func (s *SudokuSolver) coverToGrid(nodes []*Node) (x int, y int, digit int) {
-	for _, n := range nodes {
-		if n != nil {
-			if strings.ContainsAny(n.Col.Name, "r & c & b") {
-				digit, _ = strconv.Atoi(fmt.Sprintf("%c", n.Col.Name[0]))
-			} else {
-				xy := strings.Split(n.Col.Name, ",")
-				x, _ = strconv.Atoi(xy[0])
-				y, _ = strconv.Atoi(xy[1])
-			}
+
+// Digit placed in a sudoku cell, decoded from a row of the constraint matrix.
+type SudokuCell struct {
+	X, Y, Digit int
+}
+
+// Decodes the rows of sudoku constraint matrices into sudoku cells. Only the
+// cell columns, named "x,y", and the digit ones, named like "5r0", are read,
+// which leaves the columns of variants out.
+var SudokuDecoder = DecoderFunc(func(row *Node) interface{} {
+	var c SudokuCell
+	for _, n := range row.RowNodes() {
+		name := n.Col.Name
+		i := digits(name)
+		switch {
+		case i == 0 || i == len(name):
+		case name[i] == ',':
+			c.X = leadingInt(name)
+			c.Y = leadingInt(name[i+1:])
+		case strings.IndexByte("rcb", name[i]) >= 0:
+			c.Digit = leadingInt(name)
 		}
 	}
-	return
+	return c
+})
+
+// Returns the number of digits at the start of a string.
+func digits(s string) int {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	return i
 }
-func (s *SudokuSolver) Eureka(O *Solution) {
+
+// Parses the digits at the start of a string, without allocating.
+func leadingInt(s string) int {
+	n := 0
+	for i := 0; i < len(s) && s[i] >= '0' && s[i] <= '9'; i++ {
+		n = n*10 + int(s[i]-'0')
+	}
+	return n
+}
+
+// Translates a solution back to a grid.
+func (s *SudokuSolver) Grid(O *Solution) [][]int {
 	grid := make([][]int, s.Dim)
 	for i := 0; i < s.Dim; i++ {
 		grid[i] = make([]int, s.Dim)
 	}
-	for _, n := range *O {
-		nodes := make([]*Node, 4)
-		nodes = append(nodes, n)
-		for m := n.Right; n != m; m = m.Right {
-			nodes = append(nodes, m)
-		}
-		x, y, digit := s.coverToGrid(nodes)
-		grid[x][y] = digit
-	}
-	sdim := int(math.Sqrt(float64(s.Dim)))
-	delim := "+" + strings.Repeat(strings.Repeat("-", sdim*2+1)+"+", sdim)
-	for i, line := range grid {
-		if i%sdim == 0 {
-			fmt.Println(delim)
-		}
-		for j, cell := range line {
-			if j%sdim == 0 {
-				if j > 0 {
-					fmt.Print(" ")
-				}
-				fmt.Print("|")
-			}
-			fmt.Print(" ", cell)
+	for _, obj := range s.Decode(O) {
+		// rows added by variants are no cells
+		if c, ok := obj.(SudokuCell); ok {
+			grid[c.X][c.Y] = c.Digit
 		}
-		fmt.Print(" |\n")
 	}
-	fmt.Println(delim)
+	return grid
 }
+
+// Estimates the number of solutions of the grid, and the size of the search
+// tree of Solve(), from random probes below the givens and the singles, see
+// Solver.EstimateCount(). The estimate is empty if the givens contradict each
+// other. The matrix is restored.
+func (s *SudokuSolver) EstimateCount(sudoku [][]int, probes int, seed int64) CountEstimate {
+	s.Reset()
+	m := s.matrix
+	O := m.NewSolution()
+	rows := s.givens(sudoku)
+	if rows == nil {
+		return CountEstimate{Probes: probes}
+	}
+	k, err := m.Require(O, rows...)
+	if err != nil {
+		return CountEstimate{Probes: probes}
+	}
+	k += m.Propagate(O, k)
+	e := s.Solver.EstimateCount(probes, seed)
+	m.Release(O, k)
+	return e
+}
+
+// Solves the grid, 0 being an empty cell. The givens are required, then the
+// singles placed, before searching, and all are released if there is no
+// solution. The returned solution is
+// empty if there is none, or if the givens contradict each other.
+// Calling Reset() restores the matrix, which Solve() does itself beforehand.
 func (s *SudokuSolver) Solve(sudoku [][]int) *Solution {
-	partial := s.gridToCover(sudoku)
-	// Iterate through the digits from biggest to smallest.
-	keys := make([]int, 0, len(partial))
-	for key, _ := range partial {
-		keys = append(keys, key)
-	}
-	sort.Sort(sort.Reverse(sort.IntSlice(keys)))
-	// log.Println("Initial config is", partial)
-	O := new(Solution)
-	k := 0
+	s.Reset()
 	m := s.matrix
-	for _, digit := range keys {
-		for _, c := range partial[digit] {
-			// Find the column for existence constraint, so that all the digits are available inside.
-			n := m.Col(c)
-			n.Cover()
-			// Move down by <digit> nodes to find the row to include in the solution.
-			for j := 0; j < digit; j++ {
-				n = n.Down
-			}
-			O.Set(k, n)
-			for o := n.Right; o != n; o = o.Right {
-				o.Col.Cover()
-			}
-			k++
-		}
+	m.ResetStats()
+	s.Singles = 0
+	O := m.NewSolution()
+	rows := s.givens(sudoku)
+	if rows == nil {
+		return O
+	}
+	k, err := m.Require(O, rows...)
+	if err != nil {
+		return O
+	}
+	// singles are the unit columns
+	s.Singles = m.Propagate(O, k)
+	k += s.Singles
+	m.Search(O, k, s)
+	if len(s.Solutions) == 0 {
+		m.Release(O, k)
+		*O = (*O)[:0]
 	}
-	// fmt.Printf("Initial solution is\n%v", O)
-	s.matrix.Search(O, k, s)
 	return O
 }
+
+// Counts the completions of a partial grid, 0 being an empty cell, with the
+// memoized counter of CountMemo(). Subgrids reached through different orders
+// of placements are counted once, which makes counts like the completions of
+// a fixed top band of a small sudoku practical. Returns 0 if the givens
+// contradict each other. The matrix is restored afterwards.
+func (s *SudokuSolver) CountCompletions(partial [][]int) *big.Int {
+	s.Reset()
+	m := s.matrix
+	O := m.NewSolution()
+	rows := s.givens(partial)
+	if rows == nil {
+		return new(big.Int)
+	}
+	k, err := m.Require(O, rows...)
+	if err != nil {
+		return new(big.Int)
+	}
+	defer m.Release(O, k)
+	return s.CountMemo()
+}
@@ -0,0 +1,244 @@
+package cover
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestSudokuConstraintRows(t *testing.T) {
+	for _, dim := range []int{4, 9} {
+		matrix, mh := SudokuConstraintMatrix(dim)
+		rows, rh := SudokuConstraintRows(dim)
+		if len(rows) != len(matrix) || len(rh) != len(mh) {
+			t.Fatalf("Sparse constraints are %vx%v (wants %vx%v)", len(rows), len(rh), len(matrix), len(mh))
+		}
+		for i, row := range rows {
+			ones := 0
+			for _, v := range matrix[i] {
+				if v > 0 {
+					ones++
+				}
+			}
+			if ones != len(row) {
+				t.Errorf("Row %v has %v columns (wants %v)", i, len(row), ones)
+			}
+			for _, j := range row {
+				if matrix[i][j] == 0 {
+					t.Errorf("Row %v has column %v not in the dense matrix", i, mh[j])
+				}
+			}
+		}
+	}
+}
+
+func TestNewSparseMatrixParallel(t *testing.T) {
+	rows, h := SudokuConstraintRows(4)
+	for _, workers := range []int{1, 3, 0} {
+		solver := &Solver{matrix: NewSparseMatrixParallel(rows, h, workers)}
+		if count := solver.Count(); count.Int64() != 288 {
+			t.Errorf("There are 288 4x4 sudoku grids, %v found with %v workers", count, workers)
+		}
+	}
+}
+
+func TestNewSparseMatrixWithBudget(t *testing.T) {
+	rows, h := SudokuConstraintRows(9)
+	// 729 rows of 4 nodes, 324 headers and the root
+	if _, err := NewSparseMatrixWithBudget(rows, h, 3240); err == nil {
+		t.Errorf("Building 3241 nodes within a budget of 3240 should fail")
+	}
+	m, err := NewSparseMatrixWithBudget(rows, h, 3241)
+	if err != nil {
+		t.Fatalf("Building 3241 nodes within a budget of 3241 fails: %v", err)
+	}
+	if size := m.MemoryFootprint(); size < 3241*64 {
+		t.Errorf("Matrix of 3241 nodes uses only %v bytes", size)
+	}
+}
+
+// Parses a 81 chars sudoku, '.' being an empty cell.
+func parseGrid(s string) [][]int {
+	grid := make([][]int, 9)
+	for i := range grid {
+		grid[i] = make([]int, 9)
+		for j := range grid[i] {
+			if c := s[i*9+j]; c != '.' {
+				grid[i][j] = int(c - '0')
+			}
+		}
+	}
+	return grid
+}
+
+func checkGrid(t *testing.T, given, grid [][]int) {
+	for i := 0; i < 9; i++ {
+		row, col, block := map[int]bool{}, map[int]bool{}, map[int]bool{}
+		for j := 0; j < 9; j++ {
+			row[grid[i][j]] = true
+			col[grid[j][i]] = true
+			block[grid[i/3*3+j/3][i%3*3+j%3]] = true
+			if given[i][j] > 0 && given[i][j] != grid[i][j] {
+				t.Errorf("Cell %v,%v is %v (wants given %v)", i, j, grid[i][j], given[i][j])
+			}
+		}
+		if len(row) != 9 || len(col) != 9 || len(block) != 9 {
+			t.Errorf("Grid is not a sudoku solution: %v", grid)
+		}
+	}
+}
+
+func TestSudokuSolverSolve(t *testing.T) {
+	s := NewSudokuSolver(9)
+	for _, p := range []string{
+		"1....7.9..3..2...8..96..5....53..9...1..8...26....4...3......1..4......7..7...3..",
+		"8..........36......7..9.2...5...7.......457.....1...3...1....68..85...1..9....4..",
+	} {
+		given := parseGrid(p)
+		O := s.Solve(given)
+		if O.Len() != 81 {
+			t.Fatalf("Solution has %v rows (wants %v)", O.Len(), 81)
+		}
+		checkGrid(t, given, s.Grid(O))
+	}
+	// two 1 in the first row
+	if O := s.Solve(parseGrid("11" + strings.Repeat(".", 79))); O.Len() != 0 {
+		t.Errorf("Contradicting givens have a solution")
+	}
+	s.Reset()
+	if cols := s.matrix.ColCount(); cols != 324 {
+		t.Errorf("Matrix has %v columns after reset (wants %v)", cols, 324)
+	}
+}
+
+func TestFormatGrid(t *testing.T) {
+	p := "1....7.9..3..2...8..96..5....53..9...1..8...26....4...3......1..4......7..7...3.."
+	if line := FormatGrid(parseGrid(p), nil, Line); line != p {
+		t.Errorf("Line format is %v (wants %v)", line, p)
+	}
+	grid := [][]int{{1, 2, 3, 4}, {3, 4, 1, 2}, {2, 1, 4, 3}, {4, 3, 2, 0}}
+	puzzle := [][]int{{1, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}}
+	for f, want := range map[Format]string{
+		Box: "+-----+-----+\n| 1 2 | 3 4 |\n| 3 4 | 1 2 |\n+-----+-----+\n| 2 1 | 4 3 |\n| 4 3 | 2 0 |\n+-----+-----+\n",
+		CSV: "1,2,3,4\n3,4,1,2\n2,1,4,3\n4,3,2,0\n",
+		JSON: `{"grid":[[1,2,3,4],[3,4,1,2],[2,1,4,3],[4,3,2,0]],` +
+			`"given":[[true,false,false,false],[false,false,false,false],[false,false,false,false],[false,false,false,false]]}`,
+	} {
+		if s := FormatGrid(grid, puzzle, f); s != want {
+			t.Errorf("Format %v is\n%v\n(wants\n%v)", f, s, want)
+		}
+	}
+}
+
+func TestFormatBox(t *testing.T) {
+	grid := [][]int{{1, 2, 3, 4}, {3, 4, 1, 2}, {2, 1, 4, 3}, {4, 3, 2, 0}}
+	want := "┌─────┬─────┐\n│ 1 2 │ 3 4 │\n│ 3 4 │ 1 2 │\n├─────┼─────┤\n│ 2 1 │ 4 3 │\n│ 4 3 │ 2 0 │\n└─────┴─────┘\n"
+	if s := FormatGrid(grid, nil, Unicode); s != want {
+		t.Errorf("Unicode format is\n%v(wants\n%v)", s, want)
+	}
+	if s := FormatBox(grid, BoxStyle{Hex: true}); !strings.Contains(s, "| 0 1 | 2 3 |") || !strings.Contains(s, "| 3 2 | 1 . |") {
+		t.Errorf("Hex format is\n%v", s)
+	}
+	big := make([][]int, 16)
+	for i := range big {
+		big[i] = make([]int, 16)
+		for j := range big[i] {
+			big[i][j] = (i*4+i/4+j)%16 + 1
+		}
+	}
+	lines := strings.Split(FormatGrid(big, nil, Box), "\n")
+	for _, line := range lines[1:17] {
+		if len(line) != len(lines[0]) {
+			t.Errorf("Line %q is not aligned with %q", line, lines[0])
+		}
+	}
+	if !strings.HasPrefix(lines[1], "|  1  2  3  4 |") {
+		t.Errorf("First line of 16x16 grid is %q", lines[1])
+	}
+	if s := FormatBox(big, BoxStyle{Hex: true}); !strings.Contains(s, "| 0 1 2 3 | 4 5 6 7 | 8 9 A B | C D E F |") {
+		t.Errorf("Hexadoku is\n%v", s)
+	}
+}
+
+func TestParseGrid(t *testing.T) {
+	want := "1....7.9..3..2...8..96..5....53..9...1..8...26....4...3......1..4......7..7...3.."
+	for _, s := range []string{
+		want,
+		"Puzzle of the day\n" + FormatGrid(parseGrid(want), nil, Box),
+		"1 _ _ | _ _ 7 | _ 9 _\n_ 3 _ | _ 2 _ | _ _ 8\n_ _ 9 | 6 _ _ | 5 _ _\n" +
+			"------+-------+------\n_ _ 5 | 3 _ _ | 9 _ _\n_ 1 _ | _ 8 _ | _ _ 2\n6 _ _ | _ _ 4 | _ _ _\n" +
+			"------+-------+------\n3 _ _ | _ _ _ | _ 1 _\n_ 4 _ | _ _ _ | _ _ 7\n_ _ 7 | _ _ _ | 3 _ _\n",
+		"┌───┬───┬───┐\n│1..│..7│.9.│\n│.3.│.2.│..8│\n│..9│6..│5..│\n├───┼───┼───┤\n" +
+			"│..5│3..│9..│\n│.1.│.8.│..2│\n│6..│..4│...│\n├───┼───┼───┤\n" +
+			"│3..│...│.1.│\n│.4.│...│..7│\n│..7│...│3..│\n└───┴───┴───┘\n",
+	} {
+		grid, err := ParseGrid(s)
+		if err != nil {
+			t.Errorf("Cannot parse %v: %v", s, err)
+		} else if line := FormatGrid(grid, nil, Line); line != want {
+			t.Errorf("Grid parses to %v (wants %v)", line, want)
+		}
+	}
+	if _, err := ParseGrid("12.."); err == nil {
+		t.Errorf("Grid of 4 cells parsed")
+	}
+	if _, err := ParseGrid("1234/3412/2143/435."); err == nil {
+		t.Errorf("Grid having a 5 parsed with dimension 4")
+	}
+}
+
+func TestSudokuEstimateCount(t *testing.T) {
+	s := NewSudokuSolver(4)
+	full := [][]int{{1, 2, 3, 4}, {3, 4, 1, 2}, {2, 1, 4, 3}, {4, 3, 2, 0}}
+	if e := s.EstimateCount(full, 10, 1); e.Count != 1 || e.Nodes != 1 {
+		t.Errorf("Grid with a single empty cell is estimated to %v solutions and %v nodes", e.Count, e.Nodes)
+	}
+	if e := s.EstimateCount([][]int{{1, 1}}, 10, 1); e.Count != 0 || e.Probes != 10 {
+		t.Errorf("Contradicting givens are estimated to %v", e)
+	}
+	empty := [][]int{{0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}}
+	if e := s.EstimateCount(empty, 100, 1); e.Count <= 0 || e.Nodes <= 1 {
+		t.Errorf("Empty 4x4 grid is estimated to %v", e)
+	}
+	if n := s.CountCompletions(empty); n.Int64() != 288 {
+		t.Errorf("Empty 4x4 grid has %v completions after estimating (wants %v)", n, 288)
+	}
+}
+
+func TestCountCompletions(t *testing.T) {
+	s := NewSudokuSolver(4)
+	empty := [][]int{{0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}}
+	if n := s.CountCompletions(empty); n.Int64() != 288 {
+		t.Errorf("Empty 4x4 grid has %v completions (wants %v)", n, 288)
+	}
+	// the bands swapping the digits of the blocks have 4 completions, the others 2
+	for band, want := range map[[4]int]int64{{3, 4, 1, 2}: 4, {3, 4, 2, 1}: 2} {
+		top := [][]int{{1, 2, 3, 4}, band[:], {0, 0, 0, 0}, {0, 0, 0, 0}}
+		if n := s.CountCompletions(top); n.Int64() != want {
+			t.Errorf("Top band 1234 %v has %v completions (wants %v)", band, n, want)
+		}
+	}
+	if n := s.CountCompletions([][]int{{1, 1}}); n.Sign() != 0 {
+		t.Errorf("Contradicting givens have %v completions", n)
+	}
+	if cols := s.matrix.ColCount(); cols != 64 {
+		t.Errorf("Matrix has %v columns after counting (wants %v)", cols, 64)
+	}
+	s = NewSudokuSolver(9)
+	p := "1....7.9..3..2...8..96..5....53..9...1..8...26....4...3......1..4......7..7...3.."
+	// removing givens from the top band of a sudoku having a unique solution
+	partial := parseGrid("........." + p[9:])
+	want := s.CountCompletions(partial)
+	if want.Int64() <= 1 {
+		t.Fatalf("Partial grid has %v completions", want)
+	}
+	rows := s.givens(partial)
+	O := s.matrix.NewSolution()
+	k, _ := s.matrix.Require(O, rows...)
+	c := &counter{matrix: s.matrix, count: new(big.Int)}
+	s.matrix.Search(O, k, c)
+	s.matrix.Release(O, k)
+	if c.count.Cmp(want) != 0 {
+		t.Errorf("Memoized count is %v (wants %v)", want, c.count)
+	}
+}
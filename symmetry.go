@@ -0,0 +1,114 @@
+package cover
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Keeps the first solution of each class of solutions related by the
+// symmetries. Each symmetry maps a solution to its image, which has to be a
+// solution too. The images are composed, so the generators of the symmetry
+// group are enough.
+func DeduplicateSolutions(sols []Solution, syms ...func(Solution) Solution) []Solution {
+	seen := map[string]bool{}
+	unique := make([]Solution, 0)
+	for _, sol := range sols {
+		if seen[sol.Key()] {
+			continue
+		}
+		unique = append(unique, sol)
+		// mark the whole orbit of the solution
+		orbit := []Solution{sol}
+		seen[sol.Key()] = true
+		for len(orbit) > 0 {
+			s := orbit[len(orbit)-1]
+			orbit = orbit[:len(orbit)-1]
+			for _, sym := range syms {
+				image := sym(s)
+				if key := image.Key(); !seen[key] {
+					seen[key] = true
+					orbit = append(orbit, image)
+				}
+			}
+		}
+	}
+	return unique
+}
+
+// Maps a cell of a board of the given size to its image.
+type BoardTransform func(x, y, height, width int) (int, int)
+
+// Transformations of boards. Rotate90 and Transpose only apply to square boards.
+var (
+	Rotate90 BoardTransform = func(x, y, h, w int) (int, int) {
+		return y, h - 1 - x
+	}
+	Rotate180 BoardTransform = func(x, y, h, w int) (int, int) {
+		return h - 1 - x, w - 1 - y
+	}
+	MirrorRows BoardTransform = func(x, y, h, w int) (int, int) {
+		return h - 1 - x, y
+	}
+	MirrorCols BoardTransform = func(x, y, h, w int) (int, int) {
+		return x, w - 1 - y
+	}
+	Transpose BoardTransform = func(x, y, h, w int) (int, int) {
+		return y, x
+	}
+)
+
+// Returns the transformations generating the symmetry group of a board.
+func BoardSymmetries(height, width int) []BoardTransform {
+	if height == width {
+		return []BoardTransform{Rotate90, MirrorCols}
+	}
+	return []BoardTransform{Rotate180, MirrorCols}
+}
+
+// Returns the names of the columns of the row, sorted.
+func rowKey(r *Node) string {
+	names := make([]string, 0)
+	r.ForEachInRow(func(n *Node) {
+		names = append(names, n.Col.Name)
+	})
+	sort.Strings(names)
+	return strings.Join(names, "\x00")
+}
+
+// Builds a symmetry for placement problems on a board, where cells are columns
+// named "x,y" like in the sudoku and polyomino builders. A row maps to the row
+// having the transformed cells and the same other columns. The matrix must be
+// fully restored, since rows are looked up in all the columns.
+// Panics if a row has no image.
+func (m *SparseMatrix) BoardSymmetry(height, width int, t BoardTransform) func(Solution) Solution {
+	rows := map[string]*Node{}
+	root := m.Root()
+	for col := root.Right; col != root; col = col.Right {
+		col.ForEachInCol(func(r *Node) {
+			rows[rowKey(r)] = r
+		})
+	}
+	return func(s Solution) Solution {
+		image := make(Solution, 0, len(s))
+		for _, r := range s {
+			names := make([]string, 0)
+			r.ForEachInRow(func(n *Node) {
+				var x, y int
+				if _, err := fmt.Sscanf(n.Col.Name, "%d,%d", &x, &y); err == nil {
+					x, y = t(x, y, height, width)
+					names = append(names, fmt.Sprintf("%v,%v", x, y))
+				} else {
+					names = append(names, n.Col.Name)
+				}
+			})
+			sort.Strings(names)
+			i, ok := rows[strings.Join(names, "\x00")]
+			if !ok {
+				panic(fmt.Sprintf("Row %v has no image", r.Row))
+			}
+			image = append(image, i)
+		}
+		return image
+	}
+}
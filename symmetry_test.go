@@ -0,0 +1,45 @@
+package cover
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Builds the domino tilings of a board, with cells named "x,y".
+func dominoes(height, width int) *Solver {
+	headers := make([]string, 0)
+	for i := 0; i < height*width; i++ {
+		headers = append(headers, fmt.Sprintf("%v,%v", i/width, i%width))
+	}
+	rows := make([][]int, 0)
+	for i := 0; i < height*width; i++ {
+		if i%width < width-1 {
+			rows = append(rows, []int{i, i + 1})
+		}
+		if i/width < height-1 {
+			rows = append(rows, []int{i, i + width})
+		}
+	}
+	return &Solver{matrix: NewSparseMatrixFromRows(rows, headers)}
+}
+
+func TestDeduplicateSolutions(t *testing.T) {
+	for _, c := range []struct{ h, w, all, unique int }{{2, 3, 3, 2}, {4, 4, 36, 9}} {
+		s := dominoes(c.h, c.w)
+		all := s.SolveAll()
+		sols := make([]Solution, len(all))
+		for i, sol := range all {
+			sols[i] = *sol
+		}
+		syms := make([]func(Solution) Solution, 0)
+		for _, t := range BoardSymmetries(c.h, c.w) {
+			syms = append(syms, s.matrix.BoardSymmetry(c.h, c.w, t))
+		}
+		if len(sols) != c.all {
+			t.Errorf("%vx%v board has %v tilings (wants %v)", c.h, c.w, len(sols), c.all)
+		}
+		if unique := DeduplicateSolutions(sols, syms...); len(unique) != c.unique {
+			t.Errorf("%vx%v board has %v distinct tilings (wants %v)", c.h, c.w, len(unique), c.unique)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package cover
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Size from which a trace writes its buffered lines out.
+const traceBlock = 32 << 10
+
+// Serializes the blocks written by all the traces, so that solvers tracing
+// concurrently to a shared writer never interleave within a line.
+var traceMu sync.Mutex
+
+// Buffered sink of the diagnostics of a matrix: the covers and uncovers of
+// its searches, the solutions found and a summary at the end of each search.
+// Every line starts with the prefix, which tells apart the traces of
+// concurrent solvers, and lines are written out in blocks of whole lines.
+type Trace struct {
+	w      io.Writer
+	prefix string
+	buf    []byte
+	// first write error, after which the trace is discarded
+	err error
+}
+
+// Starts tracing the following searches to w, replacing any previous trace,
+// which is flushed. The trace is flushed at the end of every search.
+func (m *SparseMatrix) Trace(w io.Writer, prefix string) *Trace {
+	if m.trace != nil {
+		m.trace.Flush()
+	} else {
+		m.AddHook(func(e Event, col *Node, depth int) {
+			m.trace.Printf("%v %v at depth %v", e, col.Name, depth)
+		})
+	}
+	m.trace = &Trace{w: w, prefix: prefix}
+	return m.trace
+}
+
+// Buffers a line, e.g. a diagnostic of a guesser, writing out the buffered
+// lines once they fill a block. A nil trace discards it.
+func (t *Trace) Printf(format string, a ...interface{}) {
+	if t == nil || t.err != nil {
+		return
+	}
+	t.buf = append(t.buf, t.prefix...)
+	t.buf = fmt.Appendf(t.buf, format, a...)
+	t.buf = append(t.buf, '\n')
+	if len(t.buf) >= traceBlock {
+		t.Flush()
+	}
+}
+
+// Writes out the buffered lines in a single write and returns the first
+// write error of the trace.
+func (t *Trace) Flush() error {
+	if t == nil {
+		return nil
+	}
+	if t.err == nil && len(t.buf) > 0 {
+		traceMu.Lock()
+		_, t.err = t.w.Write(t.buf)
+		traceMu.Unlock()
+	}
+	t.buf = t.buf[:0]
+	return t.err
+}
+
+// Records the end of a search with its statistics, and flushes the trace.
+func (m *SparseMatrix) traceEnd() {
+	if m.trace == nil {
+		return
+	}
+	m.trace.Printf("done with %v solutions, %v candidates, aborted %v", m.stats.Solutions, m.stats.Candidates(), m.aborted)
+	m.trace.Flush()
+}
@@ -0,0 +1,54 @@
+package cover
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestTraceConcurrent(t *testing.T) {
+	var out bytes.Buffer
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			matrix, headers, _ := GeneratePlantedCover(16, 40, 0.2, int64(i))
+			NewSolver(matrix, headers, WithTrace(&out, fmt.Sprintf("s%v: ", i))).SolveAll()
+		}(i)
+	}
+	wg.Wait()
+	lines := map[string][]string{}
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+		prefix, rest, ok := strings.Cut(line, ": ")
+		if !ok || len(prefix) != 2 || prefix[0] != 's' {
+			t.Fatalf("Line %q has no solver prefix", line)
+		}
+		lines[prefix] = append(lines[prefix], rest)
+	}
+	if len(lines) != 8 {
+		t.Errorf("Trace has lines of %v solvers (wants 8)", len(lines))
+	}
+	for prefix, l := range lines {
+		if !strings.HasPrefix(l[0], "cover ") {
+			t.Errorf("Trace of %v starts with %q", prefix, l[0])
+		}
+		if last := l[len(l)-1]; !strings.HasPrefix(last, "done with ") {
+			t.Errorf("Trace of %v ends with %q", prefix, last)
+		}
+	}
+}
+
+func TestTraceSolutions(t *testing.T) {
+	var out bytes.Buffer
+	s := NewSolver(knuth(), []string{"A", "B", "C", "D", "E", "F", "G"}, WithTrace(&out, ""))
+	s.Solve()
+	if !strings.Contains(out.String(), "solution [0 3 4]\n") {
+		t.Errorf("Trace misses the solution:\n%v", out.String())
+	}
+	if !strings.HasSuffix(out.String(), "done with 1 solutions, 5 candidates, aborted false\n") {
+		t.Errorf("Trace ends with %q", out.String()[strings.LastIndex(strings.TrimSuffix(out.String(), "\n"), "\n")+1:])
+	}
+}
@@ -0,0 +1,149 @@
+package cover
+
+import (
+	"math"
+	"math/rand"
+)
+
+// Sudoku transformations, the symmetries of the sudoku grid: each one maps a
+// valid grid or puzzle to another one, with as many solutions. Generators
+// use them to diversify from a seed grid cheaply. They return a new grid and
+// leave the given one untouched.
+
+// Returns a copy of the grid.
+func copyGrid(grid [][]int) [][]int {
+	c := make([][]int, len(grid))
+	for i, line := range grid {
+		c[i] = append([]int{}, line...)
+	}
+	return c
+}
+
+// Moves each cell of the square grid to its image by the board transform.
+func TransformGrid(grid [][]int, t BoardTransform) [][]int {
+	dim := len(grid)
+	image := make([][]int, dim)
+	for i := range image {
+		image[i] = make([]int, dim)
+	}
+	for x, line := range grid {
+		for y, cell := range line {
+			i, j := t(x, y, dim, dim)
+			image[i][j] = cell
+		}
+	}
+	return image
+}
+
+// Rotates the grid by a quarter turn clockwise.
+func RotateGrid(grid [][]int) [][]int {
+	return TransformGrid(grid, Rotate90)
+}
+
+// Swaps the rows and the columns of the grid.
+func TransposeGrid(grid [][]int) [][]int {
+	return TransformGrid(grid, Transpose)
+}
+
+// Renames the digits, digit d becoming perm[d-1]. Empty cells stay empty.
+func Relabel(grid [][]int, perm []int) [][]int {
+	image := copyGrid(grid)
+	for _, line := range image {
+		for j, cell := range line {
+			if cell > 0 {
+				line[j] = perm[cell-1]
+			}
+		}
+	}
+	return image
+}
+
+// Swaps two rows of the grid, which must belong to the same band.
+func SwapRows(grid [][]int, i, j int) [][]int {
+	if sdim := int(math.Sqrt(float64(len(grid)))); i/sdim != j/sdim {
+		panic("rows of different bands")
+	}
+	image := copyGrid(grid)
+	image[i], image[j] = image[j], image[i]
+	return image
+}
+
+// Swaps two columns of the grid, which must belong to the same stack.
+func SwapCols(grid [][]int, i, j int) [][]int {
+	return TransposeGrid(SwapRows(TransposeGrid(grid), i, j))
+}
+
+// Swaps two bands, the groups of rows sharing blocks, given by index.
+func SwapBands(grid [][]int, i, j int) [][]int {
+	sdim := int(math.Sqrt(float64(len(grid))))
+	image := copyGrid(grid)
+	for k := 0; k < sdim; k++ {
+		image[i*sdim+k], image[j*sdim+k] = image[j*sdim+k], image[i*sdim+k]
+	}
+	return image
+}
+
+// Swaps two stacks, the groups of columns sharing blocks, given by index.
+func SwapStacks(grid [][]int, i, j int) [][]int {
+	return TransposeGrid(SwapBands(TransposeGrid(grid), i, j))
+}
+
+// Applies a random element of the symmetry group of the grid: a relabeling,
+// permutations of the bands, of the stacks, of the rows within each band and
+// of the columns within each stack, and a transposition half of the time.
+func Scramble(grid [][]int, rnd *rand.Rand) [][]int {
+	dim := len(grid)
+	sdim := int(math.Sqrt(float64(dim)))
+	perm := rnd.Perm(dim)
+	for i := range perm {
+		perm[i]++
+	}
+	image := Relabel(grid, perm)
+	rnd.Shuffle(sdim, func(i, j int) {
+		image = SwapBands(image, i, j)
+	})
+	rnd.Shuffle(sdim, func(i, j int) {
+		image = SwapStacks(image, i, j)
+	})
+	for b := 0; b < sdim; b++ {
+		rnd.Shuffle(sdim, func(i, j int) {
+			image = SwapRows(image, b*sdim+i, b*sdim+j)
+		})
+		rnd.Shuffle(sdim, func(i, j int) {
+			image = SwapCols(image, b*sdim+i, b*sdim+j)
+		})
+	}
+	if rnd.Intn(2) == 1 {
+		image = TransposeGrid(image)
+	}
+	return image
+}
+
+// Returns a canonical form of the grid or puzzle, in the Line format, equal
+// for the grids related by a rotation, a mirror image or a relabeling: the
+// smallest image by those symmetries, digits being relabeled in order of
+// appearance. Permuting rows or bands, which the full symmetry group allows,
+// usually changes it.
+func CanonicalGrid(grid [][]int) string {
+	best := ""
+	image := grid
+	for turn := 0; turn < 4; turn++ {
+		for _, g := range [][][]int{image, TransformGrid(image, MirrorCols)} {
+			perm := make([]int, len(g))
+			next := 1
+			for _, line := range g {
+				for _, cell := range line {
+					if cell > 0 && perm[cell-1] == 0 {
+						perm[cell-1] = next
+						next++
+					}
+				}
+			}
+			if s := FormatGrid(Relabel(g, perm), nil, Line); best == "" || s < best {
+				best = s
+			}
+		}
+		image = RotateGrid(image)
+	}
+	return best
+}
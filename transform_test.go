@@ -0,0 +1,57 @@
+package cover
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTransformGrid(t *testing.T) {
+	grid := [][]int{{1, 2, 3, 4}, {3, 4, 1, 2}, {2, 1, 4, 3}, {4, 3, 2, 1}}
+	if r := RotateGrid(grid); !reflect.DeepEqual(r[0], []int{4, 2, 3, 1}) {
+		t.Errorf("Rotated grid starts with %v (wants [4 2 3 1])", r[0])
+	}
+	if r := RotateGrid(RotateGrid(RotateGrid(RotateGrid(grid)))); !reflect.DeepEqual(r, grid) {
+		t.Errorf("Four rotations give %v", r)
+	}
+	if r := TransposeGrid(grid); r[1][0] != 2 || r[0][1] != 3 {
+		t.Errorf("Transposed grid is %v", r)
+	}
+	if r := Relabel(grid, []int{2, 1, 4, 3}); !reflect.DeepEqual(r[0], []int{2, 1, 4, 3}) || grid[0][0] != 1 {
+		t.Errorf("Relabeled grid starts with %v (wants [2 1 4 3])", r[0])
+	}
+	if r := SwapBands(grid, 0, 1); !reflect.DeepEqual(r[0], grid[2]) || !reflect.DeepEqual(r[3], grid[1]) {
+		t.Errorf("Bands swapped give %v", r)
+	}
+	if r := SwapStacks(grid, 0, 1); !reflect.DeepEqual(r[0], []int{3, 4, 1, 2}) {
+		t.Errorf("Stacks swapped start with %v (wants [3 4 1 2])", r[0])
+	}
+	s := NewSudokuSolver(9)
+	given := parseGrid("1....7.9..3..2...8..96..5....53..9...1..8...26....4...3......1..4......7..7...3..")
+	solution := s.Grid(s.Solve(given))
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 5; i++ {
+		scrambled := Scramble(solution, rnd)
+		checkGrid(t, parseGrid(strings.Repeat(".", 81)), scrambled)
+		puzzle := Scramble(given, rand.New(rand.NewSource(int64(i))))
+		if O := s.Solve(puzzle); O.Len() != 81 {
+			t.Errorf("Scrambled puzzle has no solution")
+		}
+	}
+}
+
+func TestCanonicalGrid(t *testing.T) {
+	given := parseGrid("1....7.9..3..2...8..96..5....53..9...1..8...26....4...3......1..4......7..7...3..")
+	want := CanonicalGrid(given)
+	for _, g := range [][][]int{
+		RotateGrid(given),
+		TransposeGrid(given),
+		Relabel(given, []int{9, 8, 7, 6, 5, 4, 3, 2, 1}),
+		Relabel(TransformGrid(given, MirrorRows), []int{2, 3, 4, 5, 6, 7, 8, 9, 1}),
+	} {
+		if c := CanonicalGrid(g); c != want {
+			t.Errorf("Canonical form is %v (wants %v)", c, want)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+package cover
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Column a sudoku variant adds to the matrix, covered by the candidates of
+// the cells, i.e. by the rows placing their digits. Its name must not start
+// with a digit, like the names of the sudoku columns.
+type VariantColumn struct {
+	Name string
+	// covered at most once instead of exactly once
+	Secondary bool
+	Cells     []SudokuCell
+}
+
+// Row a sudoku variant adds to the matrix, covering only columns of the
+// variant, e.g. to let one of its primary columns be covered without a digit.
+type VariantRow struct {
+	Name    string
+	Columns []string
+}
+
+// Rules a sudoku variant adds to the classic ones, by extending the matrix
+// rather than the builder, so that new variants plug in from other packages.
+// The methods receive the dimension of the sudoku.
+type Variant interface {
+	// Returns the columns to add, e.g. the digits of a diagonal.
+	ExtraColumns(dim int) []VariantColumn
+	// Tells whether a candidate stays in the matrix, e.g. an odd digit in a
+	// cell which must hold an even one does not.
+	FilterRow(c SudokuCell) bool
+	// Returns the rows to add, which decode into themselves.
+	ExtraRows(dim int) []VariantRow
+}
+
+// Variant checking its encoding before the matrix is built, like Stack.
+type variantChecker interface {
+	Check(dim int) error
+}
+
+// Builds the solver of a sudoku of the dimension following the rules of the
+// variant too, like NewSudokuSolver(). Returns an error if the variant adds a
+// column twice, a column covered by a cell out of the grid, or a row covering
+// an unknown column, or the error of its Check() method if it has one.
+func NewVariantSolver(dim int, v Variant, opts ...Option) (*SudokuSolver, error) {
+	if c, ok := v.(variantChecker); ok {
+		if err := c.Check(dim); err != nil {
+			return nil, err
+		}
+	}
+	base, headers := SudokuConstraintRows(dim)
+	bdim := dim * dim
+	// index of the row of each remaining candidate
+	index := map[SudokuCell]int{}
+	rows := make([][]int, 0, len(base))
+	for i, r := range base {
+		c := SudokuCell{X: i / bdim, Y: i / dim % dim, Digit: i%dim + 1}
+		if v.FilterRow(c) {
+			index[c] = len(rows)
+			// appending a variant column copies the row out of the
+			// backing array it shares with the others
+			rows = append(rows, r[:len(r):len(r)])
+		}
+	}
+	cols := map[string]int{}
+	for j, name := range headers {
+		cols[name] = j
+	}
+	secondary := make([]string, 0)
+	for _, col := range v.ExtraColumns(dim) {
+		if _, ok := cols[col.Name]; ok {
+			return nil, fmt.Errorf("variant column %v added twice", col.Name)
+		}
+		j := len(headers)
+		cols[col.Name] = j
+		headers = append(headers, col.Name)
+		if col.Secondary {
+			secondary = append(secondary, col.Name)
+		}
+		for _, c := range col.Cells {
+			if c.X < 0 || c.X >= dim || c.Y < 0 || c.Y >= dim || c.Digit < 1 || c.Digit > dim {
+				return nil, fmt.Errorf("variant column %v: cell %v out of the grid", col.Name, c)
+			}
+			if i, ok := index[c]; ok {
+				rows[i] = append(rows[i], j)
+			}
+		}
+	}
+	extras := v.ExtraRows(dim)
+	for _, row := range extras {
+		r := make([]int, len(row.Columns))
+		for k, name := range row.Columns {
+			j, ok := cols[name]
+			if !ok || j < 4*bdim {
+				return nil, fmt.Errorf("variant row %v: %w %v", row.Name, ErrNoColumn, name)
+			}
+			r[k] = j
+		}
+		rows = append(rows, r)
+	}
+	candidates := len(index)
+	decoder := DecoderFunc(func(row *Node) interface{} {
+		if row.Row >= candidates {
+			return extras[row.Row-candidates]
+		}
+		return SudokuDecoder(row)
+	})
+	s := SudokuSolver{Solver: &Solver{matrix: NewSparseMatrixFromRows(rows, headers), decoder: decoder}, Dim: dim}
+	if err := s.matrix.SetSecondary(secondary...); err != nil {
+		return nil, err
+	}
+	s.matrix.TrackSizes()
+	s.matrix.PropagateUnits()
+	for _, opt := range opts {
+		opt(s.Solver)
+	}
+	return &s, nil
+}
+
+// X sudoku: both diagonals hold every digit.
+type XVariant struct{}
+
+func (XVariant) ExtraColumns(dim int) []VariantColumn {
+	cols := make([]VariantColumn, 0, 2*dim)
+	for d := 1; d <= dim; d++ {
+		main := VariantColumn{Name: fmt.Sprintf("x0=%v", d)}
+		anti := VariantColumn{Name: fmt.Sprintf("x1=%v", d)}
+		for i := 0; i < dim; i++ {
+			main.Cells = append(main.Cells, SudokuCell{i, i, d})
+			anti.Cells = append(anti.Cells, SudokuCell{i, dim - 1 - i, d})
+		}
+		cols = append(cols, main, anti)
+	}
+	return cols
+}
+
+func (XVariant) FilterRow(SudokuCell) bool {
+	return true
+}
+
+func (XVariant) ExtraRows(int) []VariantRow {
+	return nil
+}
+
+// Sudoku whose main diagonal, or anti-diagonal, holds every digit, like one
+// half of an X sudoku whose columns it shares.
+type DiagonalVariant struct {
+	Anti bool
+}
+
+func (v DiagonalVariant) ExtraColumns(dim int) []VariantColumn {
+	cols := XVariant{}.ExtraColumns(dim)
+	diagonal := make([]VariantColumn, 0, dim)
+	for i := 0; i < len(cols); i += 2 {
+		if v.Anti {
+			diagonal = append(diagonal, cols[i+1])
+		} else {
+			diagonal = append(diagonal, cols[i])
+		}
+	}
+	return diagonal
+}
+
+func (DiagonalVariant) FilterRow(SudokuCell) bool {
+	return true
+}
+
+func (DiagonalVariant) ExtraRows(int) []VariantRow {
+	return nil
+}
+
+// Hyper sudoku, or windoku: the windows of the size of a box, spaced by one
+// cell from the border and from each other, hold every digit, like the 4
+// extra boxes of a 9x9 grid.
+type HyperVariant struct{}
+
+func (HyperVariant) ExtraColumns(dim int) []VariantColumn {
+	sdim := int(math.Sqrt(float64(dim)))
+	cols := make([]VariantColumn, 0)
+	for x := 1; x+sdim < dim; x += sdim + 1 {
+		for y := 1; y+sdim < dim; y += sdim + 1 {
+			for d := 1; d <= dim; d++ {
+				col := VariantColumn{Name: fmt.Sprintf("h%v,%v=%v", x, y, d)}
+				for i := x; i < x+sdim; i++ {
+					for j := y; j < y+sdim; j++ {
+						col.Cells = append(col.Cells, SudokuCell{i, j, d})
+					}
+				}
+				cols = append(cols, col)
+			}
+		}
+	}
+	return cols
+}
+
+func (HyperVariant) FilterRow(SudokuCell) bool {
+	return true
+}
+
+func (HyperVariant) ExtraRows(int) []VariantRow {
+	return nil
+}
+
+// Anti-knight sudoku: cells a knight's move apart hold different digits. A
+// secondary column per pair of such cells and digit forbids the same digit
+// in both.
+type AntiKnightVariant struct{}
+
+func (AntiKnightVariant) ExtraColumns(dim int) []VariantColumn {
+	cols := make([]VariantColumn, 0)
+	for x := 0; x < dim; x++ {
+		for y := 0; y < dim; y++ {
+			// moves forward only, each pair once
+			for _, m := range [][2]int{{1, -2}, {1, 2}, {2, -1}, {2, 1}} {
+				i, j := x+m[0], y+m[1]
+				if i >= dim || j < 0 || j >= dim {
+					continue
+				}
+				for d := 1; d <= dim; d++ {
+					cols = append(cols, VariantColumn{
+						Name:      fmt.Sprintf("n%v,%v/%v,%v=%v", x, y, i, j, d),
+						Secondary: true,
+						Cells:     []SudokuCell{{x, y, d}, {i, j, d}},
+					})
+				}
+			}
+		}
+	}
+	return cols
+}
+
+func (AntiKnightVariant) FilterRow(SudokuCell) bool {
+	return true
+}
+
+func (AntiKnightVariant) ExtraRows(int) []VariantRow {
+	return nil
+}
+
+// Odd-even sudoku: the shaded cells hold odd digits, or even ones.
+type OddEvenVariant struct {
+	// 1 for a cell holding an odd digit, 2 for an even one, 0 for any
+	Parity [][]int
+}
+
+func (OddEvenVariant) ExtraColumns(int) []VariantColumn {
+	return nil
+}
+
+func (v OddEvenVariant) FilterRow(c SudokuCell) bool {
+	if c.X >= len(v.Parity) || c.Y >= len(v.Parity[c.X]) {
+		return true
+	}
+	switch v.Parity[c.X][c.Y] {
+	case 1:
+		return c.Digit%2 == 1
+	case 2:
+		return c.Digit%2 == 0
+	}
+	return true
+}
+
+func (OddEvenVariant) ExtraRows(int) []VariantRow {
+	return nil
+}
+
+// Error returned when the variants of a stack contradict each other.
+var ErrVariantConflict = errors.New("variant conflict")
+
+// Variants applied together, e.g. X and anti-knight: the columns and rows of
+// all of them are added, and a candidate stays if they all keep it.
+type Stack []Variant
+
+func (s Stack) ExtraColumns(dim int) []VariantColumn {
+	cols := make([]VariantColumn, 0)
+	for _, v := range s {
+		cols = append(cols, v.ExtraColumns(dim)...)
+	}
+	return cols
+}
+
+func (s Stack) FilterRow(c SudokuCell) bool {
+	for _, v := range s {
+		if !v.FilterRow(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s Stack) ExtraRows(dim int) []VariantRow {
+	rows := make([]VariantRow, 0)
+	for _, v := range s {
+		rows = append(rows, v.ExtraRows(dim)...)
+	}
+	return rows
+}
+
+// Finds the encodings of the variants which contradict each other before
+// building the matrix: a column or row two of them add, a cell whose digits
+// they all filter out together, or a primary column whose candidates another
+// variant filters out, unless a row of the variant covers it. Returns the
+// first one found, wrapping ErrVariantConflict.
+func (s Stack) Check(dim int) error {
+	owner := map[string]int{}
+	rows := map[string]int{}
+	covered := map[string]bool{}
+	for i, v := range s {
+		for _, col := range v.ExtraColumns(dim) {
+			if j, ok := owner[col.Name]; ok {
+				return fmt.Errorf("%w: %T and %T add column %v", ErrVariantConflict, s[j], v, col.Name)
+			}
+			owner[col.Name] = i
+		}
+		for _, row := range v.ExtraRows(dim) {
+			if j, ok := rows[row.Name]; ok {
+				return fmt.Errorf("%w: %T and %T add row %v", ErrVariantConflict, s[j], v, row.Name)
+			}
+			rows[row.Name] = i
+			for _, name := range row.Columns {
+				covered[name] = true
+			}
+		}
+	}
+	// variant filtering out a candidate, -1 if none does
+	filtered := func(c SudokuCell) int {
+		for i, v := range s {
+			if !v.FilterRow(c) {
+				return i
+			}
+		}
+		return -1
+	}
+	for x := 0; x < dim; x++ {
+		for y := 0; y < dim; y++ {
+			left := 0
+			for d := 1; d <= dim; d++ {
+				if filtered(SudokuCell{x, y, d}) < 0 {
+					left++
+				}
+			}
+			if left == 0 {
+				return fmt.Errorf("%w: no digit left in cell %v,%v", ErrVariantConflict, x, y)
+			}
+		}
+	}
+	for i, v := range s {
+		for _, col := range v.ExtraColumns(dim) {
+			if col.Secondary || covered[col.Name] {
+				continue
+			}
+			by := -1
+			for _, c := range col.Cells {
+				if by = filtered(c); by < 0 {
+					break
+				}
+			}
+			if by >= 0 {
+				return fmt.Errorf("%w: %T filters out the candidates of column %v of %T", ErrVariantConflict, s[by], col.Name, s[i])
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package cover
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Variant whose top left cell holds an even digit, adding a column only its
+// row covers.
+type evenCorner struct{}
+
+func (evenCorner) ExtraColumns(int) []VariantColumn {
+	return []VariantColumn{{Name: "free"}}
+}
+
+func (evenCorner) FilterRow(c SudokuCell) bool {
+	return c.X != 0 || c.Y != 0 || c.Digit%2 == 0
+}
+
+func (evenCorner) ExtraRows(int) []VariantRow {
+	return []VariantRow{{Name: "slack", Columns: []string{"free"}}}
+}
+
+func TestVariants(t *testing.T) {
+	empty := make([][]int, 9)
+	for i := range empty {
+		empty[i] = make([]int, 9)
+	}
+	for _, v := range []Variant{XVariant{}, HyperVariant{}, AntiKnightVariant{}, evenCorner{}} {
+		s, err := NewVariantSolver(9, v)
+		if err != nil {
+			t.Fatalf("%T: %v", v, err)
+		}
+		grid := s.Grid(s.Solve(empty))
+		checkGrid(t, empty, grid)
+		for _, col := range v.ExtraColumns(9) {
+			digits := 0
+			for _, c := range col.Cells {
+				if grid[c.X][c.Y] == c.Digit {
+					digits++
+				}
+			}
+			if digits > 1 || digits == 0 && !col.Secondary && col.Name != "free" {
+				t.Errorf("%T: column %v covered by %v digits", v, col.Name, digits)
+			}
+		}
+		if _, ok := v.(evenCorner); ok && grid[0][0]%2 != 0 {
+			t.Errorf("Odd top left corner %v", grid[0][0])
+		}
+	}
+}
+
+// Variant adding a column twice.
+type twice struct{ evenCorner }
+
+func (twice) ExtraColumns(int) []VariantColumn {
+	return []VariantColumn{{Name: "free"}, {Name: "free"}}
+}
+
+func TestVariantErrors(t *testing.T) {
+	if _, err := NewVariantSolver(4, twice{}); err == nil {
+		t.Error("Column added twice builds")
+	}
+	var cells variantFunc = func(int) []VariantColumn {
+		return []VariantColumn{{Name: "far", Cells: []SudokuCell{{4, 0, 1}}}}
+	}
+	if _, err := NewVariantSolver(4, cells); err == nil {
+		t.Error("Cell out of the grid builds")
+	}
+	if _, err := NewVariantSolver(4, rowsOf("1,1")); !errors.Is(err, ErrNoColumn) {
+		t.Errorf("Row covering a sudoku column: %v (wants %v)", err, ErrNoColumn)
+	}
+}
+
+// Variant adding the columns of a function.
+type variantFunc func(dim int) []VariantColumn
+
+func (f variantFunc) ExtraColumns(dim int) []VariantColumn { return f(dim) }
+func (variantFunc) FilterRow(SudokuCell) bool              { return true }
+func (variantFunc) ExtraRows(int) []VariantRow             { return nil }
+
+// Variant adding a row covering the columns.
+type rowsOf string
+
+func (rowsOf) ExtraColumns(int) []VariantColumn { return nil }
+func (rowsOf) FilterRow(SudokuCell) bool        { return true }
+func (r rowsOf) ExtraRows(int) []VariantRow {
+	return []VariantRow{{Name: fmt.Sprint("row ", r), Columns: []string{string(r)}}}
+}
+
+func TestSudokuDecoderVariant(t *testing.T) {
+	s, err := NewVariantSolver(4, AntiKnightVariant{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	O := s.Solve([][]int{{1, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}, {0, 0, 0, 0}})
+	if O.Len() != 16 {
+		t.Fatalf("Solution has %v cells (wants 16)", O.Len())
+	}
+	for _, obj := range s.Decode(O) {
+		c := obj.(SudokuCell)
+		if c.Digit < 1 || c.Digit > 4 {
+			t.Errorf("Decoded %v", c)
+		}
+	}
+}
+
+func TestStack(t *testing.T) {
+	parity := make([][]int, 9)
+	for i := range parity {
+		parity[i] = make([]int, 9)
+	}
+	parity[0][0], parity[4][4], parity[8][0] = 1, 2, 2
+	stack := Stack{HyperVariant{}, AntiKnightVariant{}, OddEvenVariant{parity}}
+	s, err := NewVariantSolver(9, stack)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the top band of a solution spares the long search of an empty grid
+	given := parseGrid("157436289869712453243859716" + strings.Repeat(".", 54))
+	grid := s.Grid(s.Solve(given))
+	checkGrid(t, given, grid)
+	for _, col := range stack.ExtraColumns(9) {
+		digits := 0
+		for _, c := range col.Cells {
+			if grid[c.X][c.Y] == c.Digit {
+				digits++
+			}
+		}
+		if digits > 1 || digits == 0 && !col.Secondary {
+			t.Errorf("Column %v covered by %v digits", col.Name, digits)
+		}
+	}
+	if grid[0][0]%2 != 1 || grid[4][4]%2 != 0 || grid[8][0]%2 != 0 {
+		t.Errorf("Parities not met: %v", grid)
+	}
+}
+
+func TestStackConflicts(t *testing.T) {
+	odd := make([][]int, 4)
+	for i := range odd {
+		odd[i] = []int{1, 1, 1, 1}
+	}
+	even := OddEvenVariant{[][]int{{2}}}
+	for _, stack := range []Stack{
+		{XVariant{}, XVariant{}},
+		{OddEvenVariant{odd}, even},
+		// the diagonals need the even digits of the odd cells
+		{XVariant{}, OddEvenVariant{odd}},
+	} {
+		if _, err := NewVariantSolver(4, stack); !errors.Is(err, ErrVariantConflict) {
+			t.Errorf("%T: %v (wants %v)", stack, err, ErrVariantConflict)
+		} else {
+			t.Log(err)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package cover
+
+import (
+	"math/big"
+	"sort"
+	"sync/atomic"
+)
+
+// Speculative state of the matrix of a solver, made of the rows forced so
+// far. Views share the matrix and apply their rows to it only for the time
+// of a query, so exploring "what if this row is forced" costs the covers of
+// a few rows rather than a clone of the matrix, which makes hint generators
+// and interactive tools cheap. A view derived by Force() shares the rows of
+// its parent, which are copied when another view is derived from it, so
+// sibling views never see each other's rows. Like the matrix, the views of a
+// solver are not safe for concurrent use.
+type View struct {
+	solver *Solver
+	rows   []*Node
+}
+
+// Returns a view forcing no row.
+func (s *Solver) View() *View {
+	return &View{solver: s}
+}
+
+// Returns a view forcing the rows, given by any of their nodes, on top of
+// the rows of v, which is left unchanged. Returns ErrConflict if a row
+// overlaps the rows already forced.
+func (v *View) Force(rows ...*Node) (*View, error) {
+	// the capacity makes any later append copy the shared rows
+	child := &View{solver: v.solver, rows: append(v.rows[:len(v.rows):len(v.rows)], rows...)}
+	m := v.solver.matrix
+	O := m.NewSolution()
+	k, err := m.Require(O, child.rows...)
+	if err != nil {
+		return nil, err
+	}
+	m.Release(O, k)
+	return child, nil
+}
+
+// Returns the sorted indexes of the rows forced by the view.
+func (v *View) Rows() []int {
+	rows := make([]int, len(v.rows))
+	for i, r := range v.rows {
+		rows[i] = r.Row
+	}
+	sort.Ints(rows)
+	return rows
+}
+
+// Applies the rows of the view to the matrix, calls f with the matrix, a
+// solution holding the rows and the level to search from, and restores the
+// matrix. f may search the matrix but must leave it as given.
+func (v *View) Do(f func(m *SparseMatrix, O *Solution, k int)) {
+	m := v.solver.matrix
+	O := m.NewSolution()
+	// the rows were checked by Force()
+	k, _ := m.Require(O, v.rows...)
+	f(m, O, k)
+	m.Release(O, k)
+}
+
+// Returns the first row holding all the named columns among the rows left
+// available by the view, nil if there is none.
+func (v *View) Row(names ...string) *Node {
+	var r *Node
+	v.Do(func(m *SparseMatrix, O *Solution, k int) {
+		r = m.Row(names...)
+	})
+	return r
+}
+
+// Returns a solution holding the rows of the view, empty if there is none.
+func (v *View) Solve() *Solution {
+	var O *Solution
+	v.Do(func(m *SparseMatrix, forced *Solution, k int) {
+		m.ResetStats()
+		r := &racer{matrix: m, stop: new(atomic.Bool)}
+		m.Search(forced, k, r)
+		O = r.solution
+	})
+	if O == nil {
+		O = v.solver.matrix.NewSolution()
+	}
+	return O
+}
+
+// Counts the solutions holding the rows of the view.
+func (v *View) Count() *big.Int {
+	count := new(big.Int)
+	v.Do(func(m *SparseMatrix, O *Solution, k int) {
+		m.ResetStats()
+		m.Search(O, k, &counter{matrix: m, count: count})
+	})
+	return count
+}
@@ -0,0 +1,67 @@
+package cover
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestView(t *testing.T) {
+	s := NewSolver(knuth(), []string{"A", "B", "C", "D", "E", "F", "G"})
+	base := s.View()
+	cef, err := base.Force(s.matrix.Row("C", "E", "F"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := s.matrix.rowNodes()
+	ad, err := cef.Force(rows[3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	adg, err := cef.Force(rows[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(ad.Rows(), adg.Rows(), cef.Rows()) != "[0 3] [0 1] [0]" {
+		t.Errorf("Views force %v, %v and %v", ad.Rows(), adg.Rows(), cef.Rows())
+	}
+	if O := ad.Solve(); fmt.Sprint(O.Rows()) != "[0 3 4]" {
+		t.Errorf("View solves with %v", O.Rows())
+	}
+	if n := adg.Count(); n.Sign() != 0 {
+		t.Errorf("Dead view has %v solutions", n)
+	}
+	if O := adg.Solve(); O.Len() != 0 {
+		t.Errorf("Dead view solves with %v", O.Rows())
+	}
+	if _, err := cef.Force(s.matrix.Row("B", "C", "F")); err != ErrConflict {
+		t.Errorf("Conflicting row is forced with error %v", err)
+	}
+	if r := cef.Row("B", "C", "F"); r != nil {
+		t.Errorf("View finds the covered row %v", r.Row)
+	}
+	if r := cef.Row("A", "D"); r == nil || r.Row != 1 {
+		t.Errorf("View finds %v for AD", r)
+	}
+	if err := s.matrix.Validate(); err != nil || s.matrix.ColCount() != 7 {
+		t.Errorf("Views leave %v columns: %v", s.matrix.ColCount(), err)
+	}
+
+	matrix, headers, _ := GeneratePlantedCover(16, 40, 0.2, 5)
+	s = NewSolver(matrix, headers)
+	holding := map[int]int64{}
+	for _, O := range s.SolveAll() {
+		for _, r := range O.Rows() {
+			holding[r]++
+		}
+	}
+	view := s.View()
+	for i := range matrix {
+		v, err := view.Force(s.matrix.rowNodes()[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n := v.Count(); n.Int64() != holding[i] {
+			t.Errorf("Forcing row %v gives %v solutions (wants %v)", i, n, holding[i])
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package cover
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Chosen row excluding another one, and the column they share.
+type Conflict struct {
+	By     *Node
+	Column string
+}
+
+// Why a row is or is not part of the solution of a session, see WhyNot().
+type Explanation struct {
+	Row int
+	// the row is part of the solution
+	Chosen bool
+	// the row is still in the matrix, so that it can be chosen
+	Available bool
+	// the chosen rows excluding the row, in the order they were chosen
+	Conflicts []Conflict
+}
+
+func (e Explanation) String() string {
+	switch {
+	case e.Chosen:
+		return fmt.Sprintf("row %v is chosen", e.Row)
+	case e.Available:
+		return fmt.Sprintf("row %v can be chosen", e.Row)
+	case len(e.Conflicts) == 0:
+		return fmt.Sprintf("row %v is not in the matrix", e.Row)
+	}
+	reasons := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		reasons[i] = fmt.Sprintf("row %v on column %v", c.By.Row, c.Column)
+	}
+	return fmt.Sprintf("row %v conflicts with %v", e.Row, strings.Join(reasons, ", "))
+}
+
+// Explains why a row, by index, cannot be chosen in the current solution of
+// the session, e.g. for an editing UI to tell why an employee cannot take a
+// shift: reports each chosen row covering one of its columns, or giving a
+// colored one another color. A row neither chosen, nor available, nor in
+// conflict was removed from the matrix otherwise, or does not exist.
+func (s *Session) WhyNot(rowID int) Explanation {
+	e := Explanation{Row: rowID}
+	// node committing each column, and the chosen row holding it
+	owner, chooser := map[*Node]*Node{}, map[*Node]*Node{}
+	for _, r := range s.O {
+		if r.Row == rowID {
+			e.Chosen = true
+			return e
+		}
+		r.ForEachInRow(func(j *Node) {
+			if _, ok := owner[j.Col]; !ok && j.Color >= 0 {
+				owner[j.Col] = j
+				chooser[j.Col] = r
+			}
+		})
+	}
+	// the columns committed keep the rows they removed in their lists
+	var node *Node
+	find := func(col *Node) {
+		for n := col.Down; n != col && node == nil; n = n.Down {
+			if n.Row == rowID {
+				node = n
+			}
+		}
+	}
+	for _, r := range s.O {
+		r.ForEachInRow(func(j *Node) { find(j.Col) })
+	}
+	s.matrix.forEachCol(find)
+	if node == nil {
+		return e
+	}
+	if isActive(node) {
+		e.Available = true
+		return e
+	}
+	node.ForEachInRow(func(k *Node) {
+		j, ok := owner[k.Col]
+		if ok && (j.Color == 0 || k.Color == 0 || k.Color > 0 && k.Color != j.Color) {
+			e.Conflicts = append(e.Conflicts, Conflict{By: chooser[k.Col], Column: k.Col.Name})
+		}
+	})
+	order := map[*Node]int{}
+	for i, r := range s.O {
+		order[r] = i
+	}
+	sort.SliceStable(e.Conflicts, func(a, b int) bool {
+		return order[e.Conflicts[a].By] < order[e.Conflicts[b].By]
+	})
+	return e
+}
@@ -0,0 +1,55 @@
+package cover
+
+import (
+	"sort"
+)
+
+// Buffers reused by the solves of one goroutine, so that a service answering
+// many requests with a pool of solvers makes no allocation in the steady
+// state. A workspace is not safe for concurrent use, but can be passed to
+// solvers of different problems in turn.
+type Workspace struct {
+	solution Solution
+	rows     []int
+	stats    Stats
+}
+
+// Creates an empty workspace, whose buffers grow to the largest solve.
+func NewWorkspace() *Workspace {
+	return &Workspace{}
+}
+
+// Same as Solve(), but returns the sorted row indexes of the solution, nil if
+// there is none, in a buffer of the workspace valid until its next use. The
+// matrix is restored afterwards, so the solver needs no Reset().
+func (s *Solver) SolveIn(w *Workspace) []int {
+	s.Reset()
+	m := s.matrix
+	m.ResetStats()
+	if n := m.ColCount(); cap(w.solution) < n {
+		w.solution = make(Solution, 0, n)
+	}
+	O := &w.solution
+	*O = (*O)[:0]
+	m.Search(O, 0, s)
+	w.rows = w.rows[:0]
+	if len(s.Solutions) == 0 {
+		return nil
+	}
+	for _, n := range *O {
+		w.rows = append(w.rows, n.Row)
+	}
+	sort.Ints(w.rows)
+	s.Reset()
+	return w.rows
+}
+
+// Same as Stats(), but copies the counters into a buffer of the workspace
+// valid until its next use.
+func (s *Solver) StatsIn(w *Workspace) *Stats {
+	m := s.matrix
+	w.stats.Levels = append(w.stats.Levels[:0], m.stats.Levels...)
+	w.stats.Solutions = m.stats.Solutions
+	w.stats.Problem, w.stats.Tags = m.name, m.tags
+	return &w.stats
+}
@@ -0,0 +1,36 @@
+package cover
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestWorkspace(t *testing.T) {
+	w := NewWorkspace()
+	solvers := make([]*Solver, 0)
+	for seed := int64(0); seed < 4; seed++ {
+		matrix, headers, planted := GeneratePlantedCover(20, 40, 0.15, seed)
+		s := NewSolver(matrix, headers)
+		rows := s.SolveIn(w)
+		if err := s.CheckRows(rows); err != nil {
+			t.Errorf("Seed %v: %v", seed, err)
+		}
+		if fmt.Sprint(rows) != fmt.Sprint(s.Solve().Rows()) {
+			t.Errorf("Seed %v: solves %v in the workspace, %v otherwise (planted %v)", seed, rows, s.Solve().Rows(), planted)
+		}
+		s.Reset()
+		solvers = append(solvers, s)
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		for _, s := range solvers {
+			s.SolveIn(w)
+			s.StatsIn(w)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Solves in a workspace allocate %v times", allocs)
+	}
+	if rows := NewSolver([][]int{{1, 0}}, []string{"A", "B"}).SolveIn(w); rows != nil {
+		t.Errorf("Unsolvable problem solves with %v", rows)
+	}
+}